@@ -0,0 +1,151 @@
+// Package claymore is a client for the remote management API of Claymore's Dual Miner, which PhoenixMiner also serves,
+// reporting the stats of the rig it runs on as strings packing values with semicolons
+package claymore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPort is the port the API listens on unless -mport sets another one
+	DefaultPort = "3333"
+	// DefaultTimeout is the per-call timeout a Client applies when WithTimeout is not given
+	DefaultTimeout = 5 * time.Second
+)
+
+// Dialer is an interface to abstract net.Dialer to support testing
+type Dialer interface {
+	DialContext(ctx context.Context, network string, address string) (conn net.Conn, err error)
+}
+
+// Client talks to the API of a single Claymore or PhoenixMiner instance, opening a connection for each call as the
+// miner closes it after every reply
+type Client struct {
+	address  string
+	password string
+	dialer   Dialer
+	timeout  time.Duration
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithPassword sets the password the miner was started with through -mpsw
+func WithPassword(password string) Option {
+	return func(c *Client) {
+		c.password = password
+	}
+}
+
+// WithDialer sets how connections to the API are opened
+func WithDialer(dialer Dialer) Option {
+	return func(c *Client) {
+		c.dialer = dialer
+	}
+}
+
+// WithTimeout sets the timeout applied to each call, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// NewClient returns a Client for the API listening on address, a host:port, adjusted by options
+func NewClient(address string, options ...Option) *Client {
+	c := &Client{
+		address: address,
+		dialer:  &net.Dialer{},
+		timeout: DefaultTimeout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Address returns the host:port of the API
+func (c *Client) Address() string {
+	return c.address
+}
+
+// GetStat1 calls miner_getstat1 and parses the rig wide stats and the hashrate, temperature and fan speed of each GPU
+func (c *Client) GetStat1(ctx context.Context) (stat Stat, err error) {
+	return c.getStat(ctx, "miner_getstat1")
+}
+
+// GetStat2 calls miner_getstat2 and parses what GetStat1 does along with the shares and PCI bus of each GPU
+func (c *Client) GetStat2(ctx context.Context) (stat Stat, err error) {
+	return c.getStat(ctx, "miner_getstat2")
+}
+
+func (c *Client) getStat(ctx context.Context, method string) (stat Stat, err error) {
+	var result []string
+	if err = c.call(ctx, method, &result); err != nil {
+		return
+	}
+	stat, err = ParseStat(result)
+	if err != nil {
+		log.Errorf("Client.getStat: ParseStat(result); returned err=%s\n", err.Error())
+		err = &APIError{Method: method, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	return
+}
+
+// call sends a single request for method within the per-call timeout and decodes its result in to output
+func (c *Client) call(ctx context.Context, method string, output interface{}) (err error) {
+	log.Debugf("Client.call(address=%s, method=%s, output interface{}) called\n", c.address, method)
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		log.Errorf("Client.call: c.dialer.DialContext(ctx, tcp, %s); returned err=%s\n", c.address, err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrUnreachable}
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	request, _ := json.Marshal(Request{ID: 0, JSONRPC: "2.0", Method: method, Password: c.password})
+	if _, err = conn.Write(append(request, '\n')); err != nil {
+		log.Errorf("Client.call: conn.Write(%s); returned err=%s\n", method, err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrUnreachable}
+	}
+	// Claymore closes the connection after its reply instead of ending it with a new line, PhoenixMiner does both
+	reply, err := bufio.NewReader(conn).ReadBytes('\n')
+	reply = bytes.TrimSpace(reply)
+	if err != nil && (err != io.EOF || len(reply) == 0) {
+		message := err.Error()
+		if err == io.EOF {
+			// Claymore hangs up without a word on a wrong password
+			message = "connection closed without a reply, check the password"
+		}
+		log.Errorf("Client.call: reader.ReadBytes(); returned err=%s\n", err.Error())
+		return &APIError{Method: method, Message: message, Err: ErrUnreachable}
+	}
+	response := new(Response)
+	if err = json.Unmarshal(reply, response); err != nil {
+		log.Errorf("Client.call: json.Unmarshal(reply, response); returned err=%s\n", err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	if err = checkResponse(method, response); err != nil {
+		log.Errorf("Client.call: checkResponse(%s, response); returned err=%s\n", method, err.Error())
+		return
+	}
+	if err = json.Unmarshal(response.Result, output); err != nil {
+		log.Errorf("Client.call: json.Unmarshal(response.Result, output); returned err=%s\n", err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	return
+}
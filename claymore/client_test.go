@@ -0,0 +1,124 @@
+package claymore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"mining-tools/rig"
+	"mining-tools/rig/rigtest"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+// reply answers every request with body
+func reply(body string) rigtest.Handler {
+	return func(request []byte) []byte {
+		return []byte(body)
+	}
+}
+
+func Test_callErrors(t *testing.T) {
+	closed := rigtest.NewServer(reply(""))
+	closed.Close()
+	tests := []struct {
+		name    string
+		address string
+		handler rigtest.Handler
+		wantErr error
+	}{
+		{
+			name:    "Success01",
+			handler: reply(stat2Claymore),
+			wantErr: nil,
+		},
+		{
+			name:    "Success02",
+			handler: reply(stat2Phoenix),
+			wantErr: nil,
+		},
+		{
+			name:    "Unreachable01",
+			address: closed.Addr,
+			wantErr: ErrUnreachable,
+		},
+		{
+			name:    "Unreachable02",
+			handler: func(request []byte) []byte { return nil },
+			wantErr: rig.ErrUnreachable,
+		},
+		{
+			name:    "Unauthorized01",
+			handler: reply(wrongPassword),
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "Malformed01",
+			handler: reply("garbage"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "Malformed02",
+			handler: reply(`{"id": 0, "result": ["9.3 - ETH", "21"], "error": null}`),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "RequestFailed01",
+			handler: reply(`{"id":0,"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"}}`),
+			wantErr: ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.address
+			if tt.handler != nil {
+				server := rigtest.NewOneShotServer(tt.handler)
+				defer server.Close()
+				address = server.Addr
+			}
+			_, err := NewClient(address).GetStat2(context.Background())
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetStat2() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.Method != "miner_getstat2") {
+				t.Errorf("Client.GetStat2() error = %#v, want *APIError for miner_getstat2", err)
+			}
+		})
+	}
+}
+
+func Test_methods(t *testing.T) {
+	server := rigtest.NewOneShotServer(func(request []byte) []byte {
+		var r Request
+		json.Unmarshal(request, &r)
+		switch {
+		case r.Password != "secret":
+			return []byte(wrongPassword)
+		case r.Method == "miner_getstat1":
+			return []byte(stat1Claymore)
+		case r.Method == "miner_getstat2":
+			return []byte(stat2Claymore)
+		}
+		return nil
+	})
+	defer server.Close()
+	c := NewClient(server.Addr, WithPassword("secret"))
+	ctx := context.Background()
+	stat1, err := c.GetStat1(ctx)
+	if err != nil || len(stat1.GPUs) != 4 || stat1.GPUs[3].PCIBus != -1 {
+		t.Errorf("Client.GetStat1() = %+v, %v, want 4 GPUs without their PCI bus", stat1, err)
+	}
+	stat2, err := c.GetStat2(ctx)
+	if err != nil || len(stat2.GPUs) != 4 || stat2.GPUs[3].PCIBus != 4 || stat2.GPUs[2].Accepted != 14 {
+		t.Errorf("Client.GetStat2() = %+v, %v, want 4 GPUs with their shares and PCI bus", stat2, err)
+	}
+	if _, err = NewClient(server.Addr).GetStat1(ctx); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Client.GetStat1() without password error = %v, want %v", err, ErrUnauthorized)
+	}
+}
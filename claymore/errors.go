@@ -0,0 +1,70 @@
+package claymore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"mining-tools/rig"
+)
+
+var (
+	// ErrUnreachable is returned when no connection could be made to the API or it closed before answering
+	ErrUnreachable = errors.New("unreachable")
+	// ErrUnauthorized is returned when the miner was started with a password and the Client sent none or a wrong one
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrMalformedResponse is returned when the response or one of its packed strings could not be decoded
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrRequestFailed is returned for any other error answered by the miner
+	ErrRequestFailed = errors.New("request failed")
+)
+
+// APIError describes a failed call to a Claymore or PhoenixMiner method, Err is one of the Err* sentinels above so
+// callers can branch with errors.Is
+type APIError struct {
+	Method  string
+	Message string
+	Err     error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("claymore %s: %s", e.Method, e.Err)
+	}
+	return fmt.Sprintf("claymore %s: %s: %s", e.Method, e.Err, e.Message)
+}
+
+// Unwrap returns the sentinel error describing the kind of failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets callers match the rig package sentinels, so code written against rig.Rig can branch on Claymore failures
+func (e *APIError) Is(target error) bool {
+	return target == rig.ErrUnreachable && e.Err == ErrUnreachable
+}
+
+// checkResponse turns the error member of a response in to an *APIError, or nil if the call succeeded. The error is
+// null on success and a string or a JSON-RPC error object otherwise, depending on the miner
+func checkResponse(method string, response *Response) (err error) {
+	if len(response.Error) == 0 || string(response.Error) == "null" {
+		if response.Result == nil {
+			return &APIError{Method: method, Message: "no result", Err: ErrMalformedResponse}
+		}
+		return nil
+	}
+	var message string
+	if json.Unmarshal(response.Error, &message) != nil {
+		var rpcErr struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(response.Error, &rpcErr)
+		message = rpcErr.Message
+	}
+	apiErr := &APIError{Method: method, Message: message, Err: ErrRequestFailed}
+	if strings.Contains(strings.ToLower(message), "password") {
+		apiErr.Err = ErrUnauthorized
+	}
+	return apiErr
+}
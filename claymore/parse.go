@@ -0,0 +1,123 @@
+package claymore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Positions of the packed strings in the result of miner_getstat1, miner_getstat2 appends the per-GPU ones
+const (
+	fieldVersion = iota
+	fieldUptime
+	fieldTotals
+	fieldHashrates
+	fieldDualTotals
+	fieldDualHashrates
+	fieldSensors
+	fieldPools
+	fieldCounters
+	fieldAccepted
+	fieldRejected
+	fieldInvalid
+	fieldDualAccepted
+	fieldDualRejected
+	fieldDualInvalid
+	fieldPCIBus
+	stat1Fields = fieldCounters + 1
+	stat2Fields = fieldPCIBus + 1
+)
+
+// ParseStat parses the result of miner_getstat1 or miner_getstat2, a list of strings packing values with semicolons
+// such as "182724;51;0". Hashrates are sent in kH/s and GPUs that are switched off are sent as "off"
+func ParseStat(result []string) (stat Stat, err error) {
+	if len(result) < stat1Fields {
+		return stat, fmt.Errorf("expected at least %d fields, got %d", stat1Fields, len(result))
+	}
+	stat.Version = result[fieldVersion]
+	minutes, err := packed(result, fieldUptime, 1)
+	if err != nil {
+		return
+	}
+	stat.Uptime = time.Duration(minutes[0]) * time.Minute
+	totals, err := packed(result, fieldTotals, 3)
+	if err != nil {
+		return
+	}
+	stat.Hashrate = totals[0] * 1000
+	stat.Accepted, stat.Rejected = int64(totals[1]), int64(totals[2])
+	counters, err := packed(result, fieldCounters, 2)
+	if err != nil {
+		return
+	}
+	stat.Invalid, stat.PoolSwitches = int64(counters[0]), int64(counters[1])
+	if result[fieldPools] != "" {
+		stat.Pools = strings.Split(result[fieldPools], ";")
+	}
+
+	hashrates, err := packed(result, fieldHashrates, 0)
+	if err != nil {
+		return
+	}
+	sensors, err := packed(result, fieldSensors, 0)
+	if err != nil {
+		return
+	}
+	stat.GPUs = make([]StatGPU, len(hashrates))
+	for i := range stat.GPUs {
+		stat.GPUs[i].Hashrate = hashrates[i] * 1000
+		stat.GPUs[i].PCIBus = -1
+		// some miners leave out the sensors of GPUs they cannot read
+		if 2*i+1 < len(sensors) {
+			stat.GPUs[i].Temperature, stat.GPUs[i].FanSpeed = sensors[2*i], sensors[2*i+1]
+		}
+	}
+	if len(result) < stat2Fields {
+		return
+	}
+	perGPU := []struct {
+		field int
+		set   func(gpu *StatGPU, v float64)
+	}{
+		{fieldAccepted, func(gpu *StatGPU, v float64) { gpu.Accepted = int64(v) }},
+		{fieldRejected, func(gpu *StatGPU, v float64) { gpu.Rejected = int64(v) }},
+		{fieldInvalid, func(gpu *StatGPU, v float64) { gpu.Invalid = int64(v) }},
+		{fieldPCIBus, func(gpu *StatGPU, v float64) { gpu.PCIBus = int(v) }},
+	}
+	for _, p := range perGPU {
+		values, err := packed(result, p.field, len(stat.GPUs))
+		if err != nil {
+			return stat, err
+		}
+		for i := range stat.GPUs {
+			p.set(&stat.GPUs[i], values[i])
+		}
+	}
+	return
+}
+
+// packed splits the values packed in field, failing when there are fewer than want. Values switched off count as 0
+func packed(result []string, field int, want int) (values []float64, err error) {
+	if result[field] == "" {
+		if want > 0 {
+			return nil, fmt.Errorf("field %d: expected %d values, got none", field, want)
+		}
+		return
+	}
+	for _, s := range strings.Split(result[field], ";") {
+		if s == "off" {
+			values = append(values, 0)
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %q is not a number", field, s)
+		}
+		values = append(values, v)
+	}
+	if len(values) < want {
+		return nil, fmt.Errorf("field %d: expected %d values, got %d", field, want, len(values))
+	}
+	return
+}
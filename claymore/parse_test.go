@@ -0,0 +1,95 @@
+package claymore
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ParseStat(t *testing.T) {
+	stat1 := []string{"9.3 - ETH", "21", "182724;51;0", "30502;30457;30297;31469", "0;0;0", "off;off;off;off",
+		"53;71;57;67;61;72;55;70", "eu1.ethermine.org:4444", "0;0;0;0"}
+	stat2 := append(append([]string{}, stat1...), "13;12;14;12", "0;1;0;0", "0;0;0;2", "0;0;0;0", "0;0;0;0", "0;0;0;0", "1;2;3;4")
+	tests := []struct {
+		name    string
+		result  []string
+		want    Stat
+		wantErr string
+	}{
+		{
+			name:   "Stat1",
+			result: stat1,
+			want: Stat{
+				Version: "9.3 - ETH", Uptime: 21 * time.Minute, Hashrate: 182724000, Accepted: 51,
+				Pools: []string{"eu1.ethermine.org:4444"},
+				GPUs: []StatGPU{
+					{Hashrate: 30502000, Temperature: 53, FanSpeed: 71, PCIBus: -1},
+					{Hashrate: 30457000, Temperature: 57, FanSpeed: 67, PCIBus: -1},
+					{Hashrate: 30297000, Temperature: 61, FanSpeed: 72, PCIBus: -1},
+					{Hashrate: 31469000, Temperature: 55, FanSpeed: 70, PCIBus: -1},
+				},
+			},
+		},
+		{
+			name:   "Stat2",
+			result: stat2,
+			want: Stat{
+				Version: "9.3 - ETH", Uptime: 21 * time.Minute, Hashrate: 182724000, Accepted: 51,
+				Pools: []string{"eu1.ethermine.org:4444"},
+				GPUs: []StatGPU{
+					{Hashrate: 30502000, Temperature: 53, FanSpeed: 71, Accepted: 13, PCIBus: 1},
+					{Hashrate: 30457000, Temperature: 57, FanSpeed: 67, Accepted: 12, Rejected: 1, PCIBus: 2},
+					{Hashrate: 30297000, Temperature: 61, FanSpeed: 72, Accepted: 14, PCIBus: 3},
+					{Hashrate: 31469000, Temperature: 55, FanSpeed: 70, Accepted: 12, Invalid: 2, PCIBus: 4},
+				},
+			},
+		},
+		{
+			name:   "SwitchedOff01",
+			result: []string{"5.5c - ETH", "1440", "95000;2100;3", "47500;off", "0;0;0", "off;off", "62;45", "a:1;b:2", "1;2;0;0"},
+			want: Stat{
+				Version: "5.5c - ETH", Uptime: 24 * time.Hour, Hashrate: 95000000, Accepted: 2100, Rejected: 3, Invalid: 1,
+				PoolSwitches: 2, Pools: []string{"a:1", "b:2"},
+				GPUs: []StatGPU{{Hashrate: 47500000, Temperature: 62, FanSpeed: 45, PCIBus: -1}, {PCIBus: -1}},
+			},
+		},
+		{
+			name:    "TooFewFields01",
+			result:  stat1[:8],
+			wantErr: "expected at least 9 fields, got 8",
+		},
+		{
+			name:    "NotANumber01",
+			result:  append([]string{"9.3 - ETH", "21", "182724;many;0"}, stat1[3:]...),
+			wantErr: `field 2: "many" is not a number`,
+		},
+		{
+			name:    "MissingValues01",
+			result:  append(append([]string{}, stat1[:8]...), "0"),
+			wantErr: "field 8: expected 2 values, got 1",
+		},
+		{
+			name:    "MissingValues02",
+			result:  append(append([]string{}, stat2[:9]...), "13;12", "0;0;0;0", "0;0;0;0", "", "", "", "1;2;3;4"),
+			wantErr: "field 9: expected 4 values, got 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStat(tt.result)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseStat() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStat() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
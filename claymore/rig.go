@@ -0,0 +1,52 @@
+package claymore
+
+import (
+	"context"
+	"strings"
+
+	"mining-tools/rig"
+)
+
+// Rig adapts a Client to the rig.Rig interface
+type Rig struct {
+	client *Client
+	miner  string
+}
+
+// NewRig returns a rig.Rig backed by client, miner names the mining software in Stats, e.g. claymore or phoenixminer
+func NewRig(client *Client, miner string) *Rig {
+	return &Rig{client: client, miner: miner}
+}
+
+// Client returns the Claymore Client behind the Rig
+func (r *Rig) Client() *Client {
+	return r.client
+}
+
+// Stats returns the hashrate, shares, pool switches and sensor readings of the rig and each of its GPUs from
+// miner_getstat2
+func (r *Rig) Stats(ctx context.Context) (stats rig.Stats, err error) {
+	stat, err := r.client.GetStat2(ctx)
+	if err != nil {
+		return
+	}
+	// the version is sent as "9.3 - ETH", only the number is kept so the miner stays usable as a tag
+	stats.Miner = r.miner
+	if fields := strings.Fields(stat.Version); len(fields) > 0 {
+		stats.Miner += "-" + fields[0]
+	}
+	stats.Uptime = stat.Uptime
+	stats.Hashrate = stat.Hashrate
+	stats.Shares = rig.Shares{Accepted: stat.Accepted, Rejected: stat.Rejected, Invalid: stat.Invalid}
+	stats.PoolSwitches = stat.PoolSwitches
+	for i, gpu := range stat.GPUs {
+		stats.GPUs = append(stats.GPUs, rig.GPU{
+			Index:       i,
+			Hashrate:    gpu.Hashrate,
+			Temperature: gpu.Temperature,
+			FanSpeed:    gpu.FanSpeed,
+			Shares:      rig.Shares{Accepted: gpu.Accepted, Rejected: gpu.Rejected, Invalid: gpu.Invalid},
+		})
+	}
+	return
+}
@@ -0,0 +1,35 @@
+package claymore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/rig"
+	"mining-tools/rig/rigtest"
+)
+
+func Test_Rig(t *testing.T) {
+	server := rigtest.NewServer(reply(stat2Phoenix))
+	defer server.Close()
+	stats, err := NewRig(NewClient(server.Addr), "phoenixminer").Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Rig.Stats() error = %v", err)
+	}
+	want := rig.Stats{
+		Miner:        "phoenixminer-5.5c",
+		Uptime:       24 * time.Hour,
+		Hashrate:     95000000,
+		Shares:       rig.Shares{Accepted: 2100, Rejected: 3, Invalid: 1},
+		PoolSwitches: 2,
+		GPUs: []rig.GPU{
+			{Index: 0, Hashrate: 47500000, Temperature: 62, FanSpeed: 45, Shares: rig.Shares{Accepted: 1050, Rejected: 1}},
+			{Index: 1},
+			{Index: 2, Hashrate: 47500000, Temperature: 64, FanSpeed: 50, Shares: rig.Shares{Accepted: 1050, Rejected: 2, Invalid: 1}},
+		},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("Rig.Stats() = %+v, want %+v", stats, want)
+	}
+}
@@ -0,0 +1,50 @@
+package claymore
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Request is a request to the API, Password is only sent when the miner requires one
+type Request struct {
+	ID       int    `json:"id"`
+	JSONRPC  string `json:"jsonrpc"`
+	Method   string `json:"method"`
+	Password string `json:"psw,omitempty"`
+}
+
+// Response is a response from the API, Result is decoded by the method's caller and Error is null on success
+type Response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// Stat is the parsed result of miner_getstat1 or miner_getstat2, hashrates are in H/s. Only the main coin is kept,
+// the second coin of dual mining is left out
+type Stat struct {
+	// Version is the miner and the main coin, e.g. "9.3 - ETH"
+	Version  string
+	Uptime   time.Duration
+	Hashrate float64
+	Accepted int64
+	Rejected int64
+	Invalid  int64
+	// PoolSwitches counts the times the miner switched to another pool since it started
+	PoolSwitches int64
+	// Pools are the pools being mined on, the second is the pool of the dual mined coin
+	Pools []string
+	GPUs  []StatGPU
+}
+
+// StatGPU is the parsed stats of one GPU, Temperature is in °C and FanSpeed in percent. The shares and PCI bus are
+// only given by miner_getstat2, PCIBus is -1 without it
+type StatGPU struct {
+	Hashrate    float64
+	Temperature float64
+	FanSpeed    float64
+	Accepted    int64
+	Rejected    int64
+	Invalid     int64
+	PCIBus      int
+}
@@ -0,0 +1,18 @@
+package claymore
+
+// stat1Claymore is a miner_getstat1 reply of Claymore's Dual Miner mining ETH on four GPUs, sent without a line ending
+const stat1Claymore = `{"id": 0, "result": ["9.3 - ETH", "21", "182724;51;0", "30502;30457;30297;31469", "0;0;0", ` +
+	`"off;off;off;off", "53;71;57;67;61;72;55;70", "eu1.ethermine.org:4444", "0;0;0;0"], "error": null}`
+
+// stat2Claymore is the miner_getstat2 reply matching stat1Claymore
+const stat2Claymore = `{"id": 0, "result": ["9.3 - ETH", "21", "182724;51;0", "30502;30457;30297;31469", "0;0;0", ` +
+	`"off;off;off;off", "53;71;57;67;61;72;55;70", "eu1.ethermine.org:4444", "0;0;0;0", "13;12;14;12", "0;0;0;0", ` +
+	`"0;0;0;0", "0;0;0;0", "0;0;0;0", "0;0;0;0", "1;2;3;4"], "error": null}`
+
+// stat2Phoenix is a miner_getstat2 reply of PhoenixMiner with a switched off GPU and a pool switch, ended by a new line
+const stat2Phoenix = `{"id":0,"jsonrpc":"2.0","result":["5.5c - ETH","1440","95000;2100;3","47500;0;47500","0;0;0",` +
+	`"off;off;off","62;45;0;0;64;50","eth-eu1.nanopool.org:9999;eth-eu2.nanopool.org:9999","1;2;0;0","1050;0;1050","1;0;2",` +
+	`"0;0;1","0;0;0","0;0;0","0;0;0","5;6;7"]}` + "\n"
+
+// wrongPassword is PhoenixMiner's reply to a request with a missing or wrong password
+const wrongPassword = `{"id":0,"jsonrpc":"2.0","error":"Missing or invalid password"}` + "\n"
@@ -0,0 +1,66 @@
+/*
+Package miningtools contains the various supported CLI commands for mining-tools
+Copyright © 2020 Keith Olenchak <kenjin.domini@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miningtools
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"mining-tools/nanopool/nanopooltest"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// devCmd groups the hidden commands used while developing mining-tools
+var (
+	fakeNanopoolListen string
+	fakeNanopoolDelay  time.Duration
+	devCmd             = &cobra.Command{
+		Use:    "dev",
+		Short:  "Tools for developing mining-tools",
+		Hidden: true,
+	}
+	fakeNanopoolCmd = &cobra.Command{
+		Use:   "fake-nanopool",
+		Short: "Serves a fake nanopool API for offline development",
+		Long: `Serves a fake nanopool API with realistic data for the miner address
+` + nanopooltest.DefaultAddress + `, point miningtools.nanopool.apiRoot at the printed
+URL to run metrics and nanopool generalInfo offline.`,
+		Run: fakeNanopoolCmdRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(devCmd)
+	devCmd.AddCommand(fakeNanopoolCmd)
+
+	fakeNanopoolCmd.Flags().StringVar(&fakeNanopoolListen, "listen", "127.0.0.1:8080", "address to serve the fake nanopool API on")
+	fakeNanopoolCmd.Flags().DurationVar(&fakeNanopoolDelay, "delay", 0, "delay added to every response, e.g. 2s")
+}
+
+func fakeNanopoolCmdRun(cmd *cobra.Command, args []string) {
+	log.Debugln("fakeNanopoolCmdRun called")
+	config := nanopooltest.DefaultConfig()
+	config.Delay = fakeNanopoolDelay
+	fmt.Printf("Serving fake nanopool API for %s at http://%s/v1/eth/\n", nanopooltest.DefaultAddress, fakeNanopoolListen)
+	if err := http.ListenAndServe(fakeNanopoolListen, nanopooltest.NewHandler(config)); err != nil {
+		fmt.Println(err)
+		log.Errorf("fakeNanopoolCmdRun: http.ListenAndServe(%s, handler); returned err=%s\n", fakeNanopoolListen, err.Error())
+	}
+}
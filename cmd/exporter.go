@@ -0,0 +1,287 @@
+/*
+Package miningtools contains the various supported CLI commands for mining-tools
+Copyright © 2020 Keith Olenchak <kenjin.domini@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miningtools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"mining-tools/nanopool"
+	"mining-tools/promtext"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// exporterShareRateHours is how far back the share rate history is averaged for mining_nanopool_shares_per_hour
+const exporterShareRateHours = 24
+
+// exporterCmd represents the exporter command
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Serves nanopool and wallet metrics on /metrics for Prometheus to scrape",
+	Long: `Listens on --listen and serves /metrics in the Prometheus text exposition format. The nanopool
+account (balance, hashrate averages, per-worker hashrate and last share age, share rate) and the
+etherscan wallet with its value at nanopool's prices are collected on every scrape, or every
+--refresh when it is set, in which case scrapes are answered with the last collection.`,
+	Run: exporterCmdRun,
+}
+
+// exporterCollector is one source of the exporter's metrics, Enabled leaves out sources that are not configured
+type exporterCollector struct {
+	Name    string
+	Enabled func() bool
+	Collect func(ctx context.Context, now time.Time) ([]*promtext.Family, error)
+}
+
+// exporterCollectors are collected in order, each independently of the others failing
+var exporterCollectors = []exporterCollector{
+	{Name: "nanopool", Enabled: func() bool { return true }, Collect: collectNanopoolFamilies},
+	{Name: "wallet", Enabled: walletConfigured, Collect: collectWalletFamilies},
+}
+
+// exporter serves the collected metrics, collecting on every request unless refresh is set
+type exporter struct {
+	refresh  time.Duration
+	mu       sync.RWMutex
+	families []*promtext.Family
+}
+
+func init() {
+	rootCmd.AddCommand(exporterCmd)
+
+	exporterCmd.Flags().String("listen", ":9797", "address the /metrics endpoint listens on")
+	viper.BindPFlag("miningtools.exporter.listen", exporterCmd.Flags().Lookup("listen"))
+	exporterCmd.Flags().Duration("refresh", 0, "collect in the background at this interval instead of on every scrape, e.g. 1m (Default: 0, on every scrape)")
+	viper.BindPFlag("miningtools.exporter.refresh", exporterCmd.Flags().Lookup("refresh"))
+}
+
+func exporterCmdRun(cmd *cobra.Command, args []string) {
+	log.Debugln("exporterCmdRun called")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	listen := viper.GetString("miningtools.exporter.listen")
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", newExporter(ctx, viper.GetDuration("miningtools.exporter.refresh")))
+	server := &http.Server{Addr: listen, Handler: mux}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Infoln("exporterCmdRun: shutting down")
+		cancel()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	log.Infof("exporterCmdRun: serving /metrics on %s\n", listen)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fmt.Println(err)
+		log.Errorf("exporterCmdRun: server.ListenAndServe(%s); returned err=%s\n", listen, err.Error())
+	}
+}
+
+// newExporter returns an exporter collecting on every request, or, when refresh is positive, collecting once now and
+// then every refresh until ctx is done
+func newExporter(ctx context.Context, refresh time.Duration) *exporter {
+	e := &exporter{refresh: refresh}
+	if refresh <= 0 {
+		return e
+	}
+	e.families = collectFamilies(ctx, time.Now().UTC())
+	go func() {
+		ticker := time.NewTicker(refresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				families := collectFamilies(ctx, time.Now().UTC())
+				e.mu.Lock()
+				e.families = families
+				e.mu.Unlock()
+			}
+		}
+	}()
+	return e
+}
+
+// ServeHTTP answers a scrape with the current metrics, the response is encoded in full first so an invalid metric is
+// reported as an error rather than a truncated body
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var families []*promtext.Family
+	if e.refresh > 0 {
+		e.mu.RLock()
+		families = e.families
+		e.mu.RUnlock()
+	} else {
+		families = collectFamilies(r.Context(), time.Now().UTC())
+	}
+	var body bytes.Buffer
+	if err := promtext.Encode(&body, families); err != nil {
+		log.Errorf("exporter.ServeHTTP: promtext.Encode(%d families); returned err=%s\n", len(families), err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", promtext.ContentType)
+	w.Write(body.Bytes())
+}
+
+// collectFamilies runs every enabled collector and adds whether each succeeded and how long it took, a failing
+// collector leaves out its own metrics only
+func collectFamilies(ctx context.Context, now time.Time) (families []*promtext.Family) {
+	up := promtext.NewFamily("mining_exporter_collector_up", promtext.Gauge,
+		"Whether the last collection from a source succeeded, 1, or failed, 0")
+	duration := promtext.NewFamily("mining_exporter_collector_duration_seconds", promtext.Gauge,
+		"How long the last collection from a source took")
+	for _, c := range exporterCollectors {
+		if !c.Enabled() {
+			continue
+		}
+		start := time.Now()
+		collected, err := c.Collect(ctx, now)
+		duration.Add(time.Since(start).Seconds(), "collector", c.Name)
+		if err != nil {
+			log.Errorf("collectFamilies: %s.Collect(); returned err=%s\n", c.Name, err.Error())
+			up.Add(0, "collector", c.Name)
+			continue
+		}
+		up.Add(1, "collector", c.Name)
+		families = append(families, collected...)
+	}
+	return append(families, up, duration)
+}
+
+// collectNanopoolFamilies reads the balance, hashrates, workers and share rate of the configured nanopool account.
+// Hashrates are converted to hashes (or solutions) per second whatever unit the coin is reported in
+func collectNanopoolFamilies(ctx context.Context, now time.Time) (families []*promtext.Family, err error) {
+	client, err := newNanopoolClient()
+	if err != nil {
+		log.Errorf("collectNanopoolFamilies: newNanopoolClient(); returned err=%s\n", err.Error())
+		return
+	}
+	coin := client.Coin().String()
+	address := nanopoolAddress(client.Coin())
+	info, err := client.GetMinerGeneralInfo(ctx, address)
+	if err != nil {
+		log.Errorf("collectNanopoolFamilies: client.GetMinerGeneralInfo(ctx, %s); returned err=%s\n", address, err.Error())
+		return
+	}
+	shareRate, err := client.GetMinerShareRate(ctx, address)
+	if err != nil {
+		log.Errorf("collectNanopoolFamilies: client.GetMinerShareRate(ctx, %s); returned err=%s\n", address, err.Error())
+		return
+	}
+	unit := client.Coin().Info().HashrateUnit
+
+	balance := promtext.NewFamily("mining_nanopool_balance_coins", promtext.Gauge,
+		"Confirmed balance of the nanopool account").
+		Add(info.Data.Balance.Float64(), "coin", coin, "account", address)
+	unconfirmed := promtext.NewFamily("mining_nanopool_unconfirmed_balance_coins", promtext.Gauge,
+		"Balance of the nanopool account waiting for its blocks to be confirmed").
+		Add(info.Data.UnconfirmedBalance.Float64(), "coin", coin, "account", address)
+	hashrate := promtext.NewFamily("mining_nanopool_hashrate_hashes_per_second", promtext.Gauge,
+		"Hashrate nanopool credits the account with, currently and averaged over the window")
+	avg := info.Data.AvgHashrate
+	hashrate.Add(info.Data.Hashrate.HashesPerSecond(unit), "coin", coin, "account", address, "window", "current")
+	for i, h := range []nanopool.Hashrate{avg.H1, avg.H3, avg.H6, avg.H12, avg.H24} {
+		hashrate.Add(h.HashesPerSecond(unit), "coin", coin, "account", address, "window", windowLabel(reconcileWindows[i]))
+	}
+	workerHashrate := promtext.NewFamily("mining_nanopool_worker_hashrate_hashes_per_second", promtext.Gauge,
+		"Hashrate nanopool credits a worker with, currently and averaged over the window")
+	lastShareAge := promtext.NewFamily("mining_nanopool_worker_last_share_age_seconds", promtext.Gauge,
+		"Seconds since nanopool last received a share from a worker")
+	for _, w := range info.Data.Workers {
+		workerHashrate.Add(w.Hashrate.HashesPerSecond(unit), "coin", coin, "account", address, "worker", w.ID, "window", "current")
+		for i, h := range []nanopool.Hashrate{w.H1, w.H3, w.H6, w.H12, w.H24} {
+			workerHashrate.Add(h.HashesPerSecond(unit), "coin", coin, "account", address, "worker", w.ID, "window", windowLabel(reconcileWindows[i]))
+		}
+		if w.Lastshare > 0 {
+			lastShareAge.Add(now.Sub(time.Unix(w.Lastshare, 0)).Seconds(), "coin", coin, "account", address, "worker", w.ID)
+		}
+	}
+	sharesPerHour := promtext.NewFamily("mining_nanopool_shares_per_hour", promtext.Gauge,
+		"Shares the account submitted per hour, averaged over the window").
+		Add(averageSharesPerHour(shareRate.Data, now, exporterShareRateHours),
+			"coin", coin, "account", address, "window", windowLabel(exporterShareRateHours))
+	families = append(families, balance, unconfirmed, hashrate, workerHashrate, lastShareAge, sharesPerHour)
+	return
+}
+
+// collectWalletFamilies reads the etherscan wallet and values it at nanopool's ETH prices
+func collectWalletFamilies(ctx context.Context, now time.Time) (families []*promtext.Family, err error) {
+	walletStats, err := collectWalletFinancialStats()
+	if err != nil {
+		log.Errorf("collectWalletFamilies: collectWalletFinancialStats(); returned err=%s\n", err.Error())
+		return
+	}
+	address := viper.GetString("miningtools.etherscan.address")
+	price, _ := walletStats.CoinUSD.Float64()
+	eth, _ := walletStats.Balance.Float64()
+	usd, _ := walletStats.BalanceUSD.Float64()
+	btc, _ := walletStats.BalanceBTC.Float64()
+	families = append(families,
+		promtext.NewFamily("mining_ethereum_price_usd", promtext.Gauge, "Price of one ETH in USD according to nanopool").
+			Add(price),
+		promtext.NewFamily("mining_wallet_balance_eth", promtext.Gauge, "Balance of the wallet in ETH").
+			Add(eth, "address", address),
+		promtext.NewFamily("mining_wallet_balance_usd", promtext.Gauge, "Balance of the wallet valued in USD").
+			Add(usd, "address", address),
+		promtext.NewFamily("mining_wallet_balance_btc", promtext.Gauge, "Balance of the wallet valued in BTC").
+			Add(btc, "address", address),
+	)
+	return
+}
+
+// walletConfigured reports whether an etherscan wallet address is set, without one there is no wallet to collect
+func walletConfigured() bool {
+	return viper.GetString("miningtools.etherscan.address") != ""
+}
+
+// averageSharesPerHour divides the shares of the slots in the hours before now by hours. Unlike calcSharesPerHour
+// the average is not rounded, as a rate of a few shares an hour would lose most of its precision
+func averageSharesPerHour(shareRate []nanopool.MinerShareRateData, now time.Time, hours int64) float64 {
+	since := now.Add(-time.Duration(hours) * time.Hour).Unix()
+	shares := int64(0)
+	for _, sr := range shareRate {
+		if sr.Date > since {
+			shares += sr.Shares
+		}
+	}
+	return float64(shares) / float64(hours)
+}
+
+// windowLabel is the window label of an average over hours, e.g. 24h
+func windowLabel(hours int64) string {
+	return strconv.FormatInt(hours, 10) + "h"
+}
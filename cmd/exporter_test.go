@@ -0,0 +1,110 @@
+package miningtools
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mining-tools/nanopool/nanopooltest"
+	"mining-tools/promtext"
+)
+
+// scrape gets /metrics from an exporter served by a local server
+func scrape(t *testing.T, e *exporter, method string) (statusCode int, contentType string, body string) {
+	t.Helper()
+	server := httptest.NewServer(e)
+	defer server.Close()
+	req, _ := http.NewRequest(method, server.URL+"/metrics", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s /metrics error = %v", method, err)
+	}
+	defer resp.Body.Close()
+	b, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), string(b)
+}
+
+func Test_exporter(t *testing.T) {
+	account := `coin="eth",account="` + nanopooltest.DefaultAddress + `"`
+	tests := []struct {
+		name    string
+		errors  map[string]string
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "Success01",
+			want: []string{
+				"# HELP mining_nanopool_balance_coins Confirmed balance of the nanopool account\n# TYPE mining_nanopool_balance_coins gauge\n",
+				"mining_nanopool_balance_coins{" + account + "} 0.142\n",
+				"mining_nanopool_unconfirmed_balance_coins{" + account + "} 0.0015\n",
+				"mining_nanopool_hashrate_hashes_per_second{" + account + `,window="current"} 1.905e+08` + "\n",
+				"mining_nanopool_hashrate_hashes_per_second{" + account + `,window="24h"} 1.905e+08` + "\n",
+				"mining_nanopool_worker_hashrate_hashes_per_second{" + account + `,worker="rig1",window="current"} 9.55e+07` + "\n",
+				"mining_nanopool_worker_hashrate_hashes_per_second{" + account + `,worker="rig2",window="1h"} `,
+				"mining_nanopool_worker_last_share_age_seconds{" + account + `,worker="rig1"} `,
+				"mining_nanopool_shares_per_hour{" + account + `,window="24h"} 72` + "\n",
+				"mining_ethereum_price_usd 730.51\n",
+				`mining_wallet_balance_eth{address="0x01"} 123.45678901234568` + "\n",
+				`mining_exporter_collector_up{collector="nanopool"} 1` + "\n",
+				`mining_exporter_collector_up{collector="wallet"} 1` + "\n",
+				`mining_exporter_collector_duration_seconds{collector="nanopool"} `,
+			},
+		},
+		{
+			name:   "NanopoolDown01",
+			errors: map[string]string{"user/" + nanopooltest.DefaultAddress: "Account not found"},
+			want: []string{
+				`mining_exporter_collector_up{collector="nanopool"} 0` + "\n",
+				`mining_exporter_collector_up{collector="wallet"} 1` + "\n",
+				`mining_wallet_balance_usd{address="0x01"} `,
+			},
+			notWant: []string{"mining_nanopool_"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := nanopooltest.DefaultConfig()
+			config.Errors = tt.errors
+			useFakeNanopool(t, config)
+			statusCode, contentType, body := scrape(t, newExporter(context.Background(), 0), http.MethodGet)
+			if statusCode != http.StatusOK || contentType != promtext.ContentType {
+				t.Fatalf("GET /metrics = %d %s, want %d %s", statusCode, contentType, http.StatusOK, promtext.ContentType)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("GET /metrics = %s, want it to contain %q", body, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("GET /metrics = %s, want it not to contain %q", body, notWant)
+				}
+			}
+		})
+	}
+}
+
+func Test_exporterRefresh(t *testing.T) {
+	server := useFakeNanopool(t, nanopooltest.DefaultConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e := newExporter(ctx, time.Hour)
+	// Scrapes are answered from the collection made at start, so nanopool going away is not noticed until the next one
+	server.Close()
+	_, _, body := scrape(t, e, http.MethodGet)
+	if want := `mining_exporter_collector_up{collector="nanopool"} 1`; !strings.Contains(body, want) {
+		t.Errorf("GET /metrics = %s, want it to contain %q", body, want)
+	}
+}
+
+func Test_exporterMethod(t *testing.T) {
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	if statusCode, _, _ := scrape(t, newExporter(context.Background(), 0), http.MethodPost); statusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /metrics = %d, want %d", statusCode, http.StatusMethodNotAllowed)
+	}
+}
@@ -17,10 +17,10 @@ limitations under the License.
 package miningtools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
-	"strconv"
 	"time"
 
 	"mining-tools/nanopool"
@@ -36,8 +36,8 @@ var (
 	sharesPerHourFlag  bool
 	generalInfoCmd     = &cobra.Command{
 		Use:   "generalInfo",
-		Short: "Gets general info of nanopool ethereum miner account",
-		Long: `Gets general info of nanopool ethereum miner account, with
+		Short: "Gets general info of a nanopool miner account",
+		Long: `Gets general info of a nanopool miner account, with
 				options for calculating additional values`,
 		Run: generalInfoCmdRun,
 	}
@@ -63,59 +63,80 @@ func init() {
 
 func generalInfoCmdRun(cmd *cobra.Command, args []string) {
 	log.Debugln("generalInfoCmdRun called")
-	address := viper.GetString("miningtools.nanopool.address")
-	apiRoot := viper.GetString("miningtools.nanopool.apiRoot")
-	log.Debugf("generalInfoCmdRun: address=%s; apiRoot=%s\n", address, apiRoot)
-	info, err := nanopool.GetMinerGeneralInfo(apiRoot, address)
+	info, err := collectGeneralInfo(rewardPerShareFlag, sharesPerHourFlag)
 	if err != nil {
 		fmt.Println(err)
-		log.Errorf("generalInfoCmdRun: getMinerGeneralInfo(apiRoot=%s, address=%s); returned err=%s\n",
-			apiRoot, address, err.Error())
+		log.Errorf("generalInfoCmdRun: collectGeneralInfo(%t, %t); returned err=%s\n",
+			rewardPerShareFlag, sharesPerHourFlag, err.Error())
+		return
+	}
+	prettyPrint(info)
+	// TODO: updating the saved config should be optional
+	// TODO: break this out to check if writing is desired and
+	//	create the file if viper fails to, then retry
+	if err = viper.WriteConfig(); err != nil {
+		fmt.Println(err)
+		log.Errorf("viper.WriteConfig(); returned err=%s\n", err.Error())
 		// TODO: handle error
 		return
 	}
-	if rewardPerShareFlag {
-		payments, err := nanopool.GetMinerPayments(apiRoot, address)
+}
+
+// collectGeneralInfo fetches the general info of the configured miner and fills in the calculated attributes requested
+func collectGeneralInfo(rewardPerShare bool, sharesPerHour bool) (info nanopool.MinerGeneralInfo, err error) {
+	client, err := newNanopoolClient()
+	if err != nil {
+		log.Errorf("collectGeneralInfo: newNanopoolClient(); returned err=%s\n", err.Error())
+		return
+	}
+	ctx := context.Background()
+	address := nanopoolAddress(client.Coin())
+	apiRoot := client.APIRoot()
+	log.Debugf("collectGeneralInfo: address=%s; apiRoot=%s\n", address, apiRoot)
+	info, err = client.GetMinerGeneralInfo(ctx, address)
+	if err != nil {
+		log.Errorf("collectGeneralInfo: getMinerGeneralInfo(apiRoot=%s, address=%s); returned err=%s\n",
+			apiRoot, address, err.Error())
+		return
+	}
+	if rewardPerShare {
+		payments, err := client.GetMinerPayments(ctx, address)
 		if err != nil {
-			fmt.Println(err)
-			log.Errorf("generalInfoCmdRun: getMinerPayments(apiRoot=%s, address=%s); returned err=%s\n",
+			log.Errorf("collectGeneralInfo: getMinerPayments(apiRoot=%s, address=%s); returned err=%s\n",
 				apiRoot, address, err.Error())
-			// TODO: handle error
-			return
+			return info, err
+		}
+		totalPayouts, err := calcTotalPayout(payments.Data)
+		if err != nil {
+			log.Errorf("collectGeneralInfo: calcTotalPayout(%d payments); returned err=%s\n", len(payments.Data), err.Error())
+			return info, err
 		}
-		totalPayouts := calcTotalPayout(payments.Data)
 		totalShares := calcTotalShares(info.Data.Workers)
-		info.Data.RewardPerShare = calcRPS(info.Data.Balance, totalPayouts, totalShares)
-		log.Debugf("generalInfoCmdRun: totalPayouts=%s; totalShares=%d; info.Data.RewardPerShare=%s\n",
+		info.Data.RewardPerShare, err = calcRPS(info.Data.Balance, totalPayouts, totalShares)
+		if err != nil {
+			log.Errorf("collectGeneralInfo: calcRPS(%s, %s, %d); returned err=%s\n",
+				info.Data.Balance, totalPayouts, totalShares, err.Error())
+			return info, err
+		}
+		log.Debugf("collectGeneralInfo: totalPayouts=%s; totalShares=%d; info.Data.RewardPerShare=%g\n",
 			totalPayouts, totalShares, info.Data.RewardPerShare)
 	}
-	if sharesPerHourFlag {
+	if sharesPerHour {
 		hours := int64(24)
-		shareRate, err := nanopool.GetMinerShareRate(apiRoot, address)
+		shareRate, err := client.GetMinerShareRate(ctx, address)
 		if err != nil {
-			fmt.Println(err)
-			log.Errorf("generalInfoCmdRun: getMinerShareRate(apiRoot=%s, address=%s); returned err=%s\n",
+			log.Errorf("collectGeneralInfo: getMinerShareRate(apiRoot=%s, address=%s); returned err=%s\n",
 				apiRoot, address, err.Error())
-			// TODO: handle error
-			return
+			return info, err
 		}
 		info.Data.SharesPerHour = calcSharesPerHour(shareRate.Data, &hours)
-		log.Debugf("generalInfoCmdRun: info.Data.SharesPerHour=%d\n", info.Data.SharesPerHour)
+		log.Debugf("collectGeneralInfo: info.Data.SharesPerHour=%d\n", info.Data.SharesPerHour)
 	}
-	if rewardPerShareFlag && sharesPerHourFlag {
+	if rewardPerShare && sharesPerHour {
 		info.Data.RewardPerHour = calcRewardPerHour(info.Data.RewardPerShare, info.Data.SharesPerHour)
-		log.Debugf("generalInfoCmdRun: info.Data.RewardPerHour=%s\n", info.Data.RewardPerHour)
-	}
-	prettyPrint(info)
-	// TODO: updating the saved config should be optional
-	// TODO: break this out to check if writing is desired and
-	//	create the file if viper fails to, then retry
-	if err = viper.WriteConfig(); err != nil {
-		fmt.Println(err)
-		log.Errorf("viper.WriteConfig(); returned err=%s\n", err.Error())
-		// TODO: handle error
-		return
+		log.Debugf("collectGeneralInfo: info.Data.RewardPerHour=%g\n", info.Data.RewardPerHour)
 	}
+	return
 }
 
 func prettyPrint(v interface{}) {
@@ -130,11 +151,19 @@ func prettyPrint(v interface{}) {
 	fmt.Println(out)
 }
 
-func calcRPS(balance string, totalPayouts string, totalShares int64) (rps string) {
-	bal, _ := strconv.ParseFloat(balance, 64)
-	payouts, _ := strconv.ParseFloat(totalPayouts, 64)
-	lifetimeEarnings := bal + payouts
-	rps = fmt.Sprintf("%f", lifetimeEarnings/float64(totalShares))
+func calcRPS(balance nanopool.Amount, totalPayouts nanopool.Amount, totalShares int64) (rps float64, err error) {
+	if totalShares < 0 {
+		err = fmt.Errorf("cannot calculate reward per share from %d shares", totalShares)
+		return
+	}
+	if totalShares == 0 {
+		return
+	}
+	lifetimeEarnings, err := balance.Add(totalPayouts)
+	if err != nil {
+		return
+	}
+	rps = lifetimeEarnings.Float64() / float64(totalShares)
 	return
 }
 
@@ -170,21 +199,16 @@ func calcSharesPerHour(shareRate []nanopool.MinerShareRateData, hours *int64) (s
 	return
 }
 
-func calcRewardPerHour(rewardPerShare string, sharesPerHour int64) (rewardPerHour string) {
-	rps, _ := strconv.ParseFloat(rewardPerShare, 64)
-	rph := rps * float64(sharesPerHour)
-	rewardPerHour = fmt.Sprintf("%f", rph)
+func calcRewardPerHour(rewardPerShare float64, sharesPerHour int64) (rewardPerHour float64) {
+	rewardPerHour = rewardPerShare * float64(sharesPerHour)
 	return
 }
 
-func calcTotalPayout(payments []nanopool.MinerPaymentsData) (totalPayout string) {
-	if len(payments) == 0 {
-		return "0"
-	}
-	payout := float64(0)
+func calcTotalPayout(payments []nanopool.MinerPaymentsData) (totalPayout nanopool.Amount, err error) {
 	for _, p := range payments {
-		payout += p.Amount
+		if totalPayout, err = totalPayout.Add(p.Amount); err != nil {
+			return
+		}
 	}
-	totalPayout = fmt.Sprintf("%f", payout)
 	return
 }
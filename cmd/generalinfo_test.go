@@ -1,7 +1,10 @@
 package miningtools
 
 import (
+	"errors"
+	"math"
 	"mining-tools/nanopool"
+	"mining-tools/nanopool/nanopooltest"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
@@ -57,3 +60,85 @@ func Test_calcSharesPerHour(t *testing.T) {
 		})
 	}
 }
+
+func Test_calcRPS(t *testing.T) {
+	balance, _ := nanopool.ParseAmount("0.142")
+	payments := []nanopool.MinerPaymentsData{{Amount: 100000000000}, {Amount: 58000000000}}
+	totalPayouts, _ := calcTotalPayout(payments)
+	type args struct {
+		balance      nanopool.Amount
+		totalPayouts nanopool.Amount
+		totalShares  int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantRPS float64
+		wantErr bool
+	}{
+		{
+			name: "Simple01",
+			args: args{
+				balance:      balance,
+				totalPayouts: totalPayouts,
+				totalShares:  10000,
+			},
+			wantRPS: 0.00003,
+		},
+		{
+			name: "NoShares01",
+			args: args{
+				balance:      balance,
+				totalPayouts: 0,
+				totalShares:  0,
+			},
+			wantRPS: 0,
+		},
+		{
+			name: "NegativeShares01",
+			args: args{
+				balance:      balance,
+				totalPayouts: 0,
+				totalShares:  -1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRPS, err := calcRPS(tt.args.balance, tt.args.totalPayouts, tt.args.totalShares)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("calcRPS() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(gotRPS-tt.wantRPS) > 1e-15 {
+				t.Errorf("calcRPS() = %v, want %v", gotRPS, tt.wantRPS)
+			}
+		})
+	}
+}
+
+func Test_calcTotalPayout(t *testing.T) {
+	payments := []nanopool.MinerPaymentsData{{Amount: math.MaxInt64 - 100}, {Amount: 58000000000}}
+	if total, err := calcTotalPayout(payments); !errors.Is(err, nanopool.ErrAmountOverflow) {
+		t.Errorf("calcTotalPayout() = %s, %v, want %v", total, err, nanopool.ErrAmountOverflow)
+	}
+}
+
+func Test_collectGeneralInfo(t *testing.T) {
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	info, err := collectGeneralInfo(true, true)
+	if err != nil {
+		t.Fatalf("collectGeneralInfo() error = %v", err)
+	}
+	if info.Data.Balance.String() != "0.142" || len(info.Data.Workers) != 2 {
+		t.Errorf("collectGeneralInfo() = %+v, want the default account", info.Data)
+	}
+	if info.Data.SharesPerHour != 72 {
+		t.Errorf("collectGeneralInfo() SharesPerHour = %d, want %d", info.Data.SharesPerHour, 72)
+	}
+	if info.Data.RewardPerShare <= 0 || info.Data.RewardPerHour <= 0 {
+		t.Errorf("collectGeneralInfo() RewardPerShare = %g, RewardPerHour = %g, want both above 0",
+			info.Data.RewardPerShare, info.Data.RewardPerHour)
+	}
+}
@@ -17,15 +17,21 @@ limitations under the License.
 package miningtools
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"math/big"
+	"mining-tools/lineprotocol"
 	"mining-tools/nanopool"
-	"net"
+	"mining-tools/pool"
+	"mining-tools/rig"
+	"mining-tools/wei"
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -35,42 +41,217 @@ import (
 
 // Metrics is an interface to cover the various stats and metrics structs that may be created over time
 type Metrics interface {
-	InfluxDBLine() []byte
+	Point(measurement string) *lineprotocol.Point
 }
 
-// PoolStats is a struct for tracking some metrics gathered and calculated from the mining pool
+// PoolStats is a struct for tracking some metrics gathered and calculated from the mining pool,
+// Location is the pool type while Pool and Account tell configured pools and accounts apart
 type PoolStats struct {
+	Time     time.Time
 	Location string
+	Pool     string
+	Account  string
 	Balance  float64
-	Shares   int64
+	// Shares, ShareCounts, WorkerCounts, EstimatedDailyEarnings, Reward24h and the effective and reported hashrates
+	// are only set for pools that report them
+	Shares                 *int64
+	ShareCounts            *pool.ShareCounts
+	WorkerCounts           *pool.WorkerCounts
+	EstimatedDailyEarnings *float64
+	Reward24h              *big.Rat
+	EffectiveHashrate      *float64
+	ReportedHashrate       *float64
 }
 
-// InfluxDBLine will convert the struct to a byte slice for delivery as a network payload
-func (ps *PoolStats) InfluxDBLine(table string) (payload []byte) {
-	balance := floatToStringNoTrail(ps.Balance)
-	payload = []byte(fmt.Sprintf("%s,Location=%s Balance=%s,Shares=%d %d\n",
-		table, ps.Location, balance, ps.Shares, time.Now().UTC().UnixNano()))
-	return
+// Point will convert the struct to a line protocol point observed at Time
+func (ps *PoolStats) Point(measurement string) *lineprotocol.Point {
+	p := lineprotocol.NewPoint(measurement, ps.Time).AddTag("Location", ps.Location)
+	addPoolTags(p, ps.Pool, ps.Account)
+	p.AddField("Balance", ps.Balance)
+	if ps.Shares != nil {
+		// written as a float as it always has been, a field cannot change type in an existing table
+		p.AddField("Shares", float64(*ps.Shares))
+	}
+	if ps.ShareCounts != nil {
+		p.AddField("ValidShares", ps.ShareCounts.Valid).
+			AddField("StaleShares", ps.ShareCounts.Stale).
+			AddField("InvalidShares", ps.ShareCounts.Invalid)
+	}
+	if ps.EstimatedDailyEarnings != nil {
+		p.AddField("EstimatedDailyEarnings", *ps.EstimatedDailyEarnings)
+	}
+	if ps.WorkerCounts != nil {
+		p.AddField("WorkersOnline", ps.WorkerCounts.Online).AddField("WorkersOffline", ps.WorkerCounts.Offline)
+	}
+	if ps.Reward24h != nil {
+		p.AddField("Reward24h", ps.Reward24h)
+	}
+	if ps.EffectiveHashrate != nil && ps.ReportedHashrate != nil {
+		p.AddField("EffectiveHashrate", *ps.EffectiveHashrate).AddField("ReportedHashrate", *ps.ReportedHashrate)
+	}
+	return p
+}
+
+// addPoolTags adds the Pool and Account tags to a point, or nothing for stats not tied to a pool account
+func addPoolTags(p *lineprotocol.Point, poolName string, account string) {
+	if poolName == "" {
+		return
+	}
+	p.AddTag("Pool", poolName).AddTag("Account", account)
+}
+
+// RigStats is a struct for tracking what the mining software on a rig reports about the whole rig,
+// Location is the rig type, Rig the configured rig and Worker the name the pool knows it by
+type RigStats struct {
+	Time           time.Time
+	Location       string
+	Rig            string
+	Miner          string
+	Worker         string
+	Hashrate       float64
+	Power          float64
+	AcceptedShares int64
+	RejectedShares int64
+	InvalidShares  int64
+	PoolSwitches   int64
+	Uptime         time.Duration
+	// PoolHashrate and PoolAverageHashrate are the current and 24 hour average hashrates a watched pool reports for
+	// Worker, when one does
+	PoolHashrate        *float64
+	PoolAverageHashrate *float64
+}
+
+// Point will convert the struct to a line protocol point observed at Time
+func (rs *RigStats) Point(measurement string) *lineprotocol.Point {
+	p := lineprotocol.NewPoint(measurement, rs.Time).
+		AddTag("Location", rs.Location).
+		AddTag("Rig", rs.Rig).
+		AddTag("Miner", rs.Miner).
+		AddTag("Worker", rs.Worker).
+		AddField("Hashrate", rs.Hashrate).
+		AddField("Power", rs.Power).
+		AddField("AcceptedShares", rs.AcceptedShares).
+		AddField("RejectedShares", rs.RejectedShares).
+		AddField("InvalidShares", rs.InvalidShares).
+		AddField("PoolSwitches", rs.PoolSwitches).
+		AddField("Uptime", int64(rs.Uptime.Seconds()))
+	if rs.Power > 0 {
+		p.AddField("Efficiency", efficiency(rs.Hashrate, rs.Power))
+	}
+	if rs.PoolHashrate != nil {
+		p.AddField("PoolHashrate", *rs.PoolHashrate)
+	}
+	if rs.PoolAverageHashrate != nil {
+		p.AddField("PoolAverageHashrate", *rs.PoolAverageHashrate)
+	}
+	return p
+}
+
+// GPUStats is a struct for tracking what the mining software on a rig reports about one of its devices, Worker is the
+// name the pool knows the rig by so devices can be grouped with the pool's workers
+type GPUStats struct {
+	Time              time.Time
+	Location          string
+	Rig               string
+	Worker            string
+	GPU               int
+	Hashrate          float64
+	Temperature       float64
+	MemoryTemperature float64
+	FanSpeed          float64
+	Power             float64
+	AcceptedShares    int64
+	RejectedShares    int64
+	InvalidShares     int64
+}
+
+// Point will convert the struct to a line protocol point observed at Time
+func (gs *GPUStats) Point(measurement string) *lineprotocol.Point {
+	p := lineprotocol.NewPoint(measurement, gs.Time).
+		AddTag("Location", gs.Location).
+		AddTag("Rig", gs.Rig).
+		AddTag("Worker", gs.Worker).
+		AddTag("GPU", strconv.Itoa(gs.GPU)).
+		AddField("Hashrate", gs.Hashrate).
+		AddField("Temperature", gs.Temperature).
+		AddField("MemoryTemperature", gs.MemoryTemperature).
+		AddField("FanSpeed", gs.FanSpeed).
+		AddField("Power", gs.Power).
+		AddField("AcceptedShares", gs.AcceptedShares).
+		AddField("RejectedShares", gs.RejectedShares).
+		AddField("InvalidShares", gs.InvalidShares)
+	if gs.Power > 0 {
+		p.AddField("Efficiency", efficiency(gs.Hashrate, gs.Power))
+	}
+	return p
+}
+
+// efficiency returns the MH/W of a hashrate in H/s drawing power W, rounded to 3 decimals as power readings are coarse
+func efficiency(hashrate float64, power float64) float64 {
+	return math.Round(hashrate/1e6/power*1000) / 1000
 }
 
-// FinancialStats is a struct for tracking some metrics relevant to financial health of mining operations
+// FinancialStats is a struct for tracking some metrics relevant to financial health of mining operations,
+// amounts are exact so small changes between runs are not lost to float rounding. Balance is in whole coins of Coin,
+// the prices and the values derived from them are nil when the pool does not publish them. CoinUSD and Balance are
+// written as the EthereumUSD and BalanceETH fields they were named when only ether was tracked, so existing tables
+// and dashboards keep filling, the Coin tag tells which coin they are in
 type FinancialStats struct {
-	Location    string
-	EthereumUSD float64
-	BalanceETH  float64
-	BalanceUSD  float64
-	BalanceBTC  float64
-}
-
-// InfluxDBLine will convert the struct to a byte slice for delivery as a network payload
-func (fs *FinancialStats) InfluxDBLine(table string) (payload []byte) {
-	eth := floatToStringNoTrail(fs.EthereumUSD)
-	balETH := floatToStringNoTrail(fs.BalanceETH)
-	balUSD := floatToStringNoTrail(fs.BalanceUSD)
-	balBTC := floatToStringNoTrail(fs.BalanceBTC)
-	payload = []byte(fmt.Sprintf("%s,Location=%s EthereumUSD=%s,BalanceETH=%s,BalanceUSD=%s,BalanceBTC=%s %d\n",
-		table, fs.Location, eth, balETH, balUSD, balBTC, time.Now().UTC().UnixNano()))
-	return
+	Time       time.Time
+	Location   string
+	Pool       string
+	Account    string
+	Coin       string
+	CoinUSD    *big.Rat
+	Balance    *big.Rat
+	BalanceUSD *big.Rat
+	BalanceBTC *big.Rat
+}
+
+// Point will convert the struct to a line protocol point observed at Time
+func (fs *FinancialStats) Point(measurement string) *lineprotocol.Point {
+	p := lineprotocol.NewPoint(measurement, fs.Time).AddTag("Location", fs.Location)
+	addPoolTags(p, fs.Pool, fs.Account)
+	p.AddTag("Coin", fs.Coin)
+	if fs.CoinUSD != nil {
+		p.AddField("EthereumUSD", fs.CoinUSD)
+	}
+	p.AddField("BalanceETH", fs.Balance)
+	if fs.BalanceUSD != nil {
+		p.AddField("BalanceUSD", fs.BalanceUSD)
+	}
+	if fs.BalanceBTC != nil {
+		p.AddField("BalanceBTC", fs.BalanceBTC)
+	}
+	return p
+}
+
+// NetworkStats is a struct for tracking pool wide and network conditions alongside our share of the pool
+type NetworkStats struct {
+	Time            time.Time
+	Location        string
+	Coin            string
+	Hashrate        float64
+	PoolHashrate    float64
+	PoolShare       float64
+	ActiveMiners    int64
+	ActiveWorkers   int64
+	AvgBlockTime    float64
+	LastBlockNumber int64
+}
+
+// Point will convert the struct to a line protocol point observed at Time
+func (ns *NetworkStats) Point(measurement string) *lineprotocol.Point {
+	return lineprotocol.NewPoint(measurement, ns.Time).
+		AddTag("Location", ns.Location).
+		AddTag("Coin", ns.Coin).
+		AddField("Hashrate", ns.Hashrate).
+		AddField("PoolHashrate", ns.PoolHashrate).
+		AddField("PoolShare", ns.PoolShare).
+		AddField("ActiveMiners", ns.ActiveMiners).
+		AddField("ActiveWorkers", ns.ActiveWorkers).
+		AddField("AvgBlockTime", ns.AvgBlockTime).
+		AddField("LastBlockNumber", ns.LastBlockNumber)
 }
 
 // QuestDBSuccessResponse is the expected shape of a successful response to a query
@@ -108,12 +289,14 @@ type QuestDBResponse interface {
 	isErrorResponse() bool
 }
 
-// EtherscanAccountBalance is the expected shape of the response from the etherscan API for an account balance plus "Balance" which is calaculated here based on result
+// EtherscanAccountBalance is the expected shape of the response from the etherscan API for an account balance plus "Wei"
+// and "Balance" (in ether) which are calculated here based on result
 type EtherscanAccountBalance struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 	Result  string `json:"result"`
-	Balance float64
+	Wei     *big.Int
+	Balance *big.Rat
 }
 
 // metricsCmd represents the metrics command
@@ -136,35 +319,131 @@ var (
 
 func metricsCmdRun(cmd *cobra.Command, args []string) {
 	log.Debugln("metricsCmdRun called")
-	poolStats, err := collectPoolStats()
-	if err != nil {
+	if dryRunFlag {
+		payload, err := collectMetrics()
+		if err != nil {
+			fmt.Println(err)
+			log.Errorf("metricsCmdRun: collectMetrics(); returned err=%s\n", err.Error())
+			return
+		}
+		fmt.Printf("DRYRUN: Metrics in InfluxDB Line format - %s", payload)
+		return
+	}
+	if err := writeMetrics(context.Background()); err != nil {
 		fmt.Println(err)
-		log.Errorf("metricsCmdRun: collectPoolStats(); returned err=%s\n", err.Error())
-		// TODO: handle error
+		log.Errorf("metricsCmdRun: writeMetrics(); returned err=%s\n", err.Error())
+	}
+}
+
+// writeMetrics gathers every stat and writes it to the timeseries database in miningtools.timeseriesDB, the database
+// is opened first so a bad protocol fails before any API is called
+func writeMetrics(ctx context.Context) (err error) {
+	s, err := openSink()
+	if err != nil {
+		log.Errorf("writeMetrics: openSink(); returned err=%s\n", err.Error())
 		return
 	}
-	payload := poolStats.InfluxDBLine("pool")
-	nanoStats, err := collectNanopoolFinancialStats()
+	points, err := collectPoints()
 	if err != nil {
-		fmt.Println(err)
-		log.Errorf("metricsCmdRun: collectFinancialStats(); returned err=%s\n", err.Error())
-		// TODO: handle error
+		log.Errorf("writeMetrics: collectPoints(); returned err=%s\n", err.Error())
 		return
 	}
-	payload = append(payload, nanoStats.InfluxDBLine("financial")...)
-	walletStats, err := collectWalletFinancialStats()
+	if err = s.Write(ctx, points); err != nil {
+		log.Errorf("writeMetrics: Write(%d points); returned err=%s\n", len(points), err.Error())
+	}
+	return
+}
+
+// collectMetrics gathers every stat and returns them as InfluxDB lines with nanosecond timestamps
+func collectMetrics() (payload []byte, err error) {
+	points, err := collectPoints()
 	if err != nil {
-		fmt.Println(err)
-		log.Errorf("metricsCmdRun: collectWalletFinancialStats(); returned err=%s\n", err.Error())
-		// TODO: handle error
+		log.Errorf("collectMetrics: collectPoints(); returned err=%s\n", err.Error())
 		return
 	}
-	payload = append(payload, walletStats.InfluxDBLine("financial")...)
-	if !dryRunFlag {
-		insertQuestDB("127.0.0.1:9009", payload)
-	} else {
-		fmt.Printf("DRYRUN: Metrics in InfluxDB Line format - %s", payload)
+	payload, err = lineprotocol.Encode(points, lineprotocol.Nanosecond)
+	if err != nil {
+		log.Errorf("collectMetrics: lineprotocol.Encode(); returned err=%s\n", err.Error())
+	}
+	return
+}
+
+// collectPoints gathers every stat as a point, stopping at the first collector to fail. Unreachable rigs, network
+// stats that could not be read and prices pools do not publish are left out instead
+func collectPoints() (points []*lineprotocol.Point, err error) {
+	accounts, err := openPoolAccounts()
+	if err != nil {
+		log.Errorf("collectPoints: openPoolAccounts(); returned err=%s\n", err.Error())
+		return
+	}
+	ctx := context.Background()
+	var financial []*lineprotocol.Point
+	for _, pa := range accounts {
+		poolStats, err := collectPoolStats(ctx, pa)
+		if err != nil {
+			log.Errorf("collectPoints: collectPoolStats(%s, %s); returned err=%s\n", pa.Config.Label(), pa.Account, err.Error())
+			return nil, err
+		}
+		points = append(points, poolStats.Point("pool"))
+		poolFinancialStats, err := collectPoolFinancialStats(ctx, pa)
+		if err != nil {
+			log.Errorf("collectPoints: collectPoolFinancialStats(%s, %s); returned err=%s\n", pa.Config.Label(), pa.Account, err.Error())
+			return nil, err
+		}
+		financial = append(financial, poolFinancialStats.Point("financial"))
+	}
+	rigs, err := openRigs()
+	if err != nil {
+		log.Errorf("collectPoints: openRigs(); returned err=%s\n", err.Error())
+		return
 	}
+	var rigStats []*RigStats
+	var gpuStats [][]GPUStats
+	for _, rc := range rigs {
+		stats, gpus, err := collectRigStats(ctx, rc)
+		if errors.Is(err, rig.ErrUnreachable) {
+			// a rig being down is what the metrics are there to show, it must not hide the stats of everything else
+			log.Warnf("collectPoints: rig %s is unreachable, leaving it out: %s\n", rc.Config.Label(), err.Error())
+			continue
+		}
+		if err != nil {
+			log.Errorf("collectPoints: collectRigStats(%s); returned err=%s\n", rc.Config.Label(), err.Error())
+			return nil, err
+		}
+		rigStats = append(rigStats, &stats)
+		gpuStats = append(gpuStats, gpus)
+	}
+	if err = addPoolHashrates(ctx, accounts, rigStats); err != nil {
+		log.Errorf("collectPoints: addPoolHashrates(); returned err=%s\n", err.Error())
+		return
+	}
+	for i := range rigStats {
+		points = append(points, rigStats[i].Point("rig"))
+		for j := range gpuStats[i] {
+			points = append(points, gpuStats[i][j].Point("gpu"))
+		}
+	}
+	coins, coinAccounts := nanopoolAccountsByCoin(accounts)
+	for _, coin := range coins {
+		// network stats only add context to the account's own stats, so a failing pool endpoint must not drop the rest
+		networkStats, err := collectNetworkStats(ctx, coinAccounts[coin])
+		if err != nil {
+			log.Warnf("collectPoints: collectNetworkStats(%s); returned err=%s, leaving out the network stats\n", coin, err.Error())
+			continue
+		}
+		points = append(points, networkStats.Point("network"))
+	}
+	points = append(points, financial...)
+	if !walletConfigured() {
+		return
+	}
+	walletStats, err := collectWalletFinancialStats()
+	if err != nil {
+		log.Errorf("collectPoints: collectWalletFinancialStats(); returned err=%s\n", err.Error())
+		return
+	}
+	points = append(points, walletStats.Point("financial"))
+	return
 }
 
 func init() {
@@ -182,71 +461,293 @@ func init() {
 	metricsCmd.Flags().BoolVarP(&dryRunFlag, "dryrun", "d", false, "Print metrics instead of shipping them to a timeseries DB")
 }
 
-func collectPoolStats() (poolStats PoolStats, err error) {
-	poolStats.Location = "nanopool"
-	nanoAPIRoot := viper.GetString("miningtools.nanopool.apiroot")
-	nanoAddress := viper.GetString("miningtools.nanopool.address")
-	mb, err := nanopool.GetMinerBalance(nanoAPIRoot, nanoAddress)
+// collectPoolStats reads the balance and the shares of the current 10 minute slot of one pool account
+func collectPoolStats(ctx context.Context, pa poolAccount) (poolStats PoolStats, err error) {
+	poolStats.Time = time.Now().UTC()
+	poolStats.Location = pa.Config.Type
+	poolStats.Pool = pa.Config.Label()
+	poolStats.Account = pa.Account
+	balance, err := pa.Pool.Balance(ctx, pa.Account)
 	if err != nil {
+		switch {
+		case errors.Is(err, pool.ErrAccountNotFound):
+			err = fmt.Errorf("pool %s does not know account %s, check miningtools.pools: %w", poolStats.Pool, pa.Account, err)
+		case errors.Is(err, pool.ErrRateLimited):
+			log.Warnf("collectPoolStats: pool %s is rate limiting requests, try running metrics less often\n", poolStats.Pool)
+		}
 		fmt.Println(err)
-		log.Errorf("collectPoolStats: getMinerBalance(%s, %s); returned err=%s\n", nanoAPIRoot, nanoAddress, err.Error())
-		// TODO: handle error
+		log.Errorf("collectPoolStats: Balance(%s, %s); returned err=%s\n", poolStats.Pool, pa.Account, err.Error())
 		return
 	}
-	poolStats.Balance = mb.Data
+	poolStats.Balance, _ = balance.Confirmed.Float64()
+	if counter, ok := pa.Pool.(pool.ShareCounter); ok {
+		counts, err := counter.ShareCounts(ctx, pa.Account)
+		if err != nil {
+			log.Errorf("collectPoolStats: ShareCounts(%s, %s); returned err=%s\n", poolStats.Pool, pa.Account, err.Error())
+			return poolStats, err
+		}
+		poolStats.ShareCounts = &counts
+	}
+	if estimator, ok := pa.Pool.(pool.EarningsEstimator); ok {
+		earnings, err := estimator.EstimatedDailyEarnings(ctx, pa.Account)
+		if err != nil {
+			log.Errorf("collectPoolStats: EstimatedDailyEarnings(%s, %s); returned err=%s\n", poolStats.Pool, pa.Account, err.Error())
+			return poolStats, err
+		}
+		poolStats.EstimatedDailyEarnings = &earnings
+	}
+	if counter, ok := pa.Pool.(pool.WorkerCounter); ok {
+		counts, err := counter.WorkerCounts(ctx, pa.Account)
+		if err != nil {
+			log.Errorf("collectPoolStats: WorkerCounts(%s, %s); returned err=%s\n", poolStats.Pool, pa.Account, err.Error())
+			return poolStats, err
+		}
+		poolStats.WorkerCounts = &counts
+	}
+	if reporter, ok := pa.Pool.(pool.RewardReporter); ok {
+		reward, err := reporter.Reward24h(ctx, pa.Account)
+		if err != nil {
+			log.Errorf("collectPoolStats: Reward24h(%s, %s); returned err=%s\n", poolStats.Pool, pa.Account, err.Error())
+			return poolStats, err
+		}
+		poolStats.Reward24h = reward
+	}
+	if reader, ok := pa.Pool.(pool.ReportedHashrateReader); ok {
+		reported, err := reader.ReportedHashrate(ctx, pa.Account)
+		if err != nil {
+			log.Errorf("collectPoolStats: ReportedHashrate(%s, %s); returned err=%s\n", poolStats.Pool, pa.Account, err.Error())
+			return poolStats, err
+		}
+		hashrate, err := pa.Pool.Hashrate(ctx, pa.Account)
+		if err != nil {
+			log.Errorf("collectPoolStats: Hashrate(%s, %s); returned err=%s\n", poolStats.Pool, pa.Account, err.Error())
+			return poolStats, err
+		}
+		poolStats.ReportedHashrate = &reported
+		poolStats.EffectiveHashrate = &hashrate.Current
+	}
 
 	d := time.Duration(10 * time.Minute)
-	now := time.Now().UTC().Truncate(d).Unix()
-	sr, err := nanopool.GetMinerShareRate(nanoAPIRoot, nanoAddress)
+	now := time.Now().UTC().Truncate(d)
+	slots, err := pa.Pool.ShareHistory(ctx, pa.Account)
+	if errors.Is(err, pool.ErrUnsupported) {
+		log.Debugf("collectPoolStats: pool %s has no share history, leaving out Shares\n", poolStats.Pool)
+		return poolStats, nil
+	}
 	if err != nil {
 		fmt.Println(err)
-		log.Errorf("collectPoolStats: getMinerShareRate(%s, %s); returned err=%s\n", nanoAPIRoot, nanoAddress, err.Error())
+		log.Errorf("collectPoolStats: ShareHistory(%s, %s); returned err=%s\n", poolStats.Pool, pa.Account, err.Error())
 		// TODO: handle error
 		return
 	}
 	found := false
-	for i := range sr.Data {
-		if sr.Data[i].Date == now {
-			poolStats.Shares = sr.Data[i].Shares
+	for i := range slots {
+		if slots[i].Time.Equal(now) {
+			poolStats.Shares = &slots[i].Shares
 			found = true
 			break
 		}
 	}
 	if !found {
-		err = fmt.Errorf("Date %d not found in share rate history", now)
+		err = fmt.Errorf("Date %d not found in share rate history", now.Unix())
+	}
+	return
+}
+
+// collectRigStats reads what the mining software on one rig reports about the rig and each of its devices
+func collectRigStats(ctx context.Context, rc rigCollector) (rigStats RigStats, gpuStats []GPUStats, err error) {
+	rigStats.Location = rc.Config.Type
+	rigStats.Rig = rc.Config.Label()
+	stats, err := rc.Rig.Stats(ctx)
+	if err != nil {
+		log.Errorf("collectRigStats: Stats(%s); returned err=%s\n", rigStats.Rig, err.Error())
+		return
+	}
+	rigStats.Time = time.Now().UTC()
+	rigStats.Miner = stats.Miner
+	rigStats.Worker = rc.Config.Worker
+	if rigStats.Worker == "" {
+		rigStats.Worker = stats.Worker
+	}
+	rigStats.Hashrate = stats.Hashrate
+	rigStats.Power = stats.Power
+	rigStats.AcceptedShares = stats.Shares.Accepted
+	rigStats.RejectedShares = stats.Shares.Rejected
+	rigStats.InvalidShares = stats.Shares.Invalid
+	rigStats.PoolSwitches = stats.PoolSwitches
+	rigStats.Uptime = stats.Uptime
+	for _, gpu := range stats.GPUs {
+		gpuStats = append(gpuStats, GPUStats{
+			Time:              rigStats.Time,
+			Location:          rigStats.Location,
+			Rig:               rigStats.Rig,
+			Worker:            rigStats.Worker,
+			GPU:               gpu.Index,
+			Hashrate:          gpu.Hashrate,
+			Temperature:       gpu.Temperature,
+			MemoryTemperature: gpu.MemoryTemperature,
+			FanSpeed:          gpu.FanSpeed,
+			Power:             gpu.Power,
+			AcceptedShares:    gpu.Shares.Accepted,
+			RejectedShares:    gpu.Shares.Rejected,
+			InvalidShares:     gpu.Shares.Invalid,
+		})
+	}
+	return
+}
+
+// addPoolHashrates sets the PoolHashrate and PoolAverageHashrate of every rig whose worker a watched pool account lists,
+// so the hashrate the pool sees can be compared with the one the rig reports. Pools are only asked for their workers
+// when a rig has a name
+func addPoolHashrates(ctx context.Context, accounts []poolAccount, rigStats []*RigStats) (err error) {
+	named := false
+	for _, rs := range rigStats {
+		named = named || rs.Worker != ""
+	}
+	if !named {
+		return
+	}
+	poolWorkers := make(map[string]pool.Worker)
+	for _, pa := range accounts {
+		workers, err := pa.Pool.Workers(ctx, pa.Account)
+		if errors.Is(err, pool.ErrUnsupported) {
+			continue
+		}
+		if err != nil {
+			log.Errorf("addPoolHashrates: Workers(%s, %s); returned err=%s\n", pa.Config.Label(), pa.Account, err.Error())
+			return err
+		}
+		for _, w := range workers {
+			if _, ok := poolWorkers[w.Name]; !ok {
+				poolWorkers[w.Name] = w
+			}
+		}
+	}
+	for _, rs := range rigStats {
+		if w, ok := poolWorkers[rs.Worker]; ok && rs.Worker != "" {
+			rs.PoolHashrate = &w.Hashrate
+			rs.PoolAverageHashrate = &w.AverageHashrate
+		}
+	}
+	return
+}
+
+// nanopoolAccountsByCoin groups the accounts watched on nanopool by the coin they mine, coins are in config order.
+// Only nanopool publishes network stats, accounts on other pools are left out
+func nanopoolAccountsByCoin(accounts []poolAccount) (coins []nanopool.Coin, coinAccounts map[nanopool.Coin][]poolAccount) {
+	coinAccounts = make(map[nanopool.Coin][]poolAccount)
+	for _, pa := range accounts {
+		np, ok := pa.Pool.(*nanopool.Pool)
+		if !ok {
+			continue
+		}
+		coin := np.Client().Coin()
+		if _, seen := coinAccounts[coin]; !seen {
+			coins = append(coins, coin)
+		}
+		coinAccounts[coin] = append(coinAccounts[coin], pa)
 	}
 	return
 }
 
-func collectNanopoolFinancialStats() (financialStats FinancialStats, err error) {
-	financialStats.Location = "nanopool"
-	nanoAPIRoot := viper.GetString("miningtools.nanopool.apiroot")
-	nanoAddress := viper.GetString("miningtools.nanopool.address")
-	mb, err := nanopool.GetMinerBalance(nanoAPIRoot, nanoAddress)
+// collectNetworkStats reads the pool and network stats of one coin from the nanopool client of the first of accounts,
+// Hashrate is the sum over every account so PoolShare is the share of the whole operation
+func collectNetworkStats(ctx context.Context, accounts []poolAccount) (networkStats NetworkStats, err error) {
+	client := accounts[0].Pool.(*nanopool.Pool).Client()
+	networkStats.Time = time.Now().UTC()
+	networkStats.Location = "nanopool"
+	networkStats.Coin = client.Coin().String()
+	var hashrate nanopool.Hashrate
+	for _, pa := range accounts {
+		hr, err := client.GetMinerHashrate(ctx, pa.Account)
+		if err != nil {
+			log.Errorf("collectNetworkStats: client.GetMinerHashrate(ctx, %s); returned err=%s\n", pa.Account, err.Error())
+			return networkStats, err
+		}
+		hashrate += hr.Data
+	}
+	networkStats.Hashrate = float64(hashrate)
+	phr, err := client.GetPoolHashrate(ctx)
 	if err != nil {
 		fmt.Println(err)
-		log.Errorf("collectFinancialStats: nanopool.GetMinerBalance(%s, %s); returned err=%s\n", nanoAPIRoot, nanoAddress, err.Error())
-		// TODO: handle error
+		log.Errorf("collectNetworkStats: client.GetPoolHashrate(ctx); returned err=%s\n", err.Error())
+		return
+	}
+	networkStats.PoolHashrate = float64(phr.Data)
+	if phr.Data > 0 {
+		networkStats.PoolShare = float64(hashrate / phr.Data)
+	}
+	am, err := client.GetPoolActiveMiners(ctx)
+	if err != nil {
+		fmt.Println(err)
+		log.Errorf("collectNetworkStats: client.GetPoolActiveMiners(ctx); returned err=%s\n", err.Error())
+		return
+	}
+	networkStats.ActiveMiners = am.Data
+	aw, err := client.GetPoolActiveWorkers(ctx)
+	if err != nil {
+		fmt.Println(err)
+		log.Errorf("collectNetworkStats: client.GetPoolActiveWorkers(ctx); returned err=%s\n", err.Error())
+		return
+	}
+	networkStats.ActiveWorkers = aw.Data
+	abt, err := client.GetNetworkAvgBlockTime(ctx)
+	if err != nil {
+		fmt.Println(err)
+		log.Errorf("collectNetworkStats: client.GetNetworkAvgBlockTime(ctx); returned err=%s\n", err.Error())
+		return
+	}
+	networkStats.AvgBlockTime = abt.Data
+	lbn, err := client.GetNetworkLastBlockNumber(ctx)
+	if err != nil {
+		fmt.Println(err)
+		log.Errorf("collectNetworkStats: client.GetNetworkLastBlockNumber(ctx); returned err=%s\n", err.Error())
 		return
 	}
-	bal := mb.Data
-	financialStats.BalanceETH = bal
-	p, err := nanopool.GetOtherPrices(nanoAPIRoot)
+	networkStats.LastBlockNumber = lbn.Data
+	return
+}
+
+// collectPoolFinancialStats values the confirmed balance of one pool account at the pool's own prices for its coin,
+// the balance is reported alone when the pool publishes no prices
+func collectPoolFinancialStats(ctx context.Context, pa poolAccount) (financialStats FinancialStats, err error) {
+	financialStats.Time = time.Now().UTC()
+	financialStats.Location = pa.Config.Type
+	financialStats.Pool = pa.Config.Label()
+	financialStats.Account = pa.Account
+	financialStats.Coin = pa.Pool.Coin()
+	balance, err := pa.Pool.Balance(ctx, pa.Account)
 	if err != nil {
 		fmt.Println(err)
-		log.Errorf("collectFinancialStats: nanopool.GetOtherPrices(%s, %s); returned err=%s\n", nanoAPIRoot, nanoAddress, err.Error())
+		log.Errorf("collectPoolFinancialStats: Balance(%s, %s); returned err=%s\n", financialStats.Pool, pa.Account, err.Error())
 		// TODO: handle error
 		return
 	}
-	financialStats.EthereumUSD = p.Data.PriceUSD
-	financialStats.BalanceUSD = p.Data.PriceUSD * bal
-	financialStats.BalanceBTC = p.Data.PriceBTC * bal
+	var prices pool.Prices
+	if reader, ok := pa.Pool.(pool.PriceReader); ok {
+		prices, err = reader.Prices(ctx, pa.Account)
+		if errors.Is(err, pool.ErrUnsupported) {
+			log.Debugf("collectPoolFinancialStats: pool %s has no prices for %s, leaving them out: %s\n", financialStats.Pool, pa.Account, err.Error())
+			prices, err = pool.Prices{}, nil
+		}
+		if err != nil {
+			fmt.Println(err)
+			log.Errorf("collectPoolFinancialStats: Prices(%s, %s); returned err=%s\n", financialStats.Pool, pa.Account, err.Error())
+			// TODO: handle error
+			return
+		}
+	}
+	setFinancialValues(&financialStats, balance.Confirmed, prices)
 	return
 }
 
 func collectWalletFinancialStats() (financialStats FinancialStats, err error) {
+	financialStats.Time = time.Now().UTC()
 	financialStats.Location = "wallet"
-	nanoAPIRoot := viper.GetString("miningtools.nanopool.apiroot")
+	// The wallet is an Ethereum account, so prices come from the ETH pool whatever coin is being mined
+	financialStats.Coin = nanopool.ETH.String()
+	client := newNanopoolCoinClient(nanopool.ETH)
+	nanoAPIRoot := client.APIRoot()
 	etherscanAPIRoot := viper.GetString("miningtools.etherscan.apiroot")
 	walletAddress := viper.GetString("miningtools.etherscan.address")
 	ab, err := getWalletBalance(etherscanAPIRoot, walletAddress)
@@ -257,20 +758,31 @@ func collectWalletFinancialStats() (financialStats FinancialStats, err error) {
 		return
 	}
 	bal := ab.Balance
-	financialStats.BalanceETH = bal
-	p, err := nanopool.GetOtherPrices(nanoAPIRoot)
+	financialStats.Balance = bal
+	prices, err := nanopool.NewPool(client).Prices(context.Background(), walletAddress)
 	if err != nil {
 		fmt.Println(err)
-		log.Errorf("collectFinancialStats: nanopool.GetOtherPrices(%s, %s); returned err=%s\n", nanoAPIRoot, walletAddress, err.Error())
+		log.Errorf("collectFinancialStats: nanopool.Prices(%s, %s); returned err=%s\n", nanoAPIRoot, walletAddress, err.Error())
 		// TODO: handle error
 		return
 	}
-	financialStats.EthereumUSD = p.Data.PriceUSD
-	financialStats.BalanceUSD = p.Data.PriceUSD * bal
-	financialStats.BalanceBTC = p.Data.PriceBTC * bal
+	setFinancialValues(&financialStats, bal, prices)
 	return
 }
 
+// setFinancialValues fills the price and the balance in each currency using exact arithmetic, leaving the values of
+// missing prices nil
+func setFinancialValues(financialStats *FinancialStats, balance *big.Rat, prices pool.Prices) {
+	financialStats.Balance = balance
+	financialStats.CoinUSD = prices.USD
+	if prices.USD != nil {
+		financialStats.BalanceUSD = new(big.Rat).Mul(prices.USD, balance)
+	}
+	if prices.BTC != nil {
+		financialStats.BalanceBTC = new(big.Rat).Mul(prices.BTC, balance)
+	}
+}
+
 func getLastTimeSeries(table string, metrics *Metrics) {
 	query := fmt.Sprintf("%s LATEST BY timestamp", table)
 	queryQuestDB("http://localhost:9000", query)
@@ -327,27 +839,6 @@ func queryQuestDB(apiRoot string, query string) (response QuestDBResponse, err e
 	return
 }
 
-func insertQuestDB(host string, payload []byte) {
-	conn, err := net.DialTimeout("tcp", host, time.Second*10)
-	if err != nil {
-		fmt.Println(err)
-		log.Errorf("insertQuestDB: net.DialTCP(tcp, nil, tcpAddr); returned err=%s\n", err.Error())
-		// TODO: handle error
-		return
-	}
-	defer conn.Close()
-
-	log.Debugf("insertQuestDB: Sending query to QuestDB - %s", payload)
-	_, err = conn.Write(payload)
-	if err != nil {
-		fmt.Println(err)
-		log.Errorf("insertQuestDB: conn.Write(payload); returned err=%s\n", err.Error())
-		// TODO: handle error
-		return
-	}
-	// QuestDB does not appear to respond.
-}
-
 func getWalletBalance(apiRoot string, address string) (accountBalance EtherscanAccountBalance, err error) {
 	log.Debugln("getWalletBalance called")
 	u, err := url.Parse(apiRoot)
@@ -386,16 +877,19 @@ func getWalletBalance(apiRoot string, address string) (accountBalance EtherscanA
 		return
 	}
 
-	if accountBalance.Status == "1" {
-		bal, _ := strconv.ParseFloat(accountBalance.Result, 64)
-		// The balance is provided as a whole number and must be divided to get the correct decimal
-		accountBalance.Balance = bal / 1000000000000000000
+	if accountBalance.Status != "1" {
+		err = fmt.Errorf("etherscan balance of %s failed: %s: %s", address, accountBalance.Message, accountBalance.Result)
+		log.Errorf("getWalletBalance: accountBalance.Status=%s; returned err=%s\n", accountBalance.Status, err.Error())
+		return
 	}
-	return
-}
-
-func floatToStringNoTrail(number float64) (noTrail string) {
-	noTrail = fmt.Sprintf("%.12f", number)
-	noTrail = strings.TrimRight(noTrail, "0")
+	// The balance is provided as a whole number of wei and must be divided to get ether
+	accountBalance.Wei, err = wei.Parse(accountBalance.Result)
+	if err != nil {
+		log.Errorf("getWalletBalance: wei.Parse(%s); returned err=%s\n", accountBalance.Result, err.Error())
+		return
+	}
+	accountBalance.Balance = wei.ToEther(accountBalance.Wei)
+	log.Debugf("getWalletBalance: %s holds %s ETH (%s gwei)\n",
+		address, wei.FormatEther(accountBalance.Wei), wei.FormatGwei(accountBalance.Wei))
 	return
 }
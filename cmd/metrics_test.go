@@ -0,0 +1,795 @@
+package miningtools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mining-tools/graphite"
+	"mining-tools/influxdb"
+	"mining-tools/lineprotocol"
+	"mining-tools/nanopool"
+	"mining-tools/nanopool/nanopooltest"
+	"mining-tools/pool"
+	"mining-tools/questdb"
+	"mining-tools/remotewrite"
+	"mining-tools/rig/rigtest"
+	"mining-tools/statsd"
+	"mining-tools/wei"
+
+	"github.com/golang/snappy"
+	"github.com/spf13/viper"
+)
+
+func Test_getWalletBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("address") {
+		case "0x01":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"123456789012345678901"}`)
+		case "0x02":
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+		default:
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"lots"}`)
+		}
+	}))
+	defer server.Close()
+	tests := []struct {
+		name      string
+		address   string
+		wantEther string
+		wantErr   bool
+	}{
+		{name: "Success01", address: "0x01", wantEther: "123.456789012345678901"},
+		{name: "NotOK01", address: "0x02", wantErr: true},
+		{name: "Invalid01", address: "0x03", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBalance, err := getWalletBalance(server.URL+"/api", tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getWalletBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := wei.FormatEther(gotBalance.Wei); got != tt.wantEther {
+				t.Errorf("getWalletBalance() = %v, want %v", got, tt.wantEther)
+			}
+		})
+	}
+}
+
+func Test_FinancialStatsPoint(t *testing.T) {
+	balance, _ := wei.Parse("123456789012345678901")
+	fs := FinancialStats{Time: time.Unix(1609459200, 0).UTC(), Location: "wallet", Coin: "eth"}
+	setFinancialValues(&fs, wei.ToEther(balance), pool.Prices{USD: big.NewRat(73051, 100), BTC: big.NewRat(251, 10000)})
+	line, err := fs.Point("financial").AppendLine(nil, lineprotocol.Nanosecond)
+	want := "financial,Location=wallet,Coin=eth EthereumUSD=730.51,BalanceETH=123.456789012345678901," +
+		"BalanceUSD=90186.41894140864189397,BalanceBTC=3.09876540420987654 1609459200000000000\n"
+	if err != nil || string(line) != want {
+		t.Errorf("FinancialStats.Point() = %q, %v, want %q", line, err, want)
+	}
+}
+
+func Test_RigStatsPoint(t *testing.T) {
+	poolHashrate := 95500000.0
+	rs := RigStats{
+		Time:           time.Unix(1609459200, 0).UTC(),
+		Location:       "trex",
+		Rig:            "rig 1",
+		Miner:          "t-rex-0.24.8",
+		Worker:         "rig1,a=b",
+		Hashrate:       100000000,
+		Power:          200,
+		AcceptedShares: 95,
+		Uptime:         time.Hour,
+		PoolHashrate:   &poolHashrate,
+	}
+	line, err := rs.Point("rig").AppendLine(nil, lineprotocol.Second)
+	want := `rig,Location=trex,Rig=rig\ 1,Miner=t-rex-0.24.8,Worker=rig1\,a\=b Hashrate=100000000,Power=200,AcceptedShares=95i,` +
+		"RejectedShares=0i,InvalidShares=0i,PoolSwitches=0i,Uptime=3600i,Efficiency=0.5,PoolHashrate=95500000 1609459200\n"
+	if err != nil || string(line) != want {
+		t.Errorf("RigStats.Point() = %q, %v, want %q", line, err, want)
+	}
+}
+
+// useFakeNanopool points the nanopool and etherscan config at local fake servers for the rest of the test
+func useFakeNanopool(t *testing.T, config nanopooltest.Config) *nanopooltest.Server {
+	t.Helper()
+	server := nanopooltest.NewServer(config)
+	t.Cleanup(server.Close)
+	etherscan := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"123456789012345678901"}`)
+	}))
+	t.Cleanup(etherscan.Close)
+	viper.Set("miningtools.nanopool.coin", "eth")
+	viper.Set("miningtools.nanopool.apiroot", server.APIRoot(nanopool.ETH))
+	viper.Set("miningtools.nanopool.address", nanopooltest.DefaultAddress)
+	viper.Set("miningtools.nanopool.requestsPerMinute", 0)
+	viper.Set("miningtools.nanopool.retries", 1)
+	viper.Set("miningtools.nanopool.timeout", time.Second)
+	viper.Set("miningtools.nanopool.cacheTTL", 0)
+	viper.Set("miningtools.pools", nil)
+	viper.Set("miningtools.rigs", nil)
+	viper.Set("miningtools.etherscan.apiroot", etherscan.URL+"/api")
+	viper.Set("miningtools.etherscan.address", "0x01")
+	return server
+}
+
+func Test_collectMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		pools    []map[string]interface{}
+		update   func(config *nanopooltest.Config)
+		want     []string
+		notWant  []string
+		timeout  time.Duration
+		wantErr  error
+		wantFail bool
+	}{
+		{
+			name: "Success01",
+			want: []string{
+				"pool,Location=nanopool,Pool=nanopool,Account=" + nanopooltest.DefaultAddress + " Balance=0.142,Shares=12 ",
+				"network,Location=nanopool,Coin=eth ",
+				"financial,Location=nanopool,Pool=nanopool,Account=" + nanopooltest.DefaultAddress + ",Coin=eth EthereumUSD=730.51,BalanceETH=0.142,",
+				"financial,Location=wallet,Coin=eth EthereumUSD=730.51,BalanceETH=123.456789012345678901,",
+			},
+		},
+		{
+			name: "MultiplePools01",
+			pools: []map[string]interface{}{
+				// the coin is tagged as the pool names it however the config spells it
+				{"name": "eth-main", "type": "nanopool", "coin": "ETH", "accounts": []string{nanopooltest.DefaultAddress, "0x02"}},
+			},
+			update: func(config *nanopooltest.Config) {
+				second := *config.Accounts[nanopooltest.DefaultAddress]
+				second.Balance = 500000000000 // 0.5
+				config.Accounts["0x02"] = &second
+			},
+			want: []string{
+				"pool,Location=nanopool,Pool=eth-main,Account=" + nanopooltest.DefaultAddress + " Balance=0.142,Shares=12 ",
+				"pool,Location=nanopool,Pool=eth-main,Account=0x02 Balance=0.5,Shares=12 ",
+				"financial,Location=nanopool,Pool=eth-main,Account=0x02,Coin=eth EthereumUSD=730.51,BalanceETH=0.5,",
+			},
+		},
+		{
+			name:     "UnknownPoolType01",
+			pools:    []map[string]interface{}{{"type": "missing", "accounts": []string{"0x01"}}},
+			wantFail: true,
+		},
+		{name: "AddressNotFound01", address: "0x02", wantErr: nanopool.ErrAddressNotFound},
+		{
+			name: "ShareRateGap01",
+			update: func(config *nanopooltest.Config) {
+				config.Accounts[nanopooltest.DefaultAddress].ShareRateGaps = []int64{0}
+			},
+			wantFail: true,
+		},
+		{
+			name: "RateLimited01",
+			update: func(config *nanopooltest.Config) {
+				config.StatusCodes = map[string]int{"prices/": http.StatusTooManyRequests}
+			},
+			wantErr: nanopool.ErrRateLimited,
+		},
+		{
+			name: "Timeout01",
+			update: func(config *nanopooltest.Config) {
+				config.Delays = map[string]time.Duration{"user/" + nanopooltest.DefaultAddress: 200 * time.Millisecond}
+			},
+			timeout:  50 * time.Millisecond,
+			wantFail: true,
+		},
+		{
+			name: "NetworkDown01",
+			update: func(config *nanopooltest.Config) {
+				config.StatusCodes = map[string]int{"pool/hashrate": http.StatusBadGateway}
+			},
+			want: []string{
+				"pool,Location=nanopool,Pool=nanopool,Account=" + nanopooltest.DefaultAddress + " Balance=0.142,Shares=12 ",
+				"financial,Location=wallet,Coin=eth EthereumUSD=730.51,BalanceETH=123.456789012345678901,",
+			},
+			notWant: []string{"network,"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := nanopooltest.DefaultConfig()
+			if tt.update != nil {
+				tt.update(&config)
+			}
+			useFakeNanopool(t, config)
+			if tt.address != "" {
+				viper.Set("miningtools.nanopool.address", tt.address)
+			}
+			if tt.pools != nil {
+				viper.Set("miningtools.pools", tt.pools)
+			}
+			if tt.timeout != 0 {
+				viper.Set("miningtools.nanopool.timeout", tt.timeout)
+			}
+			payload, err := collectMetrics()
+			if tt.wantErr != nil || tt.wantFail {
+				if err == nil {
+					t.Fatalf("collectMetrics() error = nil, want error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("collectMetrics() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("collectMetrics() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(payload), want) {
+					t.Errorf("collectMetrics() = %s, want it to contain %s", payload, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(string(payload), notWant) {
+					t.Errorf("collectMetrics() = %s, want it not to contain %s", payload, notWant)
+				}
+			}
+		})
+	}
+}
+
+func Test_collectMetricsEthermine(t *testing.T) {
+	now := time.Now().UTC().Truncate(10 * time.Minute).Unix()
+	ethermineServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/miner/0xE1/currentStats":
+			fmt.Fprint(w, `{"status":"OK","data":{"time":1609459200,"reportedHashrate":200400000,"currentHashrate":190500000,`+
+				`"averageHashrate":185000000,`+
+				`"validShares":190,"invalidShares":1,"staleShares":4,"unpaid":250000000000000000,"coinsPerMin":0.0000125,`+
+				`"usdPerMin":0.0091,"btcPerMin":0.0000003}}`)
+		case "/miner/0xE1/history":
+			fmt.Fprintf(w, `{"status":"OK","data":[{"time":%d,"validShares":31},{"time":%d,"validShares":32}]}`, now-600, now)
+		default:
+			fmt.Fprint(w, `{"status":"OK","data":"NO DATA"}`)
+		}
+	}))
+	defer ethermineServer.Close()
+	// without a nanopool account or a wallet nothing is asked of nanopool, which would fail on the closed server
+	nanopoolServer := httptest.NewServer(http.NotFoundHandler())
+	nanopoolServer.Close()
+	viper.Set("miningtools.nanopool.apiroot", nanopoolServer.URL+"/")
+	viper.Set("miningtools.nanopool.retries", 1)
+	viper.Set("miningtools.rigs", nil)
+	viper.Set("miningtools.etherscan.address", "")
+	viper.Set("miningtools.ethermine.requestsPerMinute", 0)
+	viper.Set("miningtools.ethermine.retries", 1)
+	viper.Set("miningtools.pools", []map[string]interface{}{
+		{"name": "ethermine", "type": "ethermine", "coin": "eth", "apiRoot": ethermineServer.URL + "/", "accounts": []string{"0xE1"}},
+	})
+	payload, err := collectMetrics()
+	if err != nil {
+		t.Fatalf("collectMetrics() error = %v", err)
+	}
+	for _, want := range []string{
+		// floats are written with every digit they have, 0.0000125 coins a minute is not exactly 0.018 a day
+		"pool,Location=ethermine,Pool=ethermine,Account=0xE1 Balance=0.25,Shares=32,ValidShares=190i,StaleShares=4i,InvalidShares=1i," +
+			"EstimatedDailyEarnings=0.018000000000000002,EffectiveHashrate=190500000,ReportedHashrate=200400000 ",
+		// the prices are derived from what the account earns a minute in coins, USD and BTC
+		"financial,Location=ethermine,Pool=ethermine,Account=0xE1,Coin=eth EthereumUSD=728,BalanceETH=0.25,BalanceUSD=182,BalanceBTC=0.006 ",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("collectMetrics() = %s, want it to contain %s", payload, want)
+		}
+	}
+	for _, notWant := range []string{"network,", "Location=wallet"} {
+		if strings.Contains(string(payload), notWant) {
+			t.Errorf("collectMetrics() = %s, want it not to contain %s", payload, notWant)
+		}
+	}
+}
+
+func Test_collectMetricsFlexpool(t *testing.T) {
+	now := time.Now().UTC().Truncate(10 * time.Minute).Unix()
+	flexpoolServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") != "0xF1" || r.URL.Query().Get("coin") != "eth" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"Invalid address","result":null}`)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/miner/balance":
+			fmt.Fprint(w, `{"error":null,"result":{"balance":142000000000000000,"balanceCountervalue":103.73,"price":730.51}}`)
+		case "/v2/miner/stats":
+			fmt.Fprint(w, `{"error":null,"result":{"reportedHashrate":200400000,"currentEffectiveHashrate":190500000,`+
+				`"averageEffectiveHashrate":185000000,"validShares":4500,"staleShares":45,"invalidShares":2}}`)
+		case "/v2/miner/chart":
+			fmt.Fprintf(w, `{"error":null,"result":[{"timestamp":%d,"validShares":32},{"timestamp":%d,"validShares":31}]}`, now, now-600)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Not found","result":null}`)
+		}
+	}))
+	defer flexpoolServer.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.flexpool.requestsPerMinute", 0)
+	viper.Set("miningtools.flexpool.retries", 1)
+	viper.Set("miningtools.pools", []map[string]interface{}{
+		{"type": "flexpool", "coin": "eth", "apiRoot": flexpoolServer.URL + "/v2/", "accounts": []string{"0xF1"}},
+	})
+	payload, err := collectMetrics()
+	if err != nil {
+		t.Fatalf("collectMetrics() error = %v", err)
+	}
+	for _, want := range []string{
+		"pool,Location=flexpool,Pool=flexpool,Account=0xF1 Balance=0.142,Shares=32,ValidShares=4500i,StaleShares=45i,InvalidShares=2i," +
+			"EffectiveHashrate=190500000,ReportedHashrate=200400000 ",
+		// flexpool publishes no BTC price
+		"financial,Location=flexpool,Pool=flexpool,Account=0xF1,Coin=eth EthereumUSD=730.51,BalanceETH=0.142,BalanceUSD=103.73242 ",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("collectMetrics() = %s, want it to contain %s", payload, want)
+		}
+	}
+	viper.Set("miningtools.pools", []map[string]interface{}{
+		{"type": "flexpool", "apiRoot": flexpoolServer.URL + "/v2/", "accounts": []string{"0xF2"}},
+	})
+	if _, err = collectMetrics(); !errors.Is(err, pool.ErrAccountNotFound) {
+		t.Errorf("collectMetrics() error = %v, want %v", err, pool.ErrAccountNotFound)
+	}
+}
+
+func Test_collectMetricsOpenEthPool(t *testing.T) {
+	twoMiners := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/accounts/0xA1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"currentHashrate":190500000,"hashrate":185000000,"stats":{"balance":142000000,"immature":1500000},`+
+			`"workers":{"rig1":{"lastBeat":1609459200,"hr":190500000,"hr2":185000000}},"workersOnline":1,"workersOffline":2,`+
+			`"24hreward":9000000}`)
+	}))
+	defer twoMiners.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.openethpool.requestsPerMinute", 0)
+	viper.Set("miningtools.openethpool.retries", 1)
+	viper.Set("miningtools.pools", []map[string]interface{}{
+		{"name": "2miners", "type": "openethpool", "coin": "eth", "apiRoot": twoMiners.URL + "/api/", "accounts": []string{"0xA1"}},
+	})
+	payload, err := collectMetrics()
+	if err != nil {
+		t.Fatalf("collectMetrics() error = %v", err)
+	}
+	for _, want := range []string{
+		"pool,Location=openethpool,Pool=2miners,Account=0xA1 Balance=0.142,WorkersOnline=1i,WorkersOffline=2i,Reward24h=0.009 ",
+		// open-ethereum-pool publishes no prices, the balance is still reported
+		"financial,Location=openethpool,Pool=2miners,Account=0xA1,Coin=eth BalanceETH=0.142 ",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("collectMetrics() = %s, want it to contain %s", payload, want)
+		}
+	}
+	viper.Set("miningtools.pools", []map[string]interface{}{
+		{"type": "openethpool", "apiRoot": twoMiners.URL + "/api/", "accounts": []string{"0xA2"}},
+	})
+	if _, err = collectMetrics(); !errors.Is(err, pool.ErrAccountNotFound) {
+		t.Errorf("collectMetrics() error = %v, want %v", err, pool.ErrAccountNotFound)
+	}
+	// Ravencoin amounts are kept in its 8 decimal smallest unit rather than Shannon
+	viper.Set("miningtools.pools", []map[string]interface{}{
+		{"name": "2miners-rvn", "type": "openethpool", "coin": "rvn", "apiRoot": twoMiners.URL + "/api/", "accounts": []string{"0xA1"}},
+	})
+	payload, err = collectMetrics()
+	if want := "pool,Location=openethpool,Pool=2miners-rvn,Account=0xA1 Balance=1.42,"; err != nil || !strings.Contains(string(payload), want) {
+		t.Errorf("collectMetrics() = %s, %v, want it to contain %s", payload, err, want)
+	}
+	viper.Set("miningtools.pools", []map[string]interface{}{
+		{"type": "openethpool", "coin": "xmr", "apiRoot": twoMiners.URL + "/api/", "accounts": []string{"0xA1"}},
+	})
+	if _, err = collectMetrics(); err == nil {
+		t.Errorf("collectMetrics() error = nil, want an error for a coin open-ethereum-pool does not serve")
+	}
+}
+
+func Test_collectMetricsEthminer(t *testing.T) {
+	rig1 := rigtest.NewServer(func(request []byte) []byte {
+		return []byte(`{"id":1,"jsonrpc":"2.0","result":{"connection":{"connected":true,"switches":1},` +
+			`"devices":[{"_index":0,"hardware":{"name":"GeForce GTX 1070","sensors":[61,55,120]},` +
+			`"mining":{"hashrate":"0x0000000001c9c380","shares":[95,1,0,12]}}],` +
+			`"host":{"name":"rig1","runtime":3600,"version":"ethminer-0.19.0"},` +
+			`"mining":{"hashrate":"0x0000000001c9c380","shares":[95,1,0,12]}}}` + "\n")
+	})
+	defer rig1.Close()
+	down := rigtest.NewServer(nil)
+	down.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.ethminer.timeout", time.Second)
+	viper.Set("miningtools.rigs", []map[string]interface{}{
+		{"name": "rig1", "type": "ethminer", "address": rig1.Addr},
+		{"name": "rig2", "type": "ethminer", "address": down.Addr},
+	})
+	payload, err := collectMetrics()
+	if err != nil {
+		t.Fatalf("collectMetrics() error = %v", err)
+	}
+	for _, want := range []string{
+		"rig,Location=ethminer,Rig=rig1,Miner=ethminer-0.19.0 Hashrate=30000000,Power=120,AcceptedShares=95i,RejectedShares=1i,InvalidShares=0i," +
+			"PoolSwitches=1i,Uptime=3600i,Efficiency=0.25 ",
+		"gpu,Location=ethminer,Rig=rig1,GPU=0 Hashrate=30000000,Temperature=61,MemoryTemperature=0,FanSpeed=55,Power=120," +
+			"AcceptedShares=95i,RejectedShares=1i,InvalidShares=0i,Efficiency=0.25 ",
+		"pool,Location=nanopool,Pool=nanopool,",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("collectMetrics() = %s, want it to contain %s", payload, want)
+		}
+	}
+	if strings.Contains(string(payload), "Rig=rig2") {
+		t.Errorf("collectMetrics() = %s, want the unreachable rig2 left out", payload)
+	}
+	viper.Set("miningtools.rigs", []map[string]interface{}{{"type": "bfgminer", "address": rig1.Addr}})
+	if _, err = collectMetrics(); err == nil {
+		t.Errorf("collectMetrics() error = nil, want an unknown rig type error")
+	}
+}
+
+func Test_collectMetricsClaymore(t *testing.T) {
+	// Claymore answers without a line ending and closes the connection
+	rig1 := rigtest.NewOneShotServer(func(request []byte) []byte {
+		return []byte(`{"id": 0, "result": ["15.0 - ETH", "90", "60000;120;1", "30000;off", "0;0;0", "off;off", "60;50;0;0", ` +
+			`"eth-eu1.nanopool.org:9999", "2;3;0;0", "118;0", "1;0", "2;0", "0;0", "0;0", "0;0", "1;2"], "error": null}`)
+	})
+	defer rig1.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.claymore.timeout", time.Second)
+	viper.Set("miningtools.rigs", []map[string]interface{}{
+		{"name": "rig1", "type": "claymore", "address": rig1.Addr, "password": "secret"},
+	})
+	payload, err := collectMetrics()
+	if err != nil {
+		t.Fatalf("collectMetrics() error = %v", err)
+	}
+	for _, want := range []string{
+		"rig,Location=claymore,Rig=rig1,Miner=claymore-15.0 Hashrate=60000000,Power=0,AcceptedShares=120i,RejectedShares=1i,InvalidShares=2i," +
+			"PoolSwitches=3i,Uptime=5400i ",
+		"gpu,Location=claymore,Rig=rig1,GPU=0 Hashrate=30000000,Temperature=60,MemoryTemperature=0,FanSpeed=50,Power=0," +
+			"AcceptedShares=118i,RejectedShares=1i,InvalidShares=2i ",
+		"gpu,Location=claymore,Rig=rig1,GPU=1 Hashrate=0,Temperature=0,MemoryTemperature=0,FanSpeed=0,Power=0," +
+			"AcceptedShares=0i,RejectedShares=0i,InvalidShares=0i ",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("collectMetrics() = %s, want it to contain %s", payload, want)
+		}
+	}
+	if requests := rig1.Requests(); len(requests) != 1 || !strings.Contains(string(requests[0]), `"psw":"secret"`) {
+		t.Errorf("rig1 received %q, want a single request with the password", requests)
+	}
+}
+
+func Test_collectMetricsSgminer(t *testing.T) {
+	// TeamRedMiner speaks the cgminer socket API, every command is a connection of its own
+	replies := map[string]string{
+		"summary": `{"STATUS":[{"STATUS":"S","Description":"TeamRedMiner 0.8.1"}],"SUMMARY":[{"Elapsed":60,"MHS 30s":60,` +
+			`"Accepted":10,"Rejected":1,"Hardware Errors":0}],"id":1}`,
+		"devs": `{"STATUS":[{"STATUS":"S","Description":"TeamRedMiner 0.8.1"}],"DEVS":[{"GPU":0,"Temperature":66,` +
+			`"Fan Percent":40,"MHS 30s":30,"Accepted":5},{"GPU":1,"Temperature":68,"Fan Percent":45,"MHS 30s":30,"Accepted":5,"Rejected":1}],"id":1}`,
+		"pools": `{"STATUS":[{"STATUS":"S","Description":"TeamRedMiner 0.8.1"}],"POOLS":[{"POOL":0,"Status":"Alive",` +
+			`"User":"0x01.rig2","Stratum Active":true}],"id":1}`,
+	}
+	amd3 := rigtest.NewOneShotServer(func(request []byte) []byte {
+		var r struct{ Command string }
+		json.Unmarshal(request, &r)
+		return []byte(replies[r.Command] + "\x00")
+	})
+	defer amd3.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.sgminer.timeout", time.Second)
+	viper.Set("miningtools.rigs", []map[string]interface{}{
+		{"name": "amd3", "type": "teamredminer", "address": amd3.Addr},
+	})
+	payload, err := collectMetrics()
+	if err != nil {
+		t.Fatalf("collectMetrics() error = %v", err)
+	}
+	for _, want := range []string{
+		"rig,Location=teamredminer,Rig=amd3,Miner=teamredminer-0.8.1,Worker=rig2 Hashrate=60000000,Power=0,AcceptedShares=10i," +
+			"RejectedShares=1i,InvalidShares=0i,PoolSwitches=0i,Uptime=60i,PoolHashrate=95000000,PoolAverageHashrate=95000000 ",
+		"gpu,Location=teamredminer,Rig=amd3,Worker=rig2,GPU=0 Hashrate=30000000,Temperature=66,MemoryTemperature=0,FanSpeed=40,Power=0," +
+			"AcceptedShares=5i,RejectedShares=0i,InvalidShares=0i ",
+		"gpu,Location=teamredminer,Rig=amd3,Worker=rig2,GPU=1 Hashrate=30000000,Temperature=68,MemoryTemperature=0,FanSpeed=45,Power=0," +
+			"AcceptedShares=5i,RejectedShares=1i,InvalidShares=0i ",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("collectMetrics() = %s, want it to contain %s", payload, want)
+		}
+	}
+	if requests := amd3.Requests(); len(requests) != 3 {
+		t.Errorf("amd3 received %q, want the summary, devs and pools commands", requests)
+	}
+}
+
+func Test_collectMetricsHTTPRigs(t *testing.T) {
+	trexServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"accepted_count":95,"rejected_count":1,"invalid_count":0,"active_pool":{"worker":"rig1"},"gpus":[`+
+			`{"device_id":0,"hashrate":100000000,"memory_temperature":80,"power":200,"temperature":61,"fan_speed":55,`+
+			`"shares":{"accepted_count":95,"rejected_count":1}}],"hashrate":100000000,"uptime":3600,"version":"0.24.8"}`)
+	}))
+	defer trexServer.Close()
+	lolminerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/summary" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"Software":"lolMiner 1.42","Session":{"Uptime":60,"Performance_Summary":30,"Performance_Unit":"mh/s",`+
+			`"Accepted":10,"Submitted":10,"TotalPower":120},"Stratum":{"Current_User":"0x01.rig9"},"GPUs":[{"Index":0,"Performance":30,`+
+			`"Consumption (W)":120,"Fan Speed (%)":60,"Temp (deg C)":65,"Mem Temp (deg C)":82,"Session_Accepted":10,"Session_Submitted":10}]}`)
+	}))
+	defer lolminerServer.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.rigs", []map[string]interface{}{
+		{"name": "nvidia1", "type": "trex", "address": strings.TrimPrefix(trexServer.URL, "http://")},
+		{"name": "amd1", "type": "lolminer", "address": strings.TrimPrefix(lolminerServer.URL, "http://"), "worker": "rig2"},
+		{"name": "amd2", "type": "lolminer", "address": strings.TrimPrefix(lolminerServer.URL, "http://")},
+	})
+	payload, err := collectMetrics()
+	if err != nil {
+		t.Fatalf("collectMetrics() error = %v", err)
+	}
+	for _, want := range []string{
+		// the fake nanopool reports hashrates in MH/s, they are H/s once in the pool model
+		"rig,Location=trex,Rig=nvidia1,Miner=t-rex-0.24.8,Worker=rig1 Hashrate=100000000,Power=200,AcceptedShares=95i,RejectedShares=1i," +
+			"InvalidShares=0i,PoolSwitches=0i,Uptime=3600i,Efficiency=0.5,PoolHashrate=95500000,PoolAverageHashrate=95500000 ",
+		"gpu,Location=trex,Rig=nvidia1,Worker=rig1,GPU=0 Hashrate=100000000,Temperature=61,MemoryTemperature=80,FanSpeed=55,Power=200,",
+		"rig,Location=lolminer,Rig=amd1,Miner=lolminer-1.42,Worker=rig2 Hashrate=30000000,Power=120,AcceptedShares=10i,RejectedShares=0i," +
+			"InvalidShares=0i,PoolSwitches=0i,Uptime=60i,Efficiency=0.25,PoolHashrate=95000000,PoolAverageHashrate=95000000 ",
+		"rig,Location=lolminer,Rig=amd2,Miner=lolminer-1.42,Worker=rig9 Hashrate=30000000,Power=120,AcceptedShares=10i,RejectedShares=0i," +
+			"InvalidShares=0i,PoolSwitches=0i,Uptime=60i,Efficiency=0.25 ",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("collectMetrics() = %s, want it to contain %s", payload, want)
+		}
+	}
+}
+
+func Test_writeMetrics(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		payload, _ := ioutil.ReadAll(conn)
+		received <- payload
+	}()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.timeseriesDB.protocol", "QuestDB")
+	viper.Set("miningtools.timeseriesDB.address", listener.Addr().String())
+	defer viper.Set("miningtools.timeseriesDB.address", nil)
+	if err = writeMetrics(context.Background()); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+	select {
+	case payload := <-received:
+		want := "pool,Location=nanopool,Pool=nanopool,Account=0x0000000000000000000000000000000000000001 Balance=0.142,Shares=12 "
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("writeMetrics() sent %s, want it to contain %s", payload, want)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("writeMetrics() sent nothing to the configured address")
+	}
+
+	viper.Set("miningtools.timeseriesDB.protocol", "carbon")
+	defer viper.Set("miningtools.timeseriesDB.protocol", nil)
+	if err = writeMetrics(context.Background()); err == nil {
+		t.Errorf("writeMetrics() error = nil, want an unknown protocol error")
+	}
+}
+
+func Test_openSinkDefaultAddress(t *testing.T) {
+	if got := rootCmd.PersistentFlags().Lookup("timeseriesDB").DefValue; got != "" {
+		t.Fatalf("--timeseriesDB default = %q, want none so each protocol picks its own", got)
+	}
+	tests := []struct {
+		protocol string
+		want     string
+	}{
+		{protocol: "InfluxDB", want: questdb.DefaultAddress},
+		{protocol: "InfluxDBv2", want: influxdb.DefaultURL},
+		{protocol: "RemoteWrite", want: remotewrite.DefaultURL},
+		{protocol: "Graphite", want: graphite.DefaultAddress},
+		{protocol: "StatsD", want: statsd.DefaultAddress},
+	}
+	viper.Set("miningtools.timeseriesDB.address", "")
+	defer viper.Set("miningtools.timeseriesDB.address", nil)
+	defer viper.Set("miningtools.timeseriesDB.protocol", nil)
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			viper.Set("miningtools.timeseriesDB.protocol", tt.protocol)
+			s, err := openSink()
+			if err != nil {
+				t.Fatalf("openSink() error = %v", err)
+			}
+			var got string
+			switch s := s.(type) {
+			case *questdb.Sink:
+				got = s.Address()
+			case *influxdb.Sink:
+				got = s.URL()
+			case *remotewrite.Sink:
+				got = s.URL()
+			case *graphite.Sink:
+				got = s.Address()
+			case *statsd.Sink:
+				got = s.Address()
+			}
+			if got != tt.want {
+				t.Errorf("openSink() address = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeMetricsInfluxDBv2(t *testing.T) {
+	var mu sync.Mutex
+	var writes []*http.Request
+	var payload []byte
+	influx := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		writes = append(writes, r)
+		payload = append(payload, body...)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer influx.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.timeseriesDB.protocol", "InfluxDBv2")
+	viper.Set("miningtools.timeseriesDB.address", strings.TrimPrefix(influx.URL, "http://"))
+	viper.Set("miningtools.timeseriesDB.org", "mining")
+	viper.Set("miningtools.timeseriesDB.bucket", "metrics")
+	viper.Set("miningtools.timeseriesDB.token", "secret")
+	viper.Set("miningtools.timeseriesDB.precision", "ms")
+	viper.Set("miningtools.timeseriesDB.batchSize", 2)
+	defer func() {
+		for _, key := range []string{"protocol", "address", "org", "bucket", "token", "precision", "batchSize"} {
+			viper.Set("miningtools.timeseriesDB."+key, nil)
+		}
+	}()
+	if err := writeMetrics(context.Background()); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+	// a pool, network and two financial points make two batches of two
+	if len(writes) != 2 || writes[0].URL.Query().Get("precision") != "ms" || writes[0].Header.Get("Authorization") != "Token secret" {
+		t.Errorf("writeMetrics() made %d writes, want 2 batches in milliseconds with the token", len(writes))
+	}
+	points, err := lineprotocol.ParseLines(payload, lineprotocol.Millisecond)
+	if err != nil || len(points) != 4 || points[0].Measurement != "pool" {
+		t.Errorf("writeMetrics() sent %q, want 4 points starting with the pool", payload)
+	}
+}
+
+func Test_writeMetricsRemoteWrite(t *testing.T) {
+	var mu sync.Mutex
+	var writes []*http.Request
+	var payload []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		writes = append(writes, r)
+		payload, _ = snappy.Decode(nil, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.timeseriesDB.protocol", "RemoteWrite")
+	viper.Set("miningtools.timeseriesDB.address", receiver.URL+"/api/v1/write")
+	viper.Set("miningtools.timeseriesDB.token", "1234:secret")
+	viper.Set("miningtools.timeseriesDB.labels", map[string]interface{}{"Location": "pool_type"})
+	defer func() {
+		for _, key := range []string{"protocol", "address", "token", "labels"} {
+			viper.Set("miningtools.timeseriesDB."+key, nil)
+		}
+	}()
+	if err := writeMetrics(context.Background()); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+	if len(writes) != 1 || writes[0].Header.Get("Content-Encoding") != "snappy" {
+		t.Fatalf("writeMetrics() made %d writes, want 1 snappy compressed write", len(writes))
+	}
+	if user, password, _ := writes[0].BasicAuth(); user != "1234" || password != "secret" {
+		t.Errorf("writeMetrics() authenticated as %s:%s, want 1234:secret", user, password)
+	}
+	for _, want := range []string{"mining_pool_balance", "mining_financial_balance_usd", "pool_type", "nanopool"} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("writeMetrics() sent %q, want it to contain %s", payload, want)
+		}
+	}
+	if strings.Contains(string(payload), "location") {
+		t.Errorf("writeMetrics() sent %q, want the Location tag renamed to pool_type", payload)
+	}
+}
+
+func Test_writeMetricsGraphite(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		payload, _ := ioutil.ReadAll(conn)
+		received <- payload
+	}()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.timeseriesDB.protocol", "Graphite")
+	viper.Set("miningtools.timeseriesDB.address", listener.Addr().String())
+	viper.Set("miningtools.timeseriesDB.template", "mining.{location}.{measurement}.{field}")
+	defer func() {
+		for _, key := range []string{"protocol", "address", "template"} {
+			viper.Set("miningtools.timeseriesDB."+key, nil)
+		}
+	}()
+	if err = writeMetrics(context.Background()); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+	select {
+	case payload := <-received:
+		for _, want := range []string{"mining.nanopool.pool.Balance 0.142 ", "mining.wallet.financial.BalanceETH 123.45678901234568 "} {
+			if !strings.Contains(string(payload), want) {
+				t.Errorf("writeMetrics() sent %s, want it to contain %s", payload, want)
+			}
+		}
+	case <-time.After(time.Second):
+		t.Errorf("writeMetrics() sent nothing to the configured address")
+	}
+
+	viper.Set("miningtools.timeseriesDB.template", "mining.{location}")
+	if err = writeMetrics(context.Background()); err == nil {
+		t.Errorf("writeMetrics() error = nil, want an invalid template error")
+	}
+}
+
+func Test_writeMetricsStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.ListenPacket() error = %v", err)
+	}
+	defer conn.Close()
+	useFakeNanopool(t, nanopooltest.DefaultConfig())
+	viper.Set("miningtools.timeseriesDB.protocol", "StatsD")
+	viper.Set("miningtools.timeseriesDB.address", conn.LocalAddr().String())
+	defer func() {
+		for _, key := range []string{"protocol", "address"} {
+			viper.Set("miningtools.timeseriesDB."+key, nil)
+		}
+	}()
+	if err = writeMetrics(context.Background()); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 65536)
+	n, _, err := conn.ReadFrom(buf)
+	if want := "mining.pool.nanopool.nanopool.0x0000000000000000000000000000000000000001.Balance:0.142|g\n"; err != nil || !strings.HasPrefix(string(buf[:n]), want) {
+		t.Errorf("writeMetrics() sent %q, %v, want it to start with %q", buf[:n], err, want)
+	}
+}
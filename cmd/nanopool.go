@@ -18,11 +18,26 @@ package miningtools
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
+	"mining-tools/nanopool"
+	"mining-tools/throttle"
+
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var (
+	// nanopoolLimiter is shared by every nanopool client so that together they stay within nanopool's per-IP budget
+	nanopoolLimiter     *throttle.Limiter
+	nanopoolLimiterOnce sync.Once
+	// nanopoolCache is shared by every nanopool client so each upstream request is made once per run
+	nanopoolCache     *nanopool.Cache
+	nanopoolCacheOnce sync.Once
+)
+
 // nanopoolCmd represents the nanopool command
 var nanopoolCmd = &cobra.Command{
 	Use:   "nanopool",
@@ -48,10 +63,92 @@ func init() {
 	// nanopoolCmd.PersistentFlags().String("foo", "", "A help for foo")
 	nanopoolCmd.PersistentFlags().String("address", "", "miner account")
 	viper.BindPFlag("miningtools.nanopool.address", nanopoolCmd.PersistentFlags().Lookup("address"))
-	nanopoolCmd.PersistentFlags().String("apiRoot", "https://api.nanopool.org/v1/eth/", "base URL for nanopool ethereum API")
+	nanopoolCmd.PersistentFlags().String("coin", "eth", "nanopool coin, one of eth, etc, rvn, ergo, xmr, zec or cfx (Default: eth)")
+	viper.BindPFlag("miningtools.nanopool.coin", nanopoolCmd.PersistentFlags().Lookup("coin"))
+	nanopoolCmd.PersistentFlags().String("apiRoot", "", "base URL for the nanopool API of the coin, overrides --coin when building URLs (Default: https://api.nanopool.org/v1/<coin>/)")
 	viper.BindPFlag("miningtools.nanopool.apiRoot", nanopoolCmd.PersistentFlags().Lookup("apiRoot"))
+	viper.SetDefault("miningtools.nanopool.requestsPerMinute", 30)
+	viper.SetDefault("miningtools.nanopool.retries", throttle.DefaultPolicy.MaxAttempts)
+	viper.SetDefault("miningtools.nanopool.timeout", nanopool.DefaultTimeout)
+	viper.SetDefault("miningtools.nanopool.cacheTTL", time.Minute)
+	viper.SetDefault("miningtools.nanopool.cacheFile", "")
+	// A persisted cache only helps if its entries outlive the gap between runs, metrics is usually run every 5 to 10
+	// minutes from cron
+	viper.SetDefault("miningtools.nanopool.cacheFileTTL", 10*time.Minute)
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// nanopoolCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// newNanopoolClient builds a nanopool.Client for the coin configured in miningtools.nanopool.coin
+func newNanopoolClient() (client *nanopool.Client, err error) {
+	coin, err := nanopool.ParseCoin(viper.GetString("miningtools.nanopool.coin"))
+	if err != nil {
+		return
+	}
+	client = newNanopoolCoinClient(coin)
+	return
+}
+
+// newNanopoolCoinClient builds a nanopool.Client for coin, miningtools.nanopool.apiRoot is only honored for the configured coin.
+// extra options are applied last so they can override the config
+func newNanopoolCoinClient(coin nanopool.Coin, extra ...nanopool.Option) *nanopool.Client {
+	nanopoolLimiterOnce.Do(func() {
+		rpm := viper.GetInt("miningtools.nanopool.requestsPerMinute")
+		// Allow ten seconds worth of requests in a burst so a metrics run is not spread out needlessly
+		nanopoolLimiter = throttle.NewLimiter(rpm, rpm/6+1)
+	})
+	nanopoolCacheOnce.Do(loadNanopoolCache)
+	retry := throttle.DefaultPolicy
+	retry.MaxAttempts = viper.GetInt("miningtools.nanopool.retries")
+	options := []nanopool.Option{
+		nanopool.WithCoin(coin),
+		nanopool.WithRateLimiter(nanopoolLimiter),
+		nanopool.WithRetryPolicy(retry),
+		nanopool.WithCache(nanopoolCache),
+		nanopool.WithTimeout(viper.GetDuration("miningtools.nanopool.timeout")),
+	}
+	configured, _ := nanopool.ParseCoin(viper.GetString("miningtools.nanopool.coin"))
+	if apiRoot := viper.GetString("miningtools.nanopool.apiroot"); apiRoot != "" && coin == configured {
+		options = append(options, nanopool.WithAPIRoot(apiRoot))
+	}
+	return nanopool.NewClient(append(options, extra...)...)
+}
+
+// nanopoolAddress returns the miner account for coin, miningtools.nanopool.addresses.<coin> lets one config hold an
+// account per coin and falls back to miningtools.nanopool.address
+func nanopoolAddress(coin nanopool.Coin) string {
+	if address := viper.GetString("miningtools.nanopool.addresses." + coin.String()); address != "" {
+		return address
+	}
+	return viper.GetString("miningtools.nanopool.address")
+}
+
+// loadNanopoolCache sets up nanopoolCache, reading responses saved by a previous run from miningtools.nanopool.cacheFile if set.
+// Responses are kept for miningtools.nanopool.cacheTTL within a run, or miningtools.nanopool.cacheFileTTL when persisted
+func loadNanopoolCache() {
+	ttl := viper.GetDuration("miningtools.nanopool.cacheTTL")
+	cacheFile := viper.GetString("miningtools.nanopool.cacheFile")
+	if cacheFile == "" {
+		nanopoolCache = nanopool.NewCache(ttl)
+		return
+	}
+	ttl = viper.GetDuration("miningtools.nanopool.cacheFileTTL")
+	cache, err := nanopool.LoadCache(cacheFile, ttl)
+	if err != nil {
+		log.Warnf("loadNanopoolCache: nanopool.LoadCache(%s, %s); returned err=%s\n", cacheFile, ttl, err.Error())
+	}
+	nanopoolCache = cache
+}
+
+// saveNanopoolCache writes nanopoolCache to miningtools.nanopool.cacheFile, if both exist, for the next run to reuse
+func saveNanopoolCache() {
+	cacheFile := viper.GetString("miningtools.nanopool.cacheFile")
+	if nanopoolCache == nil || cacheFile == "" {
+		return
+	}
+	if err := nanopoolCache.Save(cacheFile); err != nil {
+		log.Errorf("saveNanopoolCache: nanopoolCache.Save(%s); returned err=%s\n", cacheFile, err.Error())
+	}
+}
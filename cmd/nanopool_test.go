@@ -0,0 +1,39 @@
+package miningtools
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func Test_loadNanopoolCache(t *testing.T) {
+	saved := nanopoolCache
+	defer func() {
+		nanopoolCache = saved
+		for _, key := range []string{"cacheTTL", "cacheFile", "cacheFileTTL"} {
+			viper.Set("miningtools.nanopool."+key, nil)
+		}
+	}()
+	cacheFile := filepath.Join(t.TempDir(), "nanopool.json")
+	viper.Set("miningtools.nanopool.cacheTTL", 0)
+	viper.Set("miningtools.nanopool.cacheFile", cacheFile)
+	viper.Set("miningtools.nanopool.cacheFileTTL", time.Hour)
+	loadNanopoolCache()
+	nanopoolCache.Set("https://api.nanopool.org/v1/eth/balance/0x01", []byte(`{"status":true,"data":0.142}`))
+	saveNanopoolCache()
+
+	// the next run must find the response although cacheTTL alone would have dropped it
+	nanopoolCache = nil
+	loadNanopoolCache()
+	if _, ok := nanopoolCache.Get("https://api.nanopool.org/v1/eth/balance/0x01"); !ok {
+		t.Errorf("loadNanopoolCache() lost the response saved by the previous run")
+	}
+	viper.Set("miningtools.nanopool.cacheFile", "")
+	loadNanopoolCache()
+	nanopoolCache.Set("https://api.nanopool.org/v1/eth/balance/0x01", []byte(`{"status":true,"data":0.142}`))
+	if _, ok := nanopoolCache.Get("https://api.nanopool.org/v1/eth/balance/0x01"); ok {
+		t.Errorf("loadNanopoolCache() cached a response with cacheTTL 0 and no cacheFile")
+	}
+}
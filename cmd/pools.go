@@ -0,0 +1,188 @@
+/*
+Package miningtools contains the various supported CLI commands for mining-tools
+Copyright © 2020 Keith Olenchak <kenjin.domini@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miningtools
+
+import (
+	"sync"
+
+	"mining-tools/ethermine"
+	"mining-tools/flexpool"
+	"mining-tools/nanopool"
+	"mining-tools/openethpool"
+	"mining-tools/pool"
+	"mining-tools/throttle"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// poolAccount is one account to watch on one configured pool
+type poolAccount struct {
+	Pool    pool.Pool
+	Config  pool.Config
+	Account string
+}
+
+var (
+	ethermineLimiter     *throttle.Limiter
+	ethermineLimiterOnce sync.Once
+	flexpoolLimiter      *throttle.Limiter
+	flexpoolLimiterOnce  sync.Once
+)
+
+func init() {
+	pool.Register("nanopool", newNanopoolPool)
+	pool.Register("ethermine", newEtherminePool)
+	pool.Register("openethpool", newOpenEthPool)
+	pool.Register("flexpool", newFlexpoolPool)
+	// Ethermine allows 100 requests every 15 minutes from one IP
+	viper.SetDefault("miningtools.ethermine.requestsPerMinute", 6)
+	viper.SetDefault("miningtools.ethermine.retries", throttle.DefaultPolicy.MaxAttempts)
+	viper.SetDefault("miningtools.ethermine.timeout", ethermine.DefaultTimeout)
+	viper.SetDefault("miningtools.openethpool.requestsPerMinute", 30)
+	viper.SetDefault("miningtools.openethpool.retries", throttle.DefaultPolicy.MaxAttempts)
+	viper.SetDefault("miningtools.openethpool.timeout", openethpool.DefaultTimeout)
+	viper.SetDefault("miningtools.flexpool.requestsPerMinute", 30)
+	viper.SetDefault("miningtools.flexpool.retries", throttle.DefaultPolicy.MaxAttempts)
+	viper.SetDefault("miningtools.flexpool.timeout", flexpool.DefaultTimeout)
+}
+
+// newNanopoolPool builds a nanopool pool.Pool sharing the rate limit and cache of every other nanopool client
+func newNanopoolPool(config pool.Config) (p pool.Pool, err error) {
+	coin, err := nanopool.ParseCoin(config.Coin)
+	if err != nil {
+		return
+	}
+	var options []nanopool.Option
+	if config.APIRoot != "" {
+		options = append(options, nanopool.WithAPIRoot(config.APIRoot))
+	}
+	return nanopool.NewPool(newNanopoolCoinClient(coin, options...)), nil
+}
+
+// newEtherminePool builds an Ethermine or Flypool pool.Pool, the coin picks the API unless apiRoot is set
+func newEtherminePool(config pool.Config) (p pool.Pool, err error) {
+	coin, err := ethermine.ParseCoin(config.Coin)
+	if err != nil {
+		return
+	}
+	ethermineLimiterOnce.Do(func() {
+		rpm := viper.GetInt("miningtools.ethermine.requestsPerMinute")
+		ethermineLimiter = throttle.NewLimiter(rpm, rpm/6+1)
+	})
+	retry := throttle.DefaultPolicy
+	retry.MaxAttempts = viper.GetInt("miningtools.ethermine.retries")
+	options := []ethermine.Option{
+		ethermine.WithRateLimiter(ethermineLimiter),
+		ethermine.WithRetryPolicy(retry),
+		ethermine.WithTimeout(viper.GetDuration("miningtools.ethermine.timeout")),
+		ethermine.WithCoin(coin),
+	}
+	if config.APIRoot != "" {
+		options = append(options, ethermine.WithBaseURL(config.APIRoot))
+	}
+	return ethermine.NewPool(ethermine.NewClient(options...)), nil
+}
+
+// newOpenEthPool builds a pool.Pool for any open-ethereum-pool API, the coin picks the 2Miners API unless apiRoot is
+// set. Each pool is a different host so each gets its own rate limit
+func newOpenEthPool(config pool.Config) (p pool.Pool, err error) {
+	coin, err := openethpool.ParseCoin(config.Coin)
+	if err != nil {
+		return
+	}
+	rpm := viper.GetInt("miningtools.openethpool.requestsPerMinute")
+	retry := throttle.DefaultPolicy
+	retry.MaxAttempts = viper.GetInt("miningtools.openethpool.retries")
+	options := []openethpool.Option{
+		openethpool.WithRateLimiter(throttle.NewLimiter(rpm, rpm/6+1)),
+		openethpool.WithRetryPolicy(retry),
+		openethpool.WithTimeout(viper.GetDuration("miningtools.openethpool.timeout")),
+		openethpool.WithCoin(coin),
+	}
+	if config.APIRoot != "" {
+		options = append(options, openethpool.WithBaseURL(config.APIRoot))
+	}
+	return openethpool.NewPool(openethpool.NewClient(options...)), nil
+}
+
+// newFlexpoolPool builds a Flexpool pool.Pool, every coin is served by the same API so the rate limit is shared
+func newFlexpoolPool(config pool.Config) (p pool.Pool, err error) {
+	coin, err := flexpool.ParseCoin(config.Coin)
+	if err != nil {
+		return
+	}
+	flexpoolLimiterOnce.Do(func() {
+		rpm := viper.GetInt("miningtools.flexpool.requestsPerMinute")
+		flexpoolLimiter = throttle.NewLimiter(rpm, rpm/6+1)
+	})
+	retry := throttle.DefaultPolicy
+	retry.MaxAttempts = viper.GetInt("miningtools.flexpool.retries")
+	options := []flexpool.Option{
+		flexpool.WithRateLimiter(flexpoolLimiter),
+		flexpool.WithRetryPolicy(retry),
+		flexpool.WithTimeout(viper.GetDuration("miningtools.flexpool.timeout")),
+		flexpool.WithCoin(coin),
+	}
+	if config.APIRoot != "" {
+		options = append(options, flexpool.WithBaseURL(config.APIRoot))
+	}
+	return flexpool.NewPool(flexpool.NewClient(options...)), nil
+}
+
+// poolConfigs reads miningtools.pools, without it the account in miningtools.nanopool is watched as before
+func poolConfigs() (configs []pool.Config, err error) {
+	if err = viper.UnmarshalKey("miningtools.pools", &configs); err != nil {
+		return
+	}
+	if len(configs) == 0 {
+		configs = []pool.Config{{
+			Type: "nanopool",
+			Coin: viper.GetString("miningtools.nanopool.coin"),
+		}}
+	}
+	for i := range configs {
+		if configs[i].Type == "nanopool" && len(configs[i].Accounts) == 0 {
+			coin, err := nanopool.ParseCoin(configs[i].Coin)
+			if err != nil {
+				return nil, err
+			}
+			configs[i].Accounts = []string{nanopoolAddress(coin)}
+		}
+	}
+	return
+}
+
+// openPoolAccounts opens every configured pool and lists the accounts to watch on each
+func openPoolAccounts() (accounts []poolAccount, err error) {
+	configs, err := poolConfigs()
+	if err != nil {
+		log.Errorf("openPoolAccounts: poolConfigs(); returned err=%s\n", err.Error())
+		return
+	}
+	for _, config := range configs {
+		p, err := pool.Open(config)
+		if err != nil {
+			log.Errorf("openPoolAccounts: pool.Open(%s); returned err=%s\n", config.Label(), err.Error())
+			return nil, err
+		}
+		for _, account := range config.Accounts {
+			accounts = append(accounts, poolAccount{Pool: p, Config: config, Account: account})
+		}
+	}
+	return
+}
@@ -0,0 +1,302 @@
+/*
+Package miningtools contains the various supported CLI commands for mining-tools
+Copyright © 2020 Keith Olenchak <kenjin.domini@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miningtools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"mining-tools/nanopool"
+	"mining-tools/rig"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// reconcileSourceStored reads the hashrate rigs reported from the rig table the metrics command fills
+	reconcileSourceStored = "stored"
+	// reconcileSourceLive asks the configured rigs for their current hashrate
+	reconcileSourceLive = "live"
+
+	// flagUnreported marks a worker the pool credits that no rig reports a hashrate for
+	flagUnreported = "unreported"
+	// flagUnseen marks a worker whose rig reports a hashrate that the pool has never credited a share of, e.g. a rig
+	// mining to another account or not yet picked up by the pool
+	flagUnseen = "unseen"
+	// flagConnectivity marks a worker the pool has stopped hearing from while its rig is mining
+	flagConnectivity = "connectivity"
+	// flagStaleShares marks a worker the pool credits with persistently less than it reports, as stale or rejected
+	// shares do
+	flagStaleShares = "stale-shares"
+	// flagMisreporting marks a worker the pool credits with persistently more than it reports
+	flagMisreporting = "misreporting"
+)
+
+// reconcileWindows are the hours nanopool averages the hashrate of every worker over, H1 to H24
+var reconcileWindows = []int64{1, 3, 6, 12, 24}
+
+// reconcileCmd represents the reconcile command
+var (
+	reconcileSourceFlag string
+	reconcileCmd        = &cobra.Command{
+		Use:   "reconcile",
+		Short: "Compares the hashrate nanopool credits each worker with the hashrate its rig reports",
+		Long: `Joins the workers of the nanopool account with the hashrate their rigs report, either stored by the
+metrics command or read live from the configured rigs, and prints the effective/reported ratio of each
+worker over nanopool's 1, 3, 6, 12 and 24 hour windows. Workers whose ratios point at stale shares,
+bad connectivity or misreporting are flagged, as are rigs the pool has not seen a share from.`,
+		Run: reconcileCmdRun,
+	}
+)
+
+// WorkerReconciliation compares the hashrate nanopool credits a worker with and the hashrate its rig reports
+type WorkerReconciliation struct {
+	Worker    string                 `json:"worker"`
+	LastShare time.Time              `json:"lastShare"`
+	Windows   []ReconciliationWindow `json:"windows"`
+	Flags     []string               `json:"flags,omitempty"`
+}
+
+// ReconciliationWindow is the effective and reported hashrate of a worker averaged over Hours, Ratio is missing when
+// nothing was reported
+type ReconciliationWindow struct {
+	Hours     int64    `json:"hours"`
+	Effective float64  `json:"effective"`
+	Reported  float64  `json:"reported"`
+	Ratio     *float64 `json:"ratio,omitempty"`
+}
+
+// reconcileLimits are the ratios and share age beyond which a worker is flagged
+type reconcileLimits struct {
+	StaleRatio     float64
+	MisreportRatio float64
+	OutageRatio    float64
+	MaxShareAge    time.Duration
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+
+	reconcileCmd.Flags().StringVarP(&reconcileSourceFlag, "source", "s", reconcileSourceStored,
+		"where the rig-reported hashrate comes from, stored (the rig table of the timeseriesDB) or live (the configured rigs)")
+	reconcileCmd.Flags().String("queryAPI", "http://127.0.0.1:9000/", "QuestDB HTTP API the stored rig hashrates are queried from")
+	viper.BindPFlag("miningtools.timeseriesDB.queryAPI", reconcileCmd.Flags().Lookup("queryAPI"))
+	viper.SetDefault("miningtools.reconcile.staleRatio", 0.9)
+	viper.SetDefault("miningtools.reconcile.misreportRatio", 1.1)
+	viper.SetDefault("miningtools.reconcile.outageRatio", 0.5)
+	viper.SetDefault("miningtools.reconcile.maxShareAge", 15*time.Minute)
+}
+
+func reconcileCmdRun(cmd *cobra.Command, args []string) {
+	log.Debugln("reconcileCmdRun called")
+	reconciliations, err := reconcile(context.Background(), reconcileSourceFlag, time.Now().UTC())
+	if err != nil {
+		fmt.Println(err)
+		log.Errorf("reconcileCmdRun: reconcile(%s); returned err=%s\n", reconcileSourceFlag, err.Error())
+		return
+	}
+	prettyPrint(reconciliations)
+}
+
+// reconcile joins the workers of the configured nanopool account with the hashrate their rigs report and flags the
+// workers that do not add up at now. Workers only known to one side are included so missing rigs stand out
+func reconcile(ctx context.Context, source string, now time.Time) (reconciliations []WorkerReconciliation, err error) {
+	reported, err := reportedHashrates(ctx, source)
+	if err != nil {
+		log.Errorf("reconcile: reportedHashrates(%s); returned err=%s\n", source, err.Error())
+		return
+	}
+	client, err := newNanopoolClient()
+	if err != nil {
+		log.Errorf("reconcile: newNanopoolClient(); returned err=%s\n", err.Error())
+		return
+	}
+	address := nanopoolAddress(client.Coin())
+	info, err := client.GetMinerGeneralInfo(ctx, address)
+	if err != nil {
+		log.Errorf("reconcile: client.GetMinerGeneralInfo(ctx, %s); returned err=%s\n", address, err.Error())
+		return
+	}
+	unit := client.Coin().Info().HashrateUnit
+	limits := reconcileLimits{
+		StaleRatio:     viper.GetFloat64("miningtools.reconcile.staleRatio"),
+		MisreportRatio: viper.GetFloat64("miningtools.reconcile.misreportRatio"),
+		OutageRatio:    viper.GetFloat64("miningtools.reconcile.outageRatio"),
+		MaxShareAge:    viper.GetDuration("miningtools.reconcile.maxShareAge"),
+	}
+	seen := make(map[string]bool)
+	for _, w := range info.Data.Workers {
+		seen[w.ID] = true
+		averages := []nanopool.Hashrate{w.H1, w.H3, w.H6, w.H12, w.H24}
+		wr := WorkerReconciliation{Worker: w.ID}
+		if w.Lastshare > 0 {
+			wr.LastShare = time.Unix(w.Lastshare, 0).UTC()
+		}
+		for i, hours := range reconcileWindows {
+			wr.Windows = append(wr.Windows, newReconciliationWindow(hours, averages[i].HashesPerSecond(unit), reported[w.ID]))
+		}
+		wr.Flags = flagWorker(wr, now, limits)
+		reconciliations = append(reconciliations, wr)
+	}
+	for worker, hashrates := range reported {
+		if seen[worker] {
+			continue
+		}
+		wr := WorkerReconciliation{Worker: worker}
+		for _, hours := range reconcileWindows {
+			wr.Windows = append(wr.Windows, newReconciliationWindow(hours, 0, hashrates))
+		}
+		wr.Flags = flagWorker(wr, now, limits)
+		reconciliations = append(reconciliations, wr)
+	}
+	sort.Slice(reconciliations, func(i, j int) bool { return reconciliations[i].Worker < reconciliations[j].Worker })
+	return
+}
+
+// newReconciliationWindow pairs the effective hashrate over hours with the hashrate reported over the same window
+func newReconciliationWindow(hours int64, effective float64, reported map[int64]float64) (window ReconciliationWindow) {
+	window.Hours = hours
+	window.Effective = effective
+	window.Reported = reported[hours]
+	if window.Reported > 0 {
+		ratio := math.Round(effective/window.Reported*1000) / 1000
+		window.Ratio = &ratio
+	}
+	return
+}
+
+// flagWorker names what the ratios of wr suggest is wrong with the worker. The effective hashrate of the shortest
+// window is at the mercy of share luck so it only counts towards connectivity, stale shares and misreporting are
+// judged on the windows of 6 hours and more
+func flagWorker(wr WorkerReconciliation, now time.Time, limits reconcileLimits) (flags []string) {
+	var long []float64
+	for _, w := range wr.Windows {
+		if w.Ratio != nil && w.Hours >= 6 {
+			long = append(long, *w.Ratio)
+		}
+	}
+	shortest := wr.Windows[0]
+	if shortest.Ratio == nil && len(long) == 0 {
+		return []string{flagUnreported}
+	}
+	// without a single share there is nothing to judge connectivity or stale shares on
+	credited := false
+	for _, w := range wr.Windows {
+		credited = credited || w.Effective > 0
+	}
+	if wr.LastShare.IsZero() && !credited {
+		return []string{flagUnseen}
+	}
+	if shortest.Ratio != nil && (*shortest.Ratio < limits.OutageRatio || now.Sub(wr.LastShare) > limits.MaxShareAge) {
+		flags = append(flags, flagConnectivity)
+	}
+	if len(long) == 0 {
+		return
+	}
+	stale, misreporting := true, true
+	for _, ratio := range long {
+		stale = stale && ratio < limits.StaleRatio
+		misreporting = misreporting && ratio > limits.MisreportRatio
+	}
+	if stale {
+		flags = append(flags, flagStaleShares)
+	}
+	if misreporting {
+		flags = append(flags, flagMisreporting)
+	}
+	return
+}
+
+// reportedHashrates returns the hashrate each worker's rigs reported, averaged over every window in reconcileWindows
+func reportedHashrates(ctx context.Context, source string) (reported map[string]map[int64]float64, err error) {
+	switch source {
+	case reconcileSourceStored:
+		return storedReportedHashrates(viper.GetString("miningtools.timeseriesDB.queryAPI"))
+	case reconcileSourceLive:
+		return liveReportedHashrates(ctx)
+	}
+	return nil, fmt.Errorf("unknown reconcile source %q, expected %s or %s", source, reconcileSourceStored, reconcileSourceLive)
+}
+
+// liveReportedHashrates asks every configured rig for its hashrate, a rig only knows its current hashrate so it stands
+// in for every window. Rigs of the same worker are added up and unreachable rigs are left out
+func liveReportedHashrates(ctx context.Context) (reported map[string]map[int64]float64, err error) {
+	rigs, err := openRigs()
+	if err != nil {
+		log.Errorf("liveReportedHashrates: openRigs(); returned err=%s\n", err.Error())
+		return
+	}
+	reported = make(map[string]map[int64]float64)
+	for _, rc := range rigs {
+		stats, _, err := collectRigStats(ctx, rc)
+		if errors.Is(err, rig.ErrUnreachable) {
+			log.Warnf("liveReportedHashrates: rig %s is unreachable, leaving it out: %s\n", rc.Config.Label(), err.Error())
+			continue
+		}
+		if err != nil {
+			log.Errorf("liveReportedHashrates: collectRigStats(%s); returned err=%s\n", rc.Config.Label(), err.Error())
+			return nil, err
+		}
+		if stats.Worker == "" {
+			log.Warnf("liveReportedHashrates: rig %s has no worker name, leaving it out\n", rc.Config.Label())
+			continue
+		}
+		if reported[stats.Worker] == nil {
+			reported[stats.Worker] = make(map[int64]float64)
+		}
+		for _, hours := range reconcileWindows {
+			reported[stats.Worker][hours] += stats.Hashrate
+		}
+	}
+	return
+}
+
+// storedReportedHashrates averages the hashrate each rig reported to the rig table over every window, rigs of the
+// same worker are added up. Rows are only written while a rig is reachable, so unlike the pool's averages the stored
+// ones leave out the time a rig was down or not yet mining
+func storedReportedHashrates(apiRoot string) (reported map[string]map[int64]float64, err error) {
+	reported = make(map[string]map[int64]float64)
+	for _, hours := range reconcileWindows {
+		query := fmt.Sprintf("SELECT Worker, Rig, avg(Hashrate) FROM rig WHERE Worker IS NOT NULL AND timestamp > dateadd('h', -%d, now())", hours)
+		response, err := queryQuestDB(apiRoot, query)
+		if err != nil {
+			log.Errorf("storedReportedHashrates: queryQuestDB(%s, %s); returned err=%s\n", apiRoot, query, err.Error())
+			return nil, err
+		}
+		for _, row := range response.(*QuestDBSuccessResponse).Dataset {
+			if len(row) != 3 {
+				return nil, fmt.Errorf("query '%s' returned an unexpected row %v", query, row)
+			}
+			worker, ok := row[0].(string)
+			hashrate, isFloat := row[2].(float64)
+			if !ok || !isFloat {
+				return nil, fmt.Errorf("query '%s' returned an unexpected row %v", query, row)
+			}
+			if reported[worker] == nil {
+				reported[worker] = make(map[int64]float64)
+			}
+			reported[worker][hours] += hashrate
+		}
+	}
+	return
+}
@@ -0,0 +1,177 @@
+package miningtools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"mining-tools/nanopool"
+	"mining-tools/nanopool/nanopooltest"
+
+	"github.com/spf13/viper"
+)
+
+// windows builds the reconciliation windows of a worker from its effective and reported hashrates over 1 to 24 hours
+func windows(effective [5]float64, reported [5]float64) (ws []ReconciliationWindow) {
+	for i, hours := range reconcileWindows {
+		ws = append(ws, newReconciliationWindow(hours, effective[i], map[int64]float64{hours: reported[i]}))
+	}
+	return
+}
+
+func Test_flagWorker(t *testing.T) {
+	now := time.Unix(1609459200, 0).UTC()
+	limits := reconcileLimits{StaleRatio: 0.9, MisreportRatio: 1.1, OutageRatio: 0.5, MaxShareAge: 15 * time.Minute}
+	reported := [5]float64{100, 100, 100, 100, 100}
+	tests := []struct {
+		name      string
+		lastShare time.Time
+		windows   []ReconciliationWindow
+		want      []string
+	}{
+		{
+			name:      "Healthy01",
+			lastShare: now.Add(-time.Minute),
+			// an unlucky hour is no reason to flag a worker
+			windows: windows([5]float64{70, 95, 97, 98, 99}, reported),
+			want:    nil,
+		},
+		{
+			name:      "Unreported01",
+			lastShare: now.Add(-time.Minute),
+			windows:   windows([5]float64{100, 100, 100, 100, 100}, [5]float64{}),
+			want:      []string{flagUnreported},
+		},
+		{
+			name:      "Connectivity01",
+			lastShare: now.Add(-time.Hour),
+			windows:   windows([5]float64{40, 80, 90, 95, 97}, reported),
+			want:      []string{flagConnectivity},
+		},
+		{
+			name:      "Connectivity02",
+			lastShare: now.Add(-time.Minute),
+			windows:   windows([5]float64{30, 95, 97, 98, 99}, reported),
+			want:      []string{flagConnectivity},
+		},
+		{
+			name:    "Unseen01",
+			windows: windows([5]float64{}, reported),
+			want:    []string{flagUnseen},
+		},
+		{
+			name:      "StaleShares01",
+			lastShare: now.Add(-time.Minute),
+			windows:   windows([5]float64{85, 86, 84, 85, 86}, reported),
+			want:      []string{flagStaleShares},
+		},
+		{
+			name:      "Misreporting01",
+			lastShare: now.Add(-time.Minute),
+			windows:   windows([5]float64{130, 125, 120, 121, 120}, reported),
+			want:      []string{flagMisreporting},
+		},
+		{
+			name:      "Misreporting02",
+			lastShare: now.Add(-time.Minute),
+			// only the 24 hour window rose above the reported hashrate
+			windows: windows([5]float64{100, 100, 100, 105, 115}, reported),
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wr := WorkerReconciliation{Worker: "rig1", LastShare: tt.lastShare, Windows: tt.windows}
+			if got := flagWorker(wr, now, limits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flagWorker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// useFreshWorkers makes the fake nanopool's workers share just now, so only the hashrates decide the flags
+func useFreshWorkers(t *testing.T) {
+	t.Helper()
+	config := nanopooltest.DefaultConfig()
+	now := time.Now().Unix()
+	for i := range config.Accounts[nanopooltest.DefaultAddress].Workers {
+		config.Accounts[nanopooltest.DefaultAddress].Workers[i].Lastshare = now
+	}
+	config.Accounts[nanopooltest.DefaultAddress].Workers = append(config.Accounts[nanopooltest.DefaultAddress].Workers,
+		nanopool.MinerGeneralInfoWorker{ID: "rig3", UID: 3, Hashrate: 50, Lastshare: now})
+	useFakeNanopool(t, config)
+}
+
+func Test_reconcileStored(t *testing.T) {
+	questDB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		if r.URL.Path != "/exec" || !strings.HasPrefix(query, "SELECT Worker, Rig, avg(Hashrate) FROM rig ") {
+			fmt.Fprintf(w, `{"query":%q,"error":"unexpected query","position":0}`, query)
+			return
+		}
+		// amd2 was overclocked three hours ago, before that it reported half its hashrate
+		amd2 := 40000000.0
+		if !strings.Contains(query, "-1, now()") && !strings.Contains(query, "-3, now()") {
+			amd2 = 20000000.0
+		}
+		fmt.Fprintf(w, `{"query":%q,"columns":[{"name":"Worker","type":"SYMBOL"},{"name":"Rig","type":"SYMBOL"},`+
+			`{"name":"avg","type":"DOUBLE"}],"dataset":[["rig1","nvidia1",100000000.0],["rig2","amd1",60000000.0],`+
+			`["rig2","amd2",%v],["rig4","amd4",30000000.0]],"count":4}`, query, amd2)
+	}))
+	defer questDB.Close()
+	useFreshWorkers(t)
+	viper.Set("miningtools.timeseriesDB.queryAPI", questDB.URL+"/")
+	got, err := reconcile(context.Background(), reconcileSourceStored, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	want := map[string][]string{
+		"rig1": nil,
+		// 95 MH/s against 100 MH/s in the short windows and as little as 65 MH/s in the long ones
+		"rig2": {flagMisreporting},
+		"rig3": {flagUnreported},
+		// rig4 mines to another account, the pool has never seen a share of it
+		"rig4": {flagUnseen},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("reconcile() = %+v, want the workers %v", got, want)
+	}
+	for _, wr := range got {
+		if !reflect.DeepEqual(wr.Flags, want[wr.Worker]) {
+			t.Errorf("reconcile() flags of %s = %v, want %v", wr.Worker, wr.Flags, want[wr.Worker])
+		}
+	}
+	if got[0].Worker != "rig1" || *got[0].Windows[4].Ratio != 0.955 || got[0].Windows[4].Effective != 95500000 {
+		t.Errorf("reconcile() rig1 = %+v, want a ratio of 0.955 over 24 hours", got[0])
+	}
+	if got[1].Windows[0].Reported != 100000000 || got[1].Windows[2].Reported != 80000000 {
+		t.Errorf("reconcile() rig2 = %+v, want both rigs added up", got[1])
+	}
+}
+
+func Test_reconcileLive(t *testing.T) {
+	trexServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"accepted_count":95,"active_pool":{"worker":"rig1"},"gpus":[],"hashrate":100000000,"uptime":3600,"version":"0.24.8"}`)
+	}))
+	defer trexServer.Close()
+	useFreshWorkers(t)
+	viper.Set("miningtools.rigs", []map[string]interface{}{
+		{"name": "nvidia1", "type": "trex", "address": strings.TrimPrefix(trexServer.URL, "http://")},
+		{"name": "nvidia2", "type": "trex", "address": strings.TrimPrefix(trexServer.URL, "http://"), "worker": "rig2"},
+	})
+	got, err := reconcile(context.Background(), reconcileSourceLive, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if len(got) != 3 || got[0].Flags != nil || got[1].Flags != nil || !reflect.DeepEqual(got[2].Flags, []string{flagUnreported}) {
+		t.Errorf("reconcile() = %+v, want rig1 and rig2 healthy and rig3 unreported", got)
+	}
+	if _, err = reconcile(context.Background(), "influxdb", time.Now().UTC()); err == nil {
+		t.Errorf("reconcile() error = nil, want an error for an unknown source")
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Package miningtools contains the various supported CLI commands for mining-tools
+Copyright © 2020 Keith Olenchak <kenjin.domini@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miningtools
+
+import (
+	"mining-tools/claymore"
+	"mining-tools/ethminer"
+	"mining-tools/lolminer"
+	"mining-tools/rig"
+	"mining-tools/sgminer"
+	"mining-tools/trex"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// rigCollector is one configured rig and the API of the mining software running on it
+type rigCollector struct {
+	Rig    rig.Rig
+	Config rig.Config
+}
+
+func init() {
+	rig.Register("ethminer", newEthminerRig)
+	rig.Register("claymore", newClaymoreRig)
+	rig.Register("phoenixminer", newClaymoreRig)
+	rig.Register("trex", newTRexRig)
+	rig.Register("lolminer", newLolMinerRig)
+	rig.Register("sgminer", newSgminerRig)
+	rig.Register("cgminer", newSgminerRig)
+	rig.Register("teamredminer", newSgminerRig)
+	viper.SetDefault("miningtools.ethminer.timeout", ethminer.DefaultTimeout)
+	viper.SetDefault("miningtools.claymore.timeout", claymore.DefaultTimeout)
+	viper.SetDefault("miningtools.trex.timeout", trex.DefaultTimeout)
+	viper.SetDefault("miningtools.lolminer.timeout", lolminer.DefaultTimeout)
+	viper.SetDefault("miningtools.sgminer.timeout", sgminer.DefaultTimeout)
+	viper.SetDefault("miningtools.sgminer.plainText", false)
+}
+
+// newEthminerRig builds a rig.Rig for the JSON-RPC API of ethminer
+func newEthminerRig(config rig.Config) (r rig.Rig, err error) {
+	options := []ethminer.Option{
+		ethminer.WithTimeout(viper.GetDuration("miningtools.ethminer.timeout")),
+	}
+	if config.Password != "" {
+		options = append(options, ethminer.WithPassword(config.Password))
+	}
+	return ethminer.NewRig(ethminer.NewClient(config.Address, options...)), nil
+}
+
+// newClaymoreRig builds a rig.Rig for the remote management API of Claymore's Dual Miner or PhoenixMiner, the type
+// names the miner in the metrics
+func newClaymoreRig(config rig.Config) (r rig.Rig, err error) {
+	options := []claymore.Option{
+		claymore.WithTimeout(viper.GetDuration("miningtools.claymore.timeout")),
+	}
+	if config.Password != "" {
+		options = append(options, claymore.WithPassword(config.Password))
+	}
+	return claymore.NewRig(claymore.NewClient(config.Address, options...), config.Type), nil
+}
+
+// newTRexRig builds a rig.Rig for the HTTP API of T-Rex
+func newTRexRig(config rig.Config) (r rig.Rig, err error) {
+	client := trex.NewClient(config.Address, trex.WithTimeout(viper.GetDuration("miningtools.trex.timeout")))
+	return trex.NewRig(client), nil
+}
+
+// newLolMinerRig builds a rig.Rig for the HTTP API of lolMiner
+func newLolMinerRig(config rig.Config) (r rig.Rig, err error) {
+	client := lolminer.NewClient(config.Address, lolminer.WithTimeout(viper.GetDuration("miningtools.lolminer.timeout")))
+	return lolminer.NewRig(client), nil
+}
+
+// newSgminerRig builds a rig.Rig for the socket API of cgminer, sgminer, TeamRedMiner and the ASIC firmwares built on
+// them, miningtools.sgminer.plainText selects the pipe delimited replies for builds without JSON support
+func newSgminerRig(config rig.Config) (r rig.Rig, err error) {
+	options := []sgminer.Option{
+		sgminer.WithTimeout(viper.GetDuration("miningtools.sgminer.timeout")),
+	}
+	if viper.GetBool("miningtools.sgminer.plainText") {
+		options = append(options, sgminer.WithPlainText())
+	}
+	return sgminer.NewRig(sgminer.NewClient(config.Address, options...)), nil
+}
+
+// rigConfigs reads miningtools.rigs, no rigs are watched without it
+func rigConfigs() (configs []rig.Config, err error) {
+	err = viper.UnmarshalKey("miningtools.rigs", &configs)
+	return
+}
+
+// openRigs opens every configured rig
+func openRigs() (rigs []rigCollector, err error) {
+	configs, err := rigConfigs()
+	if err != nil {
+		log.Errorf("openRigs: rigConfigs(); returned err=%s\n", err.Error())
+		return
+	}
+	for _, config := range configs {
+		r, err := rig.Open(config)
+		if err != nil {
+			log.Errorf("openRigs: rig.Open(%s); returned err=%s\n", config.Label(), err.Error())
+			return nil, err
+		}
+		rigs = append(rigs, rigCollector{Rig: r, Config: config})
+	}
+	return
+}
@@ -42,6 +42,9 @@ to quickly create a Cobra application.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	//	Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		saveNanopoolCache()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -65,9 +68,9 @@ func init() {
 	viper.BindPFlag("miningtools.logging.file", rootCmd.PersistentFlags().Lookup("log"))
 	rootCmd.PersistentFlags().Uint32("log-level", 4, "Sets global log level 5=Debug 0=Panic/virtually silent (Default: 4)")
 	viper.BindPFlag("miningtools.logging.level", rootCmd.PersistentFlags().Lookup("log-level"))
-	rootCmd.PersistentFlags().String("timeseriesDB", "127.0.0.1:9009", "timeseriesDB address (Default:  127.0.0.1:9009)")
+	rootCmd.PersistentFlags().String("timeseriesDB", "", "timeseriesDB address (Default:  the protocol's usual address, 127.0.0.1:9009 for the line protocol listener of InfluxDB, QuestDB and ILP, http://127.0.0.1:8086 for InfluxDBv2, http://127.0.0.1:9090/api/v1/write for RemoteWrite, 127.0.0.1:2003 for Graphite and 127.0.0.1:8125 for StatsD)")
 	viper.BindPFlag("miningtools.timeseriesDB.address", rootCmd.PersistentFlags().Lookup("timeseriesDB"))
-	rootCmd.PersistentFlags().String("timeseriesProtocol", "InfluxDB", "timeseriesDB protocol, supports InfluxDB (Default:  InfluxDB)")
+	rootCmd.PersistentFlags().String("timeseriesProtocol", "InfluxDB", "timeseriesDB protocol, InfluxDB, QuestDB and ILP send line protocol over TCP, InfluxDBv2 posts it to the InfluxDB 2.x HTTP API, RemoteWrite (or Prometheus) pushes it to a Prometheus remote write receiver, Graphite sends Graphite plaintext over TCP and StatsD gauges over UDP (Default:  InfluxDB)")
 	viper.BindPFlag("miningtools.timeseriesDB.protocol", rootCmd.PersistentFlags().Lookup("timeseriesProtocol"))
 
 	// Cobra also supports local flags, which will only run
@@ -0,0 +1,157 @@
+/*
+Package miningtools contains the various supported CLI commands for mining-tools
+Copyright © 2020 Keith Olenchak <kenjin.domini@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miningtools
+
+import (
+	"strings"
+
+	"mining-tools/graphite"
+	"mining-tools/influxdb"
+	"mining-tools/lineprotocol"
+	"mining-tools/questdb"
+	"mining-tools/remotewrite"
+	"mining-tools/sink"
+	"mining-tools/statsd"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	// InfluxDB is what --timeseriesProtocol has always meant, line protocol over TCP as QuestDB listens for it
+	sink.Register("influxdb", newQuestDBSink)
+	sink.Register("questdb", newQuestDBSink)
+	sink.Register("ilp", newQuestDBSink)
+	sink.Register("influxdbv2", newInfluxDBSink)
+	sink.Register("remotewrite", newRemoteWriteSink)
+	sink.Register("prometheus", newRemoteWriteSink)
+	sink.Register("graphite", newGraphiteSink)
+	sink.Register("statsd", newStatsDSink)
+	viper.SetDefault("miningtools.timeseriesDB.timeout", questdb.DefaultTimeout)
+	viper.SetDefault("miningtools.timeseriesDB.precision", lineprotocol.Nanosecond.String())
+	viper.SetDefault("miningtools.timeseriesDB.batchSize", influxdb.DefaultBatchSize)
+	viper.SetDefault("miningtools.timeseriesDB.namespace", remotewrite.DefaultNamespace)
+	viper.SetDefault("miningtools.timeseriesDB.template", graphite.DefaultTemplate)
+}
+
+// newQuestDBSink builds a sink.Sink for the line protocol TCP listener of QuestDB
+func newQuestDBSink(config sink.Config) (s sink.Sink, err error) {
+	address := config.Address
+	if address == "" {
+		address = questdb.DefaultAddress
+	}
+	return questdb.NewSink(address, questdb.WithTimeout(config.Timeout)), nil
+}
+
+// newInfluxDBSink builds a sink.Sink for the write API of InfluxDB 2.x, an address without a scheme is taken to be
+// plain HTTP
+func newInfluxDBSink(config sink.Config) (s sink.Sink, err error) {
+	precision, err := lineprotocol.ParsePrecision(config.Precision)
+	if err != nil {
+		return
+	}
+	serverURL := config.Address
+	if serverURL == "" {
+		serverURL = influxdb.DefaultURL
+	} else if !strings.Contains(serverURL, "://") {
+		serverURL = "http://" + serverURL
+	}
+	return influxdb.NewSink(serverURL, config.Org, config.Bucket,
+		influxdb.WithToken(config.Token),
+		influxdb.WithPrecision(precision),
+		influxdb.WithBatchSize(config.BatchSize),
+		influxdb.WithTimeout(config.Timeout),
+	), nil
+}
+
+// newRemoteWriteSink builds a sink.Sink for a Prometheus remote write receiver. A token of the form user:password is
+// sent as basic auth, as hosted receivers expect, any other token as a bearer token, and org selects the tenant
+func newRemoteWriteSink(config sink.Config) (s sink.Sink, err error) {
+	writeURL := config.Address
+	if writeURL == "" {
+		writeURL = remotewrite.DefaultURL
+	} else if !strings.Contains(writeURL, "://") {
+		writeURL = "http://" + writeURL
+	}
+	options := []remotewrite.Option{
+		remotewrite.WithNamespace(config.Namespace),
+		remotewrite.WithLabels(config.Labels),
+		remotewrite.WithExternalLabels(config.ExternalLabels),
+		remotewrite.WithTenant(config.Org),
+		remotewrite.WithTimeout(config.Timeout),
+	}
+	if i := strings.Index(config.Token, ":"); i >= 0 {
+		options = append(options, remotewrite.WithBasicAuth(config.Token[:i], config.Token[i+1:]))
+	} else if config.Token != "" {
+		options = append(options, remotewrite.WithBearerToken(config.Token))
+	}
+	return remotewrite.NewSink(writeURL, options...)
+}
+
+// newGraphiteSink builds a sink.Sink for the plaintext TCP listener of Graphite's Carbon
+func newGraphiteSink(config sink.Config) (s sink.Sink, err error) {
+	template, err := graphite.ParseTemplate(config.Template)
+	if err != nil {
+		return
+	}
+	address := config.Address
+	if address == "" {
+		address = graphite.DefaultAddress
+	}
+	return graphite.NewSink(address, graphite.WithTemplate(template), graphite.WithTimeout(config.Timeout)), nil
+}
+
+// newStatsDSink builds a sink.Sink sending gauges to a StatsD server over UDP
+func newStatsDSink(config sink.Config) (s sink.Sink, err error) {
+	template, err := graphite.ParseTemplate(config.Template)
+	if err != nil {
+		return
+	}
+	address := config.Address
+	if address == "" {
+		address = statsd.DefaultAddress
+	}
+	return statsd.NewSink(address, statsd.WithTemplate(template), statsd.WithTimeout(config.Timeout)), nil
+}
+
+// sinkConfig reads miningtools.timeseriesDB, which --timeseriesDB and --timeseriesProtocol override
+func sinkConfig() sink.Config {
+	return sink.Config{
+		Protocol:       viper.GetString("miningtools.timeseriesDB.protocol"),
+		Address:        viper.GetString("miningtools.timeseriesDB.address"),
+		Timeout:        viper.GetDuration("miningtools.timeseriesDB.timeout"),
+		Org:            viper.GetString("miningtools.timeseriesDB.org"),
+		Bucket:         viper.GetString("miningtools.timeseriesDB.bucket"),
+		Token:          viper.GetString("miningtools.timeseriesDB.token"),
+		Precision:      viper.GetString("miningtools.timeseriesDB.precision"),
+		BatchSize:      viper.GetInt("miningtools.timeseriesDB.batchSize"),
+		Template:       viper.GetString("miningtools.timeseriesDB.template"),
+		Namespace:      viper.GetString("miningtools.timeseriesDB.namespace"),
+		Labels:         viper.GetStringMapString("miningtools.timeseriesDB.labels"),
+		ExternalLabels: viper.GetStringMapString("miningtools.timeseriesDB.externalLabels"),
+	}
+}
+
+// openSink opens the configured timeseries database
+func openSink() (s sink.Sink, err error) {
+	config := sinkConfig()
+	s, err = sink.Open(config)
+	if err != nil {
+		log.Errorf("openSink: sink.Open(%s, %s); returned err=%s\n", config.Protocol, config.Address, err.Error())
+	}
+	return
+}
@@ -0,0 +1,253 @@
+// Package ethermine is a client for the miner endpoints of the Ethermine API, which Flypool also runs for its other coins
+package ethermine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"mining-tools/throttle"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultBaseURL is the root of the Ethermine API
+	DefaultBaseURL = "https://api.ethermine.org/"
+	// EthereumClassicBaseURL is the root of the Ethermine Ethereum Classic API
+	EthereumClassicBaseURL = "https://api-etc.ethermine.org/"
+	// FlypoolZcashBaseURL is the root of the Flypool Zcash API
+	FlypoolZcashBaseURL = "https://api-zcash.flypool.org/"
+	// FlypoolRavencoinBaseURL is the root of the Flypool Ravencoin API
+	FlypoolRavencoinBaseURL = "https://api-ravencoin.flypool.org/"
+	// DefaultDecimals is the number of decimal places of the smallest unit amounts are given in, 18 for wei
+	DefaultDecimals = 18
+	// DefaultTimeout is the per-call timeout a Client applies when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+)
+
+// HTTPClient is an interface to abstract http.client to support testing using mocks
+type HTTPClient interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+var (
+	apiClient = HTTPClient(&http.Client{})
+)
+
+// Client talks to a single Ethermine or Flypool API root using its own transport, user agent and timeout
+type Client struct {
+	baseURL    string
+	coin       Coin
+	decimals   int
+	httpClient HTTPClient
+	userAgent  string
+	timeout    time.Duration
+	limiter    *throttle.Limiter
+	retry      throttle.Policy
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithBaseURL sets the API root every endpoint path is appended to, e.g. https://api.ethermine.org/
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithCoin sets the coin mined on the API along with the API root and decimals it is served with, give WithBaseURL
+// after it to reach the coin somewhere else
+func WithCoin(coin Coin) Option {
+	return func(c *Client) {
+		c.coin = coin
+		c.baseURL = coin.Info().BaseURL
+		c.decimals = coin.Info().Decimals
+	}
+}
+
+// WithDecimals sets the decimal places of the smallest unit of the coin, e.g. 8 for the Flypool Zcash API
+func WithDecimals(decimals int) Option {
+	return func(c *Client) {
+		c.decimals = decimals
+	}
+}
+
+// WithHTTPClient sets the transport used for every request
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the timeout applied to each call, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRateLimiter sets the limiter every request waits on, share one limiter between all clients of the same API
+func WithRateLimiter(limiter *throttle.Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried, by default a request is attempted once
+func WithRetryPolicy(policy throttle.Policy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// NewClient returns a Client for the Ethermine API adjusted by options
+func NewClient(options ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		coin:       ETH,
+		decimals:   DefaultDecimals,
+		httpClient: apiClient,
+		timeout:    DefaultTimeout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// BaseURL returns the URL every endpoint path is appended to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Coin returns the coin mined on the API
+func (c *Client) Coin() Coin {
+	return c.coin
+}
+
+// Decimals returns the decimal places of the smallest unit amounts are given in
+func (c *Client) Decimals() int {
+	return c.decimals
+}
+
+// GetDashboard calls the Miner:Dashboard endpoint miner/:miner/dashboard and forms the response in to a usable Struct
+func (c *Client) GetDashboard(ctx context.Context, miner string) (dashboard Dashboard, err error) {
+	err = c.get(ctx, "miner/"+miner+"/dashboard", &dashboard)
+	return
+}
+
+// GetCurrentStats calls the Miner:Statistics endpoint miner/:miner/currentStats and forms the response in to a usable Struct
+func (c *Client) GetCurrentStats(ctx context.Context, miner string) (stats CurrentStats, err error) {
+	err = c.get(ctx, "miner/"+miner+"/currentStats", &stats)
+	return
+}
+
+// GetHistory calls the Miner:History endpoint miner/:miner/history and forms the response in to a usable Struct
+func (c *Client) GetHistory(ctx context.Context, miner string) (history History, err error) {
+	err = c.get(ctx, "miner/"+miner+"/history", &history)
+	return
+}
+
+// GetPayouts calls the Miner:Payouts endpoint miner/:miner/payouts and forms the response in to a usable Struct
+func (c *Client) GetPayouts(ctx context.Context, miner string) (payouts Payouts, err error) {
+	err = c.get(ctx, "miner/"+miner+"/payouts", &payouts)
+	return
+}
+
+// GetRounds calls the Miner:Rounds endpoint miner/:miner/rounds and forms the response in to a usable Struct
+func (c *Client) GetRounds(ctx context.Context, miner string) (rounds Rounds, err error) {
+	err = c.get(ctx, "miner/"+miner+"/rounds", &rounds)
+	return
+}
+
+// GetWorkers calls the Miner:Workers endpoint miner/:miner/workers and forms the response in to a usable Struct
+func (c *Client) GetWorkers(ctx context.Context, miner string) (workers Workers, err error) {
+	err = c.get(ctx, "miner/"+miner+"/workers", &workers)
+	return
+}
+
+// GetSettings calls the Miner:Settings endpoint miner/:miner/settings and forms the response in to a usable Struct
+func (c *Client) GetSettings(ctx context.Context, miner string) (settings Settings, err error) {
+	err = c.get(ctx, "miner/"+miner+"/settings", &settings)
+	return
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, output interface{}) (err error) {
+	fullPath := c.baseURL + endpoint
+	log.Debugf("Client.get(fullPath=%s, output interface{}) called\n", fullPath)
+	resp, attempts, err := c.retry.Do(ctx, c.limiter, func(ctx context.Context) (*http.Response, error) {
+		return c.send(ctx, fullPath)
+	})
+	defer func() {
+		if err != nil && attempts > 1 {
+			err = &throttle.Error{Attempts: attempts, Err: err}
+		}
+	}()
+	if err != nil {
+		log.Errorf("Client.get: c.send(ctx, %s); returned err=%s after %d attempts\n", fullPath, err.Error(), attempts)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("Client.get: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return
+	}
+	envelope := new(ErrorResponse)
+	if json.Unmarshal(body, envelope) != nil {
+		envelope = nil
+	}
+	err = checkResponse(endpoint, resp.StatusCode, envelope)
+	if err != nil {
+		log.Errorf("Client.get: checkResponse(%s, %d, envelope); returned err=%s after %d attempts\n", endpoint, resp.StatusCode, err.Error(), attempts)
+		return
+	}
+	err = json.Unmarshal(body, output)
+	if err != nil {
+		log.Errorf("Client.get: json.Unmarshal(body, output); returned err=%s\n", err.Error())
+		err = &APIError{Endpoint: endpoint, StatusCode: resp.StatusCode, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	return
+}
+
+// send makes a single attempt at fullPath within the per-call timeout, the body is read before the timeout is released
+func (c *Client) send(ctx context.Context, fullPath string) (resp *http.Response, err error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath, nil)
+	if err != nil {
+		log.Errorf("Client.send: http.NewRequestWithContext(ctx, GET, %s, nil); returned err=%s\n", fullPath, err.Error())
+		return
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err = c.httpClient.Do(req)
+	if err != nil {
+		log.Errorf("Client.send: c.httpClient.Do(%s); returned err=%s\n", fullPath, err.Error())
+		return
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		log.Errorf("Client.send: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return
+}
@@ -0,0 +1,232 @@
+package ethermine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"mining-tools/pool"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAPIClient struct {
+	mock.Mock
+}
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func (mac *mockAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	args := mac.Called(req.URL.String())
+	body, _ := json.Marshal(args.Get(0))
+	resp = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBuffer(body)),
+	}
+	return resp, args.Error(1)
+}
+
+type cannedAPIClient struct {
+	statusCode int
+	body       string
+}
+
+func (cac *cannedAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	resp = &http.Response{
+		StatusCode: cac.statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(cac.body)),
+	}
+	return
+}
+
+func Test_getErrors(t *testing.T) {
+	invalidBody, _ := json.Marshal(miner0x03Invalid)
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    error
+	}{
+		{
+			name:       "Success01",
+			statusCode: http.StatusOK,
+			body:       `{"status":"OK","data":{"unpaid":142000000000000000}}`,
+			wantErr:    nil,
+		},
+		{
+			name:       "AddressNotFound01",
+			statusCode: http.StatusOK,
+			body:       `{"status":"OK","data":"NO DATA"}`,
+			wantErr:    ErrAddressNotFound,
+		},
+		{
+			name:       "AddressNotFound02",
+			statusCode: http.StatusOK,
+			body:       string(invalidBody),
+			wantErr:    pool.ErrAccountNotFound,
+		},
+		{
+			name:       "RateLimited01",
+			statusCode: http.StatusTooManyRequests,
+			body:       `Too Many Requests`,
+			wantErr:    pool.ErrRateLimited,
+		},
+		{
+			name:       "ServerError01",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>Bad Gateway</html>`,
+			wantErr:    ErrServer,
+		},
+		{
+			name:       "Malformed01",
+			statusCode: http.StatusOK,
+			body:       `<html>maintenance</html>`,
+			wantErr:    ErrMalformedResponse,
+		},
+		{
+			name:       "Malformed02",
+			statusCode: http.StatusOK,
+			body:       `{"status":"OK","data":{"unpaid":"lots"}}`,
+			wantErr:    ErrMalformedResponse,
+		},
+		{
+			name:       "RequestFailed01",
+			statusCode: http.StatusOK,
+			body:       `{"status":"ERROR","error":"Something else"}`,
+			wantErr:    ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(WithBaseURL("http://test.com/"), WithHTTPClient(&cannedAPIClient{tt.statusCode, tt.body}))
+			_, err := c.GetCurrentStats(context.Background(), "0x02")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetCurrentStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.statusCode) {
+				t.Errorf("Client.GetCurrentStats() error = %#v, want *APIError with StatusCode %d", err, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_minerEndpoints(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	c := NewClient(WithBaseURL("http://test.com/"), WithHTTPClient(mockClient))
+	ctx := context.Background()
+	history := []HistoryData{{Time: 1609459200, CurrentHashrate: 190500000, ValidShares: 32, StaleShares: 1}}
+	workers := []WorkerData{{Worker: "rig1", LastSeen: 1609459180, CurrentHashrate: 95500000, ValidShares: 95}}
+	payouts := []PayoutData{{Start: 1609300000, End: 1609459000, Amount: big.NewInt(100000000000000000), TXHash: "0x02", PaidOn: 1609459100}}
+	settings := SettingsData{Email: "k***@gmail.com", Monitor: 1, MinPayout: big.NewInt(200000000000000000)}
+	mockClient.On("Do", "http://test.com/miner/0x01/currentStats").Return(miner0x01Stats, nil)
+	mockClient.On("Do", "http://test.com/miner/0x02/currentStats").Return(miner0x02NoData, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/dashboard").Return(Dashboard{Status: "OK", Data: DashboardData{
+		Statistics: history,
+		Workers:    workers,
+		Settings:   settings,
+	}}, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/history").Return(History{Status: "OK", Data: history}, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/payouts").Return(Payouts{Status: "OK", Data: payouts}, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/rounds").Return(Rounds{Status: "OK", Data: []RoundData{{Block: 11565019, Amount: big.NewInt(1200000000000000)}}}, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/workers").Return(Workers{Status: "OK", Data: workers}, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/settings").Return(Settings{Status: "OK", Data: settings}, nil)
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name:     "CurrentStats01",
+			call:     func() (interface{}, error) { r, err := c.GetCurrentStats(ctx, "0x01"); return r, err },
+			wantData: *miner0x01Stats,
+		},
+		{
+			name:     "CurrentStats02",
+			call:     func() (interface{}, error) { r, err := c.GetCurrentStats(ctx, "0x02"); return r, err },
+			wantData: CurrentStats{},
+			wantErr:  ErrAddressNotFound,
+		},
+		{
+			name:     "Dashboard01",
+			call:     func() (interface{}, error) { r, err := c.GetDashboard(ctx, "0x01"); return r.Data.Settings, err },
+			wantData: settings,
+		},
+		{
+			name:     "History01",
+			call:     func() (interface{}, error) { r, err := c.GetHistory(ctx, "0x01"); return r.Data, err },
+			wantData: history,
+		},
+		{
+			name:     "Payouts01",
+			call:     func() (interface{}, error) { r, err := c.GetPayouts(ctx, "0x01"); return r.Data, err },
+			wantData: payouts,
+		},
+		{
+			name:     "Rounds01",
+			call:     func() (interface{}, error) { r, err := c.GetRounds(ctx, "0x01"); return r.Data, err },
+			wantData: []RoundData{{Block: 11565019, Amount: big.NewInt(1200000000000000)}},
+		},
+		{
+			name:     "Workers01",
+			call:     func() (interface{}, error) { r, err := c.GetWorkers(ctx, "0x01"); return r.Data, err },
+			wantData: workers,
+		},
+		{
+			name:     "Settings01",
+			call:     func() (interface{}, error) { r, err := c.GetSettings(ctx, "0x01"); return r.Data, err },
+			wantData: settings,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+}
+
+func Test_ParseCoin(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		wantCoin Coin
+		wantErr  bool
+	}{
+		{name: "Default01", s: "", wantCoin: ETH},
+		{name: "Ticker01", s: "ZEC", wantCoin: ZEC},
+		{name: "Unsupported01", s: "xch", wantCoin: Coin("xch"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCoin, err := ParseCoin(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCoin(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+				return
+			}
+			if gotCoin != tt.wantCoin {
+				t.Errorf("ParseCoin(%q) = %v, want %v", tt.s, gotCoin, tt.wantCoin)
+			}
+		})
+	}
+	if got := NewClient(WithCoin(ZEC)); got.BaseURL() != FlypoolZcashBaseURL || got.Decimals() != 8 {
+		t.Errorf("NewClient(WithCoin(ZEC)) = %s with %d decimals, want %s with 8", got.BaseURL(), got.Decimals(), FlypoolZcashBaseURL)
+	}
+}
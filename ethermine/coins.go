@@ -0,0 +1,62 @@
+package ethermine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Coin identifies a coin Ethermine or Flypool runs a pool for, its value is the coin's ticker
+type Coin string
+
+// Coins supported by Ethermine and Flypool
+const (
+	ETH Coin = "eth"
+	ETC Coin = "etc"
+	ZEC Coin = "zec"
+	RVN Coin = "rvn"
+)
+
+// CoinInfo is where the API of a coin is served and the unit its amounts are given in
+type CoinInfo struct {
+	Name    string
+	BaseURL string
+	// Decimals is the number of decimal places of the coin's smallest unit, e.g. 18 for wei
+	Decimals int
+}
+
+var coins = map[Coin]CoinInfo{
+	ETH: {Name: "Ethereum", BaseURL: DefaultBaseURL, Decimals: 18},
+	ETC: {Name: "Ethereum Classic", BaseURL: EthereumClassicBaseURL, Decimals: 18},
+	ZEC: {Name: "Zcash", BaseURL: FlypoolZcashBaseURL, Decimals: 8},
+	RVN: {Name: "Ravencoin", BaseURL: FlypoolRavencoinBaseURL, Decimals: 8},
+}
+
+// Coins returns every coin supported by Ethermine and Flypool
+func Coins() []Coin {
+	return []Coin{ETH, ETC, ZEC, RVN}
+}
+
+// ParseCoin maps a ticker such as "ETH" or "zec" to a Coin, an empty string maps to ETH
+func ParseCoin(s string) (coin Coin, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ETH, nil
+	}
+	coin = Coin(s)
+	if _, ok := coins[coin]; !ok {
+		err = fmt.Errorf("unsupported ethermine coin %q, expected one of %v", s, Coins())
+	}
+	return
+}
+
+// Info returns where the coin is served and its decimals, unknown coins are treated like ETH
+func (c Coin) Info() CoinInfo {
+	if info, ok := coins[c]; ok {
+		return info
+	}
+	return coins[ETH]
+}
+
+func (c Coin) String() string {
+	return string(c)
+}
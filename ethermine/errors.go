@@ -0,0 +1,98 @@
+package ethermine
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"mining-tools/pool"
+)
+
+var (
+	// ErrAddressNotFound is returned when Ethermine does not know the requested miner or rejects the address
+	ErrAddressNotFound = errors.New("address not found")
+	// ErrRateLimited is returned when Ethermine rejected the request for exceeding its request budget
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer is returned when Ethermine answered with a 5xx status
+	ErrServer = errors.New("server error")
+	// ErrMalformedResponse is returned when the response body could not be decoded
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrRequestFailed is returned for any other non-200 status or non-OK status
+	ErrRequestFailed = errors.New("request failed")
+)
+
+// noData is the data Ethermine answers with for miners it has no statistics for
+const noData = `"NO DATA"`
+
+// APIError describes a failed call to an Ethermine endpoint, Err is one of the Err* sentinels above so
+// callers can branch with errors.Is
+type APIError struct {
+	Endpoint   string
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ethermine %s: %s (HTTP %d)", e.Endpoint, e.Err, e.StatusCode)
+	}
+	return fmt.Sprintf("ethermine %s: %s (HTTP %d): %s", e.Endpoint, e.Err, e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error describing the kind of failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets callers match the pool package sentinels, so code written against pool.Pool can branch on Ethermine failures
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case pool.ErrAccountNotFound:
+		return e.Err == ErrAddressNotFound
+	case pool.ErrRateLimited:
+		return e.Err == ErrRateLimited
+	}
+	return false
+}
+
+// checkResponse turns an HTTP status and a decoded {status,error,data} envelope in to an *APIError, or nil if the call succeeded
+func checkResponse(endpoint string, statusCode int, envelope *ErrorResponse) (err error) {
+	apiErr := &APIError{
+		Endpoint:   endpoint,
+		StatusCode: statusCode,
+	}
+	if envelope != nil {
+		apiErr.Message = envelope.Error
+	}
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		apiErr.Err = ErrRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		apiErr.Err = ErrServer
+	case envelope == nil:
+		apiErr.Err = ErrMalformedResponse
+	case statusCode < 200 || statusCode >= 300 || envelope.Status != "OK":
+		apiErr.Err = classifyMessage(statusCode, envelope.Error)
+	case string(envelope.Data) == noData:
+		apiErr.Message = "NO DATA"
+		apiErr.Err = ErrAddressNotFound
+	default:
+		return nil
+	}
+	return apiErr
+}
+
+func classifyMessage(statusCode int, message string) error {
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "invalid address") || strings.Contains(message, "not found"):
+		return ErrAddressNotFound
+	case strings.Contains(message, "rate limit") || strings.Contains(message, "too many"):
+		return ErrRateLimited
+	case statusCode == http.StatusNotFound:
+		return ErrAddressNotFound
+	}
+	return ErrRequestFailed
+}
@@ -0,0 +1,204 @@
+package ethermine
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"mining-tools/pool"
+)
+
+// statsTTL is how long a Miner:Statistics response is reused, Ethermine only refreshes them every few minutes and
+// several Pool methods are answered from the same response
+const statsTTL = time.Minute
+
+// Pool adapts a Client to the pool.Pool interface, it also reports share quality, reported hashrate and estimated earnings
+type Pool struct {
+	client *Client
+	scale  *big.Int
+
+	mu    sync.Mutex
+	stats map[string]cachedStats
+}
+
+type cachedStats struct {
+	stats   CurrentStats
+	expires time.Time
+}
+
+// NewPool returns a pool.Pool backed by client
+func NewPool(client *Client) *Pool {
+	return &Pool{
+		client: client,
+		scale:  new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(client.Decimals())), nil),
+		stats:  make(map[string]cachedStats),
+	}
+}
+
+// currentStats returns the Miner:Statistics of miner, reusing a response younger than statsTTL
+func (p *Pool) currentStats(ctx context.Context, miner string) (stats CurrentStats, err error) {
+	p.mu.Lock()
+	cached, ok := p.stats[miner]
+	p.mu.Unlock()
+	if ok && cached.expires.After(time.Now()) {
+		return cached.stats, nil
+	}
+	stats, err = p.client.GetCurrentStats(ctx, miner)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	p.stats[miner] = cachedStats{stats: stats, expires: time.Now().Add(statsTTL)}
+	p.mu.Unlock()
+	return
+}
+
+// Coin returns the ticker of the coin mined on the client's API
+func (p *Pool) Coin() string {
+	return p.client.Coin().String()
+}
+
+// Client returns the Ethermine Client behind the Pool
+func (p *Pool) Client() *Client {
+	return p.client
+}
+
+// coins converts an amount in the smallest unit of the coin to whole coins, a missing amount is zero
+func (p *Pool) coins(units *big.Int) *big.Rat {
+	if units == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(units, p.scale)
+}
+
+// Balance returns the unpaid and unconfirmed balance of miner from the Miner:Statistics endpoint
+func (p *Pool) Balance(ctx context.Context, miner string) (balance pool.Balance, err error) {
+	stats, err := p.currentStats(ctx, miner)
+	if err != nil {
+		return
+	}
+	balance.Confirmed = p.coins(stats.Data.Unpaid)
+	balance.Unconfirmed = p.coins(stats.Data.Unconfirmed)
+	return
+}
+
+// Hashrate returns the current and 24 hour average effective hashrate of miner
+func (p *Pool) Hashrate(ctx context.Context, miner string) (hashrate pool.Hashrate, err error) {
+	stats, err := p.currentStats(ctx, miner)
+	if err != nil {
+		return
+	}
+	hashrate.Current = stats.Data.CurrentHashrate
+	hashrate.Average = stats.Data.AverageHashrate
+	return
+}
+
+// Workers returns the workers of miner with their 24 hour average hashrate
+func (p *Pool) Workers(ctx context.Context, miner string) (workers []pool.Worker, err error) {
+	ew, err := p.client.GetWorkers(ctx, miner)
+	if err != nil {
+		return
+	}
+	for _, w := range ew.Data {
+		workers = append(workers, pool.Worker{
+			Name:            w.Worker,
+			Hashrate:        w.CurrentHashrate,
+			AverageHashrate: w.AverageHashrate,
+			LastShare:       time.Unix(w.LastSeen, 0).UTC(),
+		})
+	}
+	return
+}
+
+// ShareHistory returns the valid shares of miner in each 10 minute slot of the last day
+func (p *Pool) ShareHistory(ctx context.Context, miner string) (slots []pool.ShareSlot, err error) {
+	history, err := p.client.GetHistory(ctx, miner)
+	if err != nil {
+		return
+	}
+	for _, h := range history.Data {
+		slots = append(slots, pool.ShareSlot{Time: time.Unix(h.Time, 0).UTC(), Shares: h.ValidShares})
+	}
+	return
+}
+
+// Payments returns the payouts sent to miner, Ethermine only lists payouts once they are sent so all are confirmed
+func (p *Pool) Payments(ctx context.Context, miner string) (payments []pool.Payment, err error) {
+	payouts, err := p.client.GetPayouts(ctx, miner)
+	if err != nil {
+		return
+	}
+	for _, pd := range payouts.Data {
+		payments = append(payments, pool.Payment{
+			Time:      time.Unix(pd.PaidOn, 0).UTC(),
+			TXHash:    pd.TXHash,
+			Amount:    p.coins(pd.Amount),
+			Confirmed: true,
+		})
+	}
+	return
+}
+
+// PayoutThreshold returns the unpaid balance at which miner is paid out from the Miner:Settings endpoint
+func (p *Pool) PayoutThreshold(ctx context.Context, miner string) (threshold *big.Rat, err error) {
+	settings, err := p.client.GetSettings(ctx, miner)
+	if err != nil {
+		return
+	}
+	return p.coins(settings.Data.MinPayout), nil
+}
+
+// ShareCounts returns the valid, stale and invalid shares of miner over the last hour
+func (p *Pool) ShareCounts(ctx context.Context, miner string) (counts pool.ShareCounts, err error) {
+	stats, err := p.currentStats(ctx, miner)
+	if err != nil {
+		return
+	}
+	counts.Valid = stats.Data.ValidShares
+	counts.Stale = stats.Data.StaleShares
+	counts.Invalid = stats.Data.InvalidShares
+	return
+}
+
+// ReportedHashrate returns the hashrate the rigs of miner report
+func (p *Pool) ReportedHashrate(ctx context.Context, miner string) (hashrate float64, err error) {
+	stats, err := p.currentStats(ctx, miner)
+	if err != nil {
+		return
+	}
+	return stats.Data.ReportedHashrate, nil
+}
+
+// EstimatedDailyEarnings returns the coins Ethermine expects miner to earn in a day at its current hashrate
+func (p *Pool) EstimatedDailyEarnings(ctx context.Context, miner string) (coins float64, err error) {
+	stats, err := p.currentStats(ctx, miner)
+	if err != nil {
+		return
+	}
+	return stats.Data.CoinsPerMin * 60 * 24, nil
+}
+
+// Prices derives the price of the coin from what Ethermine expects miner to earn a minute in coins, USD and BTC. The
+// error matches pool.ErrUnsupported while miner earns nothing to derive the prices from
+func (p *Pool) Prices(ctx context.Context, miner string) (prices pool.Prices, err error) {
+	stats, err := p.currentStats(ctx, miner)
+	if err != nil {
+		return
+	}
+	if stats.Data.CoinsPerMin <= 0 {
+		return prices, fmt.Errorf("ethermine prices of %s: no earnings to derive them from: %w", miner, pool.ErrUnsupported)
+	}
+	coinsPerMin := decimalRat(stats.Data.CoinsPerMin)
+	prices.USD = new(big.Rat).Quo(decimalRat(stats.Data.USDPerMin), coinsPerMin)
+	prices.BTC = new(big.Rat).Quo(decimalRat(stats.Data.BTCPerMin), coinsPerMin)
+	return
+}
+
+// decimalRat converts a float to a big.Rat through its shortest decimal form, so 0.0000125 stays exactly 0.0000125
+func decimalRat(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	return r
+}
@@ -0,0 +1,116 @@
+package ethermine
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/pool"
+)
+
+func Test_Pool(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	p := NewPool(NewClient(WithBaseURL("http://test.com/"), WithHTTPClient(mockClient)))
+	ctx := context.Background()
+	mockClient.On("Do", "http://test.com/miner/0x01/currentStats").Return(miner0x01Stats, nil).Once()
+	mockClient.On("Do", "http://test.com/miner/0x02/currentStats").Return(miner0x02NoData, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/history").Return(History{Status: "OK", Data: []HistoryData{{Time: 1609459200, ValidShares: 32}}}, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/workers").Return(Workers{Status: "OK", Data: []WorkerData{{Worker: "rig1", LastSeen: 1609459200, CurrentHashrate: 95500000, AverageHashrate: 90000000}}}, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/payouts").Return(Payouts{Status: "OK", Data: []PayoutData{{Amount: big.NewInt(100000000000000000), TXHash: "0x02", PaidOn: 1609459200}}}, nil)
+	mockClient.On("Do", "http://test.com/miner/0x01/settings").Return(Settings{Status: "OK", Data: SettingsData{MinPayout: big.NewInt(200000000000000000)}}, nil)
+	at := time.Unix(1609459200, 0).UTC()
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name:     "Balance01",
+			call:     func() (interface{}, error) { return p.Balance(ctx, "0x01") },
+			wantData: pool.Balance{Confirmed: big.NewRat(142, 1000), Unconfirmed: big.NewRat(15, 10000)},
+		},
+		{
+			name:     "Balance02",
+			call:     func() (interface{}, error) { return p.Balance(ctx, "0x02") },
+			wantData: pool.Balance{},
+			wantErr:  pool.ErrAccountNotFound,
+		},
+		{
+			name:     "Hashrate01",
+			call:     func() (interface{}, error) { return p.Hashrate(ctx, "0x01") },
+			wantData: pool.Hashrate{Current: 190500000, Average: 185000000},
+		},
+		{
+			name:     "ShareCounts01",
+			call:     func() (interface{}, error) { return p.ShareCounts(ctx, "0x01") },
+			wantData: pool.ShareCounts{Valid: 190, Stale: 4, Invalid: 1},
+		},
+		{
+			name:     "ReportedHashrate01",
+			call:     func() (interface{}, error) { return p.ReportedHashrate(ctx, "0x01") },
+			wantData: float64(200400000),
+		},
+		{
+			name:     "Prices01",
+			call:     func() (interface{}, error) { return p.Prices(ctx, "0x01") },
+			wantData: pool.Prices{USD: big.NewRat(728, 1), BTC: big.NewRat(24, 1000)},
+		},
+		{
+			name:     "EstimatedDailyEarnings01",
+			call:     func() (interface{}, error) { return p.EstimatedDailyEarnings(ctx, "0x01") },
+			wantData: miner0x01Stats.Data.CoinsPerMin * 60 * 24,
+		},
+		{
+			name:     "Workers01",
+			call:     func() (interface{}, error) { return p.Workers(ctx, "0x01") },
+			wantData: []pool.Worker{{Name: "rig1", Hashrate: 95500000, AverageHashrate: 90000000, LastShare: at}},
+		},
+		{
+			name:     "ShareHistory01",
+			call:     func() (interface{}, error) { return p.ShareHistory(ctx, "0x01") },
+			wantData: []pool.ShareSlot{{Time: at, Shares: 32}},
+		},
+		{
+			name:     "Payments01",
+			call:     func() (interface{}, error) { return p.Payments(ctx, "0x01") },
+			wantData: []pool.Payment{{Time: at, TXHash: "0x02", Amount: big.NewRat(1, 10), Confirmed: true}},
+		},
+		{
+			name:     "PayoutThreshold01",
+			call:     func() (interface{}, error) { return p.PayoutThreshold(ctx, "0x01") },
+			wantData: big.NewRat(1, 5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+	// currentStats is only requested once thanks to the memo, a second request would fail the Once expectation
+	mockClient.AssertNumberOfCalls(t, "Do", 6)
+}
+
+func Test_PoolDecimals(t *testing.T) {
+	mockClient := &mockAPIClient{}
+	p := NewPool(NewClient(WithBaseURL("http://test.com/"), WithDecimals(8), WithHTTPClient(mockClient)))
+	mockClient.On("Do", "http://test.com/miner/t1/currentStats").Return(CurrentStats{Status: "OK", Data: CurrentStatsData{Unpaid: big.NewInt(150000000)}}, nil)
+	balance, err := p.Balance(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("Pool.Balance() error = %v", err)
+	}
+	if balance.Confirmed.Cmp(big.NewRat(3, 2)) != 0 || balance.Unconfirmed.Sign() != 0 {
+		t.Errorf("Pool.Balance() = %v, want 1.5 confirmed and nothing unconfirmed", balance)
+	}
+}
@@ -0,0 +1,146 @@
+package ethermine
+
+import (
+	"encoding/json"
+	"math/big"
+)
+
+// ErrorResponse is a struct for marshaling json of any error coming from Ethermine's API, unknown miners are answered
+// with status OK and the data "NO DATA"
+type ErrorResponse struct {
+	Status string          `json:"status"`
+	Error  string          `json:"error"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Dashboard is for decoding json from a successful response of the ethermine miner dashboard api endpoint
+type Dashboard struct {
+	Status string        `json:"status"`
+	Data   DashboardData `json:"data"`
+}
+
+// DashboardData is for decoding json from a successful response of the ethermine miner dashboard api endpoint
+type DashboardData struct {
+	Statistics        []HistoryData              `json:"statistics"`
+	Workers           []WorkerData               `json:"workers"`
+	CurrentStatistics DashboardCurrentStatistics `json:"currentStatistics"`
+	Settings          SettingsData               `json:"settings"`
+}
+
+// DashboardCurrentStatistics is for decoding json from a successful response of the ethermine miner dashboard api endpoint
+type DashboardCurrentStatistics struct {
+	Time             int64    `json:"time"`
+	LastSeen         int64    `json:"lastSeen"`
+	ReportedHashrate float64  `json:"reportedHashrate"`
+	CurrentHashrate  float64  `json:"currentHashrate"`
+	ValidShares      int64    `json:"validShares"`
+	InvalidShares    int64    `json:"invalidShares"`
+	StaleShares      int64    `json:"staleShares"`
+	ActiveWorkers    int64    `json:"activeWorkers"`
+	Unpaid           *big.Int `json:"unpaid"`
+}
+
+// CurrentStats is for decoding json from a successful response of the ethermine miner current stats api endpoint
+type CurrentStats struct {
+	Status string           `json:"status"`
+	Data   CurrentStatsData `json:"data"`
+}
+
+// CurrentStatsData is for decoding json from a successful response of the ethermine miner current stats api endpoint,
+// hashrates are in H/s, share counts cover the last hour and amounts are in the smallest unit of the coin
+type CurrentStatsData struct {
+	Time             int64    `json:"time"`
+	LastSeen         int64    `json:"lastSeen"`
+	ReportedHashrate float64  `json:"reportedHashrate"`
+	CurrentHashrate  float64  `json:"currentHashrate"`
+	AverageHashrate  float64  `json:"averageHashrate"`
+	ValidShares      int64    `json:"validShares"`
+	InvalidShares    int64    `json:"invalidShares"`
+	StaleShares      int64    `json:"staleShares"`
+	ActiveWorkers    int64    `json:"activeWorkers"`
+	Unpaid           *big.Int `json:"unpaid"`
+	Unconfirmed      *big.Int `json:"unconfirmed"`
+	CoinsPerMin      float64  `json:"coinsPerMin"`
+	USDPerMin        float64  `json:"usdPerMin"`
+	BTCPerMin        float64  `json:"btcPerMin"`
+}
+
+// History is for decoding json from a successful response of the ethermine miner history api endpoint
+type History struct {
+	Status string        `json:"status"`
+	Data   []HistoryData `json:"data"`
+}
+
+// HistoryData is for decoding json from a successful response of the ethermine miner history api endpoint,
+// one entry is kept for every 10 minutes of the last day
+type HistoryData struct {
+	Time             int64   `json:"time"`
+	ReportedHashrate float64 `json:"reportedHashrate"`
+	CurrentHashrate  float64 `json:"currentHashrate"`
+	AverageHashrate  float64 `json:"averageHashrate,omitempty"`
+	ValidShares      int64   `json:"validShares"`
+	InvalidShares    int64   `json:"invalidShares"`
+	StaleShares      int64   `json:"staleShares"`
+	ActiveWorkers    int64   `json:"activeWorkers"`
+}
+
+// Payouts is for decoding json from a successful response of the ethermine miner payouts api endpoint
+type Payouts struct {
+	Status string       `json:"status"`
+	Data   []PayoutData `json:"data"`
+}
+
+// PayoutData is for decoding json from a successful response of the ethermine miner payouts api endpoint
+type PayoutData struct {
+	Start  int64    `json:"start"`
+	End    int64    `json:"end"`
+	Amount *big.Int `json:"amount"`
+	TXHash string   `json:"txHash"`
+	PaidOn int64    `json:"paidOn"`
+}
+
+// Rounds is for decoding json from a successful response of the ethermine miner rounds api endpoint
+type Rounds struct {
+	Status string      `json:"status"`
+	Data   []RoundData `json:"data"`
+}
+
+// RoundData is for decoding json from a successful response of the ethermine miner rounds api endpoint
+type RoundData struct {
+	Block  int64    `json:"block"`
+	Amount *big.Int `json:"amount"`
+}
+
+// Workers is for decoding json from a successful response of the ethermine miner workers api endpoint
+type Workers struct {
+	Status string       `json:"status"`
+	Data   []WorkerData `json:"data"`
+}
+
+// WorkerData is for decoding json from a successful response of the ethermine miner workers api endpoint
+type WorkerData struct {
+	Worker           string  `json:"worker"`
+	Time             int64   `json:"time"`
+	LastSeen         int64   `json:"lastSeen"`
+	ReportedHashrate float64 `json:"reportedHashrate"`
+	CurrentHashrate  float64 `json:"currentHashrate"`
+	AverageHashrate  float64 `json:"averageHashrate,omitempty"`
+	ValidShares      int64   `json:"validShares"`
+	InvalidShares    int64   `json:"invalidShares"`
+	StaleShares      int64   `json:"staleShares"`
+}
+
+// Settings is for decoding json from a successful response of the ethermine miner settings api endpoint
+type Settings struct {
+	Status string       `json:"status"`
+	Data   SettingsData `json:"data"`
+}
+
+// SettingsData is for decoding json from a successful response of the ethermine miner settings api endpoint,
+// MinPayout is the unpaid balance at which the miner is paid out
+type SettingsData struct {
+	Email     string   `json:"email"`
+	Monitor   int64    `json:"monitor"`
+	MinPayout *big.Int `json:"minPayout"`
+	IP        string   `json:"ip,omitempty"`
+}
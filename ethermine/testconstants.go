@@ -0,0 +1,33 @@
+package ethermine
+
+import "math/big"
+
+var (
+	miner0x01Stats = &CurrentStats{
+		Status: "OK",
+		Data: CurrentStatsData{
+			Time:             1609459200,
+			LastSeen:         1609459180,
+			ReportedHashrate: 200400000,
+			CurrentHashrate:  190500000,
+			AverageHashrate:  185000000,
+			ValidShares:      190,
+			InvalidShares:    1,
+			StaleShares:      4,
+			ActiveWorkers:    2,
+			Unpaid:           big.NewInt(142000000000000000),
+			Unconfirmed:      big.NewInt(1500000000000000),
+			CoinsPerMin:      0.0000125,
+			USDPerMin:        0.0091,
+			BTCPerMin:        0.0000003,
+		},
+	}
+	miner0x02NoData = &ErrorResponse{
+		Status: "OK",
+		Data:   []byte(`"NO DATA"`),
+	}
+	miner0x03Invalid = &ErrorResponse{
+		Status: "ERROR",
+		Error:  "Invalid address",
+	}
+)
@@ -0,0 +1,131 @@
+// Package ethminer is a client for the JSON-RPC API ethminer serves over TCP when started with --api-bind or
+// --api-port, reporting the stats of the rig it runs on
+package ethminer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPort is the port ethminer's API listens on in most setups
+	DefaultPort = "3333"
+	// DefaultTimeout is the per-call timeout a Client applies when WithTimeout is not given
+	DefaultTimeout = 5 * time.Second
+)
+
+// Dialer is an interface to abstract net.Dialer to support testing
+type Dialer interface {
+	DialContext(ctx context.Context, network string, address string) (conn net.Conn, err error)
+}
+
+// Client talks to the API of a single ethminer instance, opening a connection for each call
+type Client struct {
+	address  string
+	password string
+	dialer   Dialer
+	timeout  time.Duration
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithPassword sets the password ethminer's API was started with through --api-password
+func WithPassword(password string) Option {
+	return func(c *Client) {
+		c.password = password
+	}
+}
+
+// WithDialer sets how connections to the API are opened
+func WithDialer(dialer Dialer) Option {
+	return func(c *Client) {
+		c.dialer = dialer
+	}
+}
+
+// WithTimeout sets the timeout applied to each call, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// NewClient returns a Client for the ethminer API listening on address, a host:port, adjusted by options
+func NewClient(address string, options ...Option) *Client {
+	c := &Client{
+		address: address,
+		dialer:  &net.Dialer{},
+		timeout: DefaultTimeout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Address returns the host:port of the API
+func (c *Client) Address() string {
+	return c.address
+}
+
+// GetStat1 calls miner_getstat1 and returns its Claymore compatible result, a list of strings packing values with
+// semicolons
+func (c *Client) GetStat1(ctx context.Context) (stat []string, err error) {
+	err = c.call(ctx, "miner_getstat1", &stat)
+	return
+}
+
+// GetStatDetail calls miner_getstatdetail and forms the response in to a usable Struct
+func (c *Client) GetStatDetail(ctx context.Context) (detail StatDetail, err error) {
+	err = c.call(ctx, "miner_getstatdetail", &detail)
+	return
+}
+
+// call sends a single request for method within the per-call timeout and decodes its result in to output
+func (c *Client) call(ctx context.Context, method string, output interface{}) (err error) {
+	log.Debugf("Client.call(address=%s, method=%s, output interface{}) called\n", c.address, method)
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		log.Errorf("Client.call: c.dialer.DialContext(ctx, tcp, %s); returned err=%s\n", c.address, err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrUnreachable}
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	request, _ := json.Marshal(Request{ID: 1, JSONRPC: "2.0", Method: method, Password: c.password})
+	if _, err = conn.Write(append(request, '\n')); err != nil {
+		log.Errorf("Client.call: conn.Write(%s); returned err=%s\n", method, err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrUnreachable}
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		log.Errorf("Client.call: reader.ReadBytes(); returned err=%s\n", err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrUnreachable}
+	}
+	response := new(Response)
+	if err = json.Unmarshal(line, response); err != nil {
+		log.Errorf("Client.call: json.Unmarshal(line, response); returned err=%s\n", err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	if err = checkResponse(method, response); err != nil {
+		log.Errorf("Client.call: checkResponse(%s, response); returned err=%s\n", method, err.Error())
+		return
+	}
+	if err = json.Unmarshal(response.Result, output); err != nil {
+		log.Errorf("Client.call: json.Unmarshal(response.Result, output); returned err=%s\n", err.Error())
+		return &APIError{Method: method, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	return
+}
@@ -0,0 +1,168 @@
+package ethminer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"mining-tools/rig"
+	"mining-tools/rig/rigtest"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+// reply answers every request with body
+func reply(body string) rigtest.Handler {
+	return func(request []byte) []byte {
+		return []byte(body)
+	}
+}
+
+func Test_callErrors(t *testing.T) {
+	closed := rigtest.NewServer(reply(""))
+	closed.Close()
+	tests := []struct {
+		name    string
+		address string
+		handler rigtest.Handler
+		wantErr error
+	}{
+		{
+			name:    "Success01",
+			handler: reply(statDetailRig1),
+			wantErr: nil,
+		},
+		{
+			name:    "Unreachable01",
+			address: closed.Addr,
+			wantErr: ErrUnreachable,
+		},
+		{
+			name:    "Unreachable02",
+			handler: func(request []byte) []byte { return nil },
+			wantErr: rig.ErrUnreachable,
+		},
+		{
+			name:    "Unauthorized01",
+			handler: reply(`{"id":1,"jsonrpc":"2.0","error":{"code":-401,"message":"Authorization required"}}` + "\n"),
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "Malformed01",
+			handler: reply("garbage\n"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "Malformed02",
+			handler: reply(`{"id":1,"jsonrpc":"2.0","result":{"mining":{"hashrate":"fast"}}}` + "\n"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "RequestFailed01",
+			handler: reply(`{"id":1,"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"}}` + "\n"),
+			wantErr: ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.address
+			if tt.handler != nil {
+				server := rigtest.NewServer(tt.handler)
+				defer server.Close()
+				address = server.Addr
+			}
+			_, err := NewClient(address).GetStatDetail(context.Background())
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetStatDetail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.Method != "miner_getstatdetail") {
+				t.Errorf("Client.GetStatDetail() error = %#v, want *APIError for miner_getstatdetail", err)
+			}
+		})
+	}
+}
+
+func Test_methods(t *testing.T) {
+	// Set up
+	server := rigtest.NewServer(func(request []byte) []byte {
+		var r Request
+		json.Unmarshal(request, &r)
+		switch {
+		case r.Password != "secret":
+			return []byte(`{"id":1,"jsonrpc":"2.0","error":{"code":-403,"message":"Wrong password"}}` + "\n")
+		case r.Method == "miner_getstat1":
+			return []byte(stat1Rig1)
+		case r.Method == "miner_getstatdetail":
+			return []byte(statDetailRig1)
+		}
+		return nil
+	})
+	defer server.Close()
+	c := NewClient(server.Addr, WithPassword("secret"))
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name:     "GetStat1",
+			call:     func() (interface{}, error) { return c.GetStat1(ctx) },
+			wantData: []string{"0.19.0", "60", "58800;185;1", "30000;28800", "0;0;0", "off;off", "61;55;65;60", "eth-eu1.nanopool.org:9999", "1;1;0;0"},
+		},
+		{
+			name: "GetStatDetail01",
+			call: func() (interface{}, error) { r, err := c.GetStatDetail(ctx); return r.Devices[1], err },
+			wantData: StatDetailDevice{
+				Index:    1,
+				Mode:     "CUDA",
+				Hardware: StatDetailHardware{Name: "GeForce GTX 1070 7.93 GB", PCI: "02:00.0", Sensors: []float64{65, 60}, Type: "GPU"},
+				Mining:   StatDetailDeviceMining{Hashrate: 28800000, Segment: []Hex{1, 2}, Shares: []int64{90, 0, 1, 40}},
+			},
+		},
+		{
+			name:     "GetStatDetail02",
+			call:     func() (interface{}, error) { r, err := c.GetStatDetail(ctx); return r.Host, err },
+			wantData: StatDetailHost{Name: "rig1", Runtime: 3600, Version: "ethminer-0.19.0"},
+		},
+		{
+			name:     "WrongPassword01",
+			call:     func() (interface{}, error) { return NewClient(server.Addr, WithPassword("guess")).GetStat1(ctx) },
+			wantData: []string(nil),
+			wantErr:  ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+}
+
+func Test_HexJSON(t *testing.T) {
+	var h Hex
+	if err := json.Unmarshal([]byte(`"0x0000000001c9c380"`), &h); err != nil || h != 30000000 {
+		t.Errorf("Hex.UnmarshalJSON() = %d, %v, want 30000000", h, err)
+	}
+	if b, _ := json.Marshal(h); string(b) != `"0x0000000001c9c380"` {
+		t.Errorf("Hex.MarshalJSON() = %s, want %s", b, `"0x0000000001c9c380"`)
+	}
+	if err := json.Unmarshal([]byte(`"1c9c380"`), &h); err == nil {
+		t.Errorf("Hex.UnmarshalJSON() accepted a number without the 0x prefix")
+	}
+}
@@ -0,0 +1,61 @@
+package ethminer
+
+import (
+	"errors"
+	"fmt"
+
+	"mining-tools/rig"
+)
+
+var (
+	// ErrUnreachable is returned when no connection could be made to the API or it closed before answering
+	ErrUnreachable = errors.New("unreachable")
+	// ErrUnauthorized is returned when the API was started with a password and the Client sent none or a wrong one
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrMalformedResponse is returned when the response could not be decoded
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrRequestFailed is returned for any other JSON-RPC error
+	ErrRequestFailed = errors.New("request failed")
+)
+
+// APIError describes a failed call to an ethminer method, Err is one of the Err* sentinels above so callers can
+// branch with errors.Is
+type APIError struct {
+	Method  string
+	Code    int
+	Message string
+	Err     error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ethminer %s: %s", e.Method, e.Err)
+	}
+	return fmt.Sprintf("ethminer %s: %s: %s", e.Method, e.Err, e.Message)
+}
+
+// Unwrap returns the sentinel error describing the kind of failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets callers match the rig package sentinels, so code written against rig.Rig can branch on ethminer failures
+func (e *APIError) Is(target error) bool {
+	return target == rig.ErrUnreachable && e.Err == ErrUnreachable
+}
+
+// checkResponse turns the error member of a JSON-RPC response in to an *APIError, or nil if the call succeeded
+func checkResponse(method string, response *Response) (err error) {
+	if response.Error == nil {
+		if response.Result == nil {
+			return &APIError{Method: method, Message: "no result", Err: ErrMalformedResponse}
+		}
+		return nil
+	}
+	apiErr := &APIError{Method: method, Code: response.Error.Code, Message: response.Error.Message, Err: ErrRequestFailed}
+	// ethminer answers -401 when a password is required and -403 when the one sent is wrong
+	if response.Error.Code == -401 || response.Error.Code == -403 {
+		apiErr.Err = ErrUnauthorized
+	}
+	return apiErr
+}
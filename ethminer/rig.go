@@ -0,0 +1,66 @@
+package ethminer
+
+import (
+	"context"
+	"time"
+
+	"mining-tools/rig"
+)
+
+// Rig adapts a Client to the rig.Rig interface
+type Rig struct {
+	client *Client
+}
+
+// NewRig returns a rig.Rig backed by client
+func NewRig(client *Client) *Rig {
+	return &Rig{client: client}
+}
+
+// Client returns the ethminer Client behind the Rig
+func (r *Rig) Client() *Client {
+	return r.client
+}
+
+// Stats returns the hashrate, shares, pool switches and sensor readings of the rig and each of its devices from miner_getstatdetail
+func (r *Rig) Stats(ctx context.Context) (stats rig.Stats, err error) {
+	detail, err := r.client.GetStatDetail(ctx)
+	if err != nil {
+		return
+	}
+	stats.Miner = detail.Host.Version
+	stats.Uptime = time.Duration(detail.Host.Runtime) * time.Second
+	stats.Hashrate = float64(detail.Mining.Hashrate)
+	stats.Shares = shares(detail.Mining.Shares)
+	stats.PoolSwitches = detail.Connection.Switches
+	for _, d := range detail.Devices {
+		stats.Power += sensor(d.Hardware.Sensors, 2)
+		stats.GPUs = append(stats.GPUs, rig.GPU{
+			Index:       d.Index,
+			Name:        d.Hardware.Name,
+			Hashrate:    float64(d.Mining.Hashrate),
+			Temperature: sensor(d.Hardware.Sensors, 0),
+			FanSpeed:    sensor(d.Hardware.Sensors, 1),
+			Power:       sensor(d.Hardware.Sensors, 2),
+			Shares:      shares(d.Mining.Shares),
+		})
+	}
+	return
+}
+
+// sensor returns the reading at i, older ethminer versions send fewer sensors and leave out power
+func sensor(sensors []float64, i int) float64 {
+	if i < len(sensors) {
+		return sensors[i]
+	}
+	return 0
+}
+
+// shares reads the accepted, rejected and failed counts ethminer lists in that order, failed shares are the ones the
+// pool found invalid
+func shares(counts []int64) (s rig.Shares) {
+	if len(counts) >= 3 {
+		s.Accepted, s.Rejected, s.Invalid = counts[0], counts[1], counts[2]
+	}
+	return
+}
@@ -0,0 +1,35 @@
+package ethminer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/rig"
+	"mining-tools/rig/rigtest"
+)
+
+func Test_Rig(t *testing.T) {
+	server := rigtest.NewServer(reply(statDetailRig1))
+	defer server.Close()
+	stats, err := NewRig(NewClient(server.Addr)).Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Rig.Stats() error = %v", err)
+	}
+	want := rig.Stats{
+		Miner:        "ethminer-0.19.0",
+		Uptime:       time.Hour,
+		Hashrate:     58800000,
+		Power:        120,
+		Shares:       rig.Shares{Accepted: 185, Rejected: 1, Invalid: 1},
+		PoolSwitches: 1,
+		GPUs: []rig.GPU{
+			{Index: 0, Name: "GeForce GTX 1070 7.93 GB", Hashrate: 30000000, Temperature: 61, FanSpeed: 55, Power: 120, Shares: rig.Shares{Accepted: 95, Rejected: 1}},
+			{Index: 1, Name: "GeForce GTX 1070 7.93 GB", Hashrate: 28800000, Temperature: 65, FanSpeed: 60, Shares: rig.Shares{Accepted: 90, Invalid: 1}},
+		},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("Rig.Stats() = %+v, want %+v", stats, want)
+	}
+}
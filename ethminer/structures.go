@@ -0,0 +1,115 @@
+package ethminer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Request is a JSON-RPC request to ethminer's API, Password is only sent when the API requires one
+type Request struct {
+	ID       int    `json:"id"`
+	JSONRPC  string `json:"jsonrpc"`
+	Method   string `json:"method"`
+	Password string `json:"psw,omitempty"`
+}
+
+// Response is a JSON-RPC response from ethminer's API, Result is decoded by the method's caller
+type Response struct {
+	ID      int             `json:"id"`
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error member of a failed JSON-RPC response
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Hex is a number ethminer sends as a 0x prefixed hexadecimal string, hashrates in H/s are sent this way
+type Hex uint64
+
+// UnmarshalJSON decodes a 0x prefixed hexadecimal string
+func (h *Hex) UnmarshalJSON(data []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return
+	}
+	if len(s) < 3 || s[:2] != "0x" {
+		return fmt.Errorf("ethminer: %q is not a hexadecimal number", s)
+	}
+	n, err := strconv.ParseUint(s[2:], 16, 64)
+	if err != nil {
+		return
+	}
+	*h = Hex(n)
+	return
+}
+
+// MarshalJSON encodes the number the way ethminer sends it
+func (h Hex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("0x%016x", uint64(h)))
+}
+
+// StatDetail is for decoding json from a successful response of the miner_getstatdetail method
+type StatDetail struct {
+	Connection StatDetailConnection `json:"connection"`
+	Devices    []StatDetailDevice   `json:"devices"`
+	Host       StatDetailHost       `json:"host"`
+	Mining     StatDetailMining     `json:"mining"`
+}
+
+// StatDetailConnection is for decoding json from a successful response of the miner_getstatdetail method,
+// Switches counts the pool connections made since ethminer started
+type StatDetailConnection struct {
+	Connected bool   `json:"connected"`
+	Switches  int64  `json:"switches"`
+	URI       string `json:"uri"`
+}
+
+// StatDetailDevice is for decoding json from a successful response of the miner_getstatdetail method
+type StatDetailDevice struct {
+	Index    int                    `json:"_index"`
+	Mode     string                 `json:"_mode"`
+	Hardware StatDetailHardware     `json:"hardware"`
+	Mining   StatDetailDeviceMining `json:"mining"`
+}
+
+// StatDetailHardware is for decoding json from a successful response of the miner_getstatdetail method,
+// Sensors holds the temperature in °C, the fan speed in percent and the power draw in W
+type StatDetailHardware struct {
+	Name    string    `json:"name"`
+	PCI     string    `json:"pci"`
+	Sensors []float64 `json:"sensors"`
+	Type    string    `json:"type"`
+}
+
+// StatDetailDeviceMining is for decoding json from a successful response of the miner_getstatdetail method,
+// Shares holds the accepted, rejected and failed shares and the seconds since the last share was found
+type StatDetailDeviceMining struct {
+	Hashrate    Hex     `json:"hashrate"`
+	Paused      bool    `json:"paused"`
+	PauseReason *string `json:"pause_reason"`
+	Segment     []Hex   `json:"segment"`
+	Shares      []int64 `json:"shares"`
+}
+
+// StatDetailHost is for decoding json from a successful response of the miner_getstatdetail method,
+// Runtime is in seconds
+type StatDetailHost struct {
+	Name    string `json:"name"`
+	Runtime int64  `json:"runtime"`
+	Version string `json:"version"`
+}
+
+// StatDetailMining is for decoding json from a successful response of the miner_getstatdetail method,
+// Shares holds the accepted, rejected and failed shares and the seconds since the last share was found
+type StatDetailMining struct {
+	Difficulty   float64 `json:"difficulty"`
+	Epoch        int64   `json:"epoch"`
+	EpochChanges int64   `json:"epoch_changes"`
+	Hashrate     Hex     `json:"hashrate"`
+	Shares       []int64 `json:"shares"`
+}
@@ -0,0 +1,16 @@
+package ethminer
+
+// statDetailRig1 is a miner_getstatdetail response of ethminer 0.19 running two GPUs
+const statDetailRig1 = `{"id":1,"jsonrpc":"2.0","result":{` +
+	`"connection":{"connected":true,"switches":1,"uri":"stratum1+tcp://0x01.rig1@eth-eu1.nanopool.org:9999"},` +
+	`"devices":[` +
+	`{"_index":0,"_mode":"CUDA","hardware":{"name":"GeForce GTX 1070 7.93 GB","pci":"01:00.0","sensors":[61,55,120],"type":"GPU"},` +
+	`"mining":{"hashrate":"0x0000000001c9c380","pause_reason":null,"paused":false,"segment":["0x0000000000000000","0x0000000000000001"],"shares":[95,1,0,12]}},` +
+	`{"_index":1,"_mode":"CUDA","hardware":{"name":"GeForce GTX 1070 7.93 GB","pci":"02:00.0","sensors":[65,60],"type":"GPU"},` +
+	`"mining":{"hashrate":"0x0000000001b77400","pause_reason":null,"paused":false,"segment":["0x0000000000000001","0x0000000000000002"],"shares":[90,0,1,40]}}],` +
+	`"host":{"name":"rig1","runtime":3600,"version":"ethminer-0.19.0"},` +
+	`"mining":{"difficulty":3999938964,"epoch":390,"epoch_changes":1,"hashrate":"0x0000000003813780","shares":[185,1,1,12]}}}` + "\n"
+
+// stat1Rig1 is the miner_getstat1 response matching statDetailRig1
+const stat1Rig1 = `{"id":1,"jsonrpc":"2.0","result":["0.19.0","60","58800;185;1","30000;28800","0;0;0","off;off","61;55;65;60",` +
+	`"eth-eu1.nanopool.org:9999","1;1;0;0"]}` + "\n"
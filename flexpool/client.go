@@ -0,0 +1,262 @@
+// Package flexpool is a client for the miner endpoints of version 2 of the Flexpool API
+package flexpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"mining-tools/throttle"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultBaseURL is the root of version 2 of the Flexpool API
+	DefaultBaseURL = "https://api.flexpool.io/v2/"
+	// DefaultCoin is the coin a Client asks about when WithCoin is not given
+	DefaultCoin = ETH
+	// DefaultDecimals is the number of decimal places of the smallest unit amounts are given in, 18 for wei
+	DefaultDecimals = 18
+	// DefaultTimeout is the per-call timeout a Client applies when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+)
+
+// HTTPClient is an interface to abstract http.client to support testing using mocks
+type HTTPClient interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+var (
+	apiClient = HTTPClient(&http.Client{})
+)
+
+// Client talks to the Flexpool API about one coin using its own transport, user agent and timeout
+type Client struct {
+	baseURL    string
+	coin       Coin
+	decimals   int
+	httpClient HTTPClient
+	userAgent  string
+	timeout    time.Duration
+	limiter    *throttle.Limiter
+	retry      throttle.Policy
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithBaseURL sets the API root every endpoint path is appended to, e.g. https://api.flexpool.io/v2/
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithCoin sets the coin every request asks about along with its decimals, e.g. etc
+func WithCoin(coin Coin) Option {
+	return func(c *Client) {
+		c.coin = coin
+		c.decimals = coin.Info().Decimals
+	}
+}
+
+// WithDecimals sets the decimal places of the smallest unit of the coin, e.g. 12 for the mojo of chia
+func WithDecimals(decimals int) Option {
+	return func(c *Client) {
+		c.decimals = decimals
+	}
+}
+
+// WithHTTPClient sets the transport used for every request
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the timeout applied to each call, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRateLimiter sets the limiter every request waits on, share one limiter between all clients of the same API
+func WithRateLimiter(limiter *throttle.Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried, by default a request is attempted once
+func WithRetryPolicy(policy throttle.Policy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// NewClient returns a Client for the ethereum pool of the Flexpool API adjusted by options
+func NewClient(options ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		coin:       DefaultCoin,
+		decimals:   DefaultDecimals,
+		httpClient: apiClient,
+		timeout:    DefaultTimeout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// BaseURL returns the URL every endpoint path is appended to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Coin returns the coin every request asks about
+func (c *Client) Coin() Coin {
+	return c.coin
+}
+
+// Decimals returns the decimal places of the smallest unit amounts are given in
+func (c *Client) Decimals() int {
+	return c.decimals
+}
+
+// GetMinerBalance calls the miner/balance endpoint and forms the response in to a usable Struct, countervalue is the
+// currency the balance is also valued in, e.g. USD
+func (c *Client) GetMinerBalance(ctx context.Context, address string, countervalue string) (balance MinerBalance, err error) {
+	err = c.get(ctx, "miner/balance", c.query(address, "countervalue", countervalue), &balance)
+	return
+}
+
+// GetMinerStats calls the miner/stats endpoint and forms the response in to a usable Struct
+func (c *Client) GetMinerStats(ctx context.Context, address string) (stats MinerStats, err error) {
+	err = c.get(ctx, "miner/stats", c.query(address), &stats)
+	return
+}
+
+// GetMinerWorkers calls the miner/workers endpoint and forms the response in to a usable Struct
+func (c *Client) GetMinerWorkers(ctx context.Context, address string) (workers MinerWorkers, err error) {
+	err = c.get(ctx, "miner/workers", c.query(address), &workers)
+	return
+}
+
+// GetMinerPayments calls the miner/payments endpoint for one page of payments, newest first, and forms the response
+// in to a usable Struct
+func (c *Client) GetMinerPayments(ctx context.Context, address string, page int64) (payments MinerPayments, err error) {
+	err = c.get(ctx, "miner/payments", c.query(address, "page", strconv.FormatInt(page, 10)), &payments)
+	return
+}
+
+// GetMinerRoundShare calls the miner/roundShare endpoint and forms the response in to a usable Struct
+func (c *Client) GetMinerRoundShare(ctx context.Context, address string) (roundShare MinerRoundShare, err error) {
+	err = c.get(ctx, "miner/roundShare", c.query(address), &roundShare)
+	return
+}
+
+// GetMinerDetails calls the miner/details endpoint and forms the response in to a usable Struct
+func (c *Client) GetMinerDetails(ctx context.Context, address string) (details MinerDetails, err error) {
+	err = c.get(ctx, "miner/details", c.query(address), &details)
+	return
+}
+
+// GetMinerChart calls the miner/chart endpoint and forms the response in to a usable Struct
+func (c *Client) GetMinerChart(ctx context.Context, address string) (chart MinerChart, err error) {
+	err = c.get(ctx, "miner/chart", c.query(address), &chart)
+	return
+}
+
+// query returns the coin and address parameters every miner endpoint takes followed by extra name, value pairs
+func (c *Client) query(address string, extra ...string) url.Values {
+	query := url.Values{}
+	query.Set("coin", c.coin.String())
+	query.Set("address", address)
+	for i := 0; i+1 < len(extra); i += 2 {
+		query.Set(extra[i], extra[i+1])
+	}
+	return query
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, query url.Values, output interface{}) (err error) {
+	fullPath := c.baseURL + endpoint + "?" + query.Encode()
+	log.Debugf("Client.get(fullPath=%s, output interface{}) called\n", fullPath)
+	resp, attempts, err := c.retry.Do(ctx, c.limiter, func(ctx context.Context) (*http.Response, error) {
+		return c.send(ctx, fullPath)
+	})
+	defer func() {
+		if err != nil && attempts > 1 {
+			err = &throttle.Error{Attempts: attempts, Err: err}
+		}
+	}()
+	if err != nil {
+		log.Errorf("Client.get: c.send(ctx, %s); returned err=%s after %d attempts\n", fullPath, err.Error(), attempts)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("Client.get: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return
+	}
+	envelope := new(ErrorResponse)
+	if json.Unmarshal(body, envelope) != nil {
+		envelope = nil
+	}
+	err = checkResponse(endpoint, resp.StatusCode, envelope)
+	if err != nil {
+		log.Errorf("Client.get: checkResponse(%s, %d, envelope); returned err=%s after %d attempts\n", endpoint, resp.StatusCode, err.Error(), attempts)
+		return
+	}
+	err = json.Unmarshal(body, output)
+	if err != nil {
+		log.Errorf("Client.get: json.Unmarshal(body, output); returned err=%s\n", err.Error())
+		err = &APIError{Endpoint: endpoint, StatusCode: resp.StatusCode, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	return
+}
+
+// send makes a single attempt at fullPath within the per-call timeout, the body is read before the timeout is released
+func (c *Client) send(ctx context.Context, fullPath string) (resp *http.Response, err error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath, nil)
+	if err != nil {
+		log.Errorf("Client.send: http.NewRequestWithContext(ctx, GET, %s, nil); returned err=%s\n", fullPath, err.Error())
+		return
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err = c.httpClient.Do(req)
+	if err != nil {
+		log.Errorf("Client.send: c.httpClient.Do(%s); returned err=%s\n", fullPath, err.Error())
+		return
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		log.Errorf("Client.send: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return
+}
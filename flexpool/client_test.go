@@ -0,0 +1,221 @@
+package flexpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"mining-tools/pool"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAPIClient struct {
+	mock.Mock
+}
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func (mac *mockAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	args := mac.Called(req.URL.String())
+	body, _ := json.Marshal(args.Get(0))
+	resp = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBuffer(body)),
+	}
+	return resp, args.Error(1)
+}
+
+type cannedAPIClient struct {
+	statusCode int
+	body       string
+}
+
+func (cac *cannedAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	resp = &http.Response{
+		StatusCode: cac.statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(cac.body)),
+	}
+	return
+}
+
+func Test_getErrors(t *testing.T) {
+	errorBody, _ := json.Marshal(miner0x02Error)
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    error
+	}{
+		{
+			name:       "Success01",
+			statusCode: http.StatusOK,
+			body:       `{"error":null,"result":{"balance":142000000000000000,"price":730.51}}`,
+			wantErr:    nil,
+		},
+		{
+			name:       "AddressNotFound01",
+			statusCode: http.StatusBadRequest,
+			body:       string(errorBody),
+			wantErr:    ErrAddressNotFound,
+		},
+		{
+			name:       "AddressNotFound02",
+			statusCode: http.StatusOK,
+			body:       `{"error":"Miner does not exist","result":null}`,
+			wantErr:    pool.ErrAccountNotFound,
+		},
+		{
+			name:       "RateLimited01",
+			statusCode: http.StatusTooManyRequests,
+			body:       `Too Many Requests`,
+			wantErr:    pool.ErrRateLimited,
+		},
+		{
+			name:       "ServerError01",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>Bad Gateway</html>`,
+			wantErr:    ErrServer,
+		},
+		{
+			name:       "Malformed01",
+			statusCode: http.StatusOK,
+			body:       `<html>maintenance</html>`,
+			wantErr:    ErrMalformedResponse,
+		},
+		{
+			name:       "Malformed02",
+			statusCode: http.StatusOK,
+			body:       `{"error":null,"result":{"balance":"lots"}}`,
+			wantErr:    ErrMalformedResponse,
+		},
+		{
+			name:       "RequestFailed01",
+			statusCode: http.StatusOK,
+			body:       `{"error":"Something else","result":null}`,
+			wantErr:    ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(WithBaseURL("http://test.com/v2/"), WithHTTPClient(&cannedAPIClient{tt.statusCode, tt.body}))
+			_, err := c.GetMinerBalance(context.Background(), "0x02", "USD")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetMinerBalance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.statusCode) {
+				t.Errorf("Client.GetMinerBalance() error = %#v, want *APIError with StatusCode %d", err, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_minerEndpoints(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	c := NewClient(WithBaseURL("http://test.com/v2/"), WithCoin("etc"), WithHTTPClient(mockClient))
+	ctx := context.Background()
+	workers := []MinerWorkerResult{{Name: "rig1", IsOnline: true, Count: 1, ReportedHashrate: 100000000, CurrentEffectiveHashrate: 95500000, ValidShares: 2250, LastSeen: 1609459180}}
+	payments := MinerPaymentsResult{Data: []MinerPaymentsData{{Hash: "0x02", Timestamp: 1609459200, Value: big.NewInt(100000000000000000), Fee: big.NewInt(420000000000000), Confirmed: true}}, TotalItems: 1, TotalPages: 1}
+	chart := []MinerChartResult{{Timestamp: 1609459200, ReportedHashrate: 200400000, EffectiveHashrate: 190500000, ValidShares: 32, StaleShares: 1}}
+	details := MinerDetailsResult{FirstJoined: 1600000000, PayoutLimit: big.NewInt(200000000000000000), MaxFeePrice: 80, Network: "mainnet"}
+	mockClient.On("Do", "http://test.com/v2/miner/balance?address=0x01&coin=etc&countervalue=USD").Return(miner0x01Balance, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/stats?address=0x01&coin=etc").Return(miner0x01Stats, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/workers?address=0x01&coin=etc").Return(MinerWorkers{Result: workers}, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/payments?address=0x01&coin=etc&page=1").Return(MinerPayments{Result: payments}, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/roundShare?address=0x01&coin=etc").Return(MinerRoundShare{Result: 0.0012}, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/details?address=0x01&coin=etc").Return(MinerDetails{Result: details}, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/chart?address=0x01&coin=etc").Return(MinerChart{Result: chart}, nil)
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+	}{
+		{
+			name:     "Balance01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerBalance(ctx, "0x01", "USD"); return r, err },
+			wantData: *miner0x01Balance,
+		},
+		{
+			name:     "Stats01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerStats(ctx, "0x01"); return r, err },
+			wantData: *miner0x01Stats,
+		},
+		{
+			name:     "Workers01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerWorkers(ctx, "0x01"); return r.Result, err },
+			wantData: workers,
+		},
+		{
+			name:     "Payments01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerPayments(ctx, "0x01", 1); return r.Result, err },
+			wantData: payments,
+		},
+		{
+			name:     "RoundShare01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerRoundShare(ctx, "0x01"); return r.Result, err },
+			wantData: 0.0012,
+		},
+		{
+			name:     "Details01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerDetails(ctx, "0x01"); return r.Result, err },
+			wantData: details,
+		},
+		{
+			name:     "Chart01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerChart(ctx, "0x01"); return r.Result, err },
+			wantData: chart,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if err != nil {
+				t.Errorf("%s error = %v", tt.name, err)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+}
+
+func Test_ParseCoin(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		wantCoin Coin
+		wantErr  bool
+	}{
+		{name: "Default01", s: "", wantCoin: ETH},
+		{name: "Ticker01", s: "XCH", wantCoin: XCH},
+		{name: "Alias01", s: "chia", wantCoin: XCH},
+		{name: "Unsupported01", s: "ergo", wantCoin: Coin("ergo"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCoin, err := ParseCoin(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCoin(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+				return
+			}
+			if gotCoin != tt.wantCoin {
+				t.Errorf("ParseCoin(%q) = %v, want %v", tt.s, gotCoin, tt.wantCoin)
+			}
+		})
+	}
+	if got := NewClient(WithCoin(XCH)).Decimals(); got != 12 {
+		t.Errorf("NewClient(WithCoin(XCH)).Decimals() = %d, want 12", got)
+	}
+}
@@ -0,0 +1,62 @@
+package flexpool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Coin identifies a coin mined on Flexpool, its value is the coin parameter of the API
+type Coin string
+
+// Coins supported by Flexpool
+const (
+	ETH Coin = "eth"
+	ETC Coin = "etc"
+	XCH Coin = "xch"
+)
+
+// CoinInfo is the unit the amounts of a coin are given in
+type CoinInfo struct {
+	Name string
+	// Decimals is the number of decimal places of the coin's smallest unit, e.g. 12 for the mojo of chia
+	Decimals int
+}
+
+var coins = map[Coin]CoinInfo{
+	ETH: {Name: "Ethereum", Decimals: 18},
+	ETC: {Name: "Ethereum Classic", Decimals: 18},
+	XCH: {Name: "Chia", Decimals: 12},
+}
+
+// Coins returns every coin supported by Flexpool
+func Coins() []Coin {
+	return []Coin{ETH, ETC, XCH}
+}
+
+// ParseCoin maps a ticker such as "ETH" or "xch" to a Coin, "chia" maps to XCH and an empty string to DefaultCoin
+func ParseCoin(s string) (coin Coin, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "":
+		return DefaultCoin, nil
+	case "chia":
+		return XCH, nil
+	}
+	coin = Coin(s)
+	if _, ok := coins[coin]; !ok {
+		err = fmt.Errorf("unsupported flexpool coin %q, expected one of %v", s, Coins())
+	}
+	return
+}
+
+// Info returns the decimals of the coin, unknown coins are treated like ETH
+func (c Coin) Info() CoinInfo {
+	if info, ok := coins[c]; ok {
+		return info
+	}
+	return coins[ETH]
+}
+
+func (c Coin) String() string {
+	return string(c)
+}
@@ -0,0 +1,182 @@
+package flexpool
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"mining-tools/pool"
+)
+
+// statsTTL is how long a miner/stats response is reused, several Pool methods are answered from the same response
+const statsTTL = time.Minute
+
+// Pool adapts a Client to the pool.Pool interface, it also reports share quality and the reported hashrate
+type Pool struct {
+	client *Client
+	scale  *big.Int
+
+	mu    sync.Mutex
+	stats map[string]cachedStats
+}
+
+type cachedStats struct {
+	stats   MinerStats
+	expires time.Time
+}
+
+// NewPool returns a pool.Pool backed by client
+func NewPool(client *Client) *Pool {
+	return &Pool{
+		client: client,
+		scale:  new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(client.Decimals())), nil),
+		stats:  make(map[string]cachedStats),
+	}
+}
+
+// Coin returns the coin the client asks about, as Flexpool names it
+func (p *Pool) Coin() string {
+	return p.client.Coin().String()
+}
+
+// Client returns the Flexpool Client behind the Pool
+func (p *Pool) Client() *Client {
+	return p.client
+}
+
+// minerStats returns the miner/stats of address, reusing a response younger than statsTTL
+func (p *Pool) minerStats(ctx context.Context, address string) (stats MinerStats, err error) {
+	p.mu.Lock()
+	cached, ok := p.stats[address]
+	p.mu.Unlock()
+	if ok && cached.expires.After(time.Now()) {
+		return cached.stats, nil
+	}
+	stats, err = p.client.GetMinerStats(ctx, address)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	p.stats[address] = cachedStats{stats: stats, expires: time.Now().Add(statsTTL)}
+	p.mu.Unlock()
+	return
+}
+
+// coins converts an amount in the smallest unit of the coin to whole coins, a missing amount is zero
+func (p *Pool) coins(units *big.Int) *big.Rat {
+	if units == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(units, p.scale)
+}
+
+// Balance returns the unpaid balance of address, Flexpool only credits confirmed blocks so nothing is unconfirmed
+func (p *Pool) Balance(ctx context.Context, address string) (balance pool.Balance, err error) {
+	mb, err := p.client.GetMinerBalance(ctx, address, "USD")
+	if err != nil {
+		return
+	}
+	balance.Confirmed = p.coins(mb.Result.Balance)
+	balance.Unconfirmed = new(big.Rat)
+	return
+}
+
+// Prices returns the USD price of the coin that the miner/balance endpoint of address values the balance at, Flexpool
+// publishes no BTC price
+func (p *Pool) Prices(ctx context.Context, address string) (prices pool.Prices, err error) {
+	mb, err := p.client.GetMinerBalance(ctx, address, "USD")
+	if err != nil {
+		return
+	}
+	prices.USD, _ = new(big.Rat).SetString(strconv.FormatFloat(mb.Result.Price, 'f', -1, 64))
+	return
+}
+
+// Hashrate returns the current and 24 hour average effective hashrate of address
+func (p *Pool) Hashrate(ctx context.Context, address string) (hashrate pool.Hashrate, err error) {
+	stats, err := p.minerStats(ctx, address)
+	if err != nil {
+		return
+	}
+	hashrate.Current = stats.Result.CurrentEffectiveHashrate
+	hashrate.Average = stats.Result.AverageEffectiveHashrate
+	return
+}
+
+// Workers returns the workers of address with their 24 hour average effective hashrate
+func (p *Pool) Workers(ctx context.Context, address string) (workers []pool.Worker, err error) {
+	mw, err := p.client.GetMinerWorkers(ctx, address)
+	if err != nil {
+		return
+	}
+	for _, w := range mw.Result {
+		workers = append(workers, pool.Worker{
+			Name:            w.Name,
+			Hashrate:        w.CurrentEffectiveHashrate,
+			AverageHashrate: w.AverageEffectiveHashrate,
+			LastShare:       time.Unix(w.LastSeen, 0).UTC(),
+		})
+	}
+	return
+}
+
+// ShareHistory returns the valid shares of address in each 10 minute slot of the last day
+func (p *Pool) ShareHistory(ctx context.Context, address string) (slots []pool.ShareSlot, err error) {
+	chart, err := p.client.GetMinerChart(ctx, address)
+	if err != nil {
+		return
+	}
+	for _, c := range chart.Result {
+		slots = append(slots, pool.ShareSlot{Time: time.Unix(c.Timestamp, 0).UTC(), Shares: c.ValidShares})
+	}
+	return
+}
+
+// Payments returns the latest page of payouts sent to address
+func (p *Pool) Payments(ctx context.Context, address string) (payments []pool.Payment, err error) {
+	mp, err := p.client.GetMinerPayments(ctx, address, 0)
+	if err != nil {
+		return
+	}
+	for _, pd := range mp.Result.Data {
+		payments = append(payments, pool.Payment{
+			Time:      time.Unix(pd.Timestamp, 0).UTC(),
+			TXHash:    pd.Hash,
+			Amount:    p.coins(pd.Value),
+			Confirmed: pd.Confirmed,
+		})
+	}
+	return
+}
+
+// PayoutThreshold returns the balance at which address is paid out from the miner/details endpoint
+func (p *Pool) PayoutThreshold(ctx context.Context, address string) (threshold *big.Rat, err error) {
+	details, err := p.client.GetMinerDetails(ctx, address)
+	if err != nil {
+		return
+	}
+	return p.coins(details.Result.PayoutLimit), nil
+}
+
+// ShareCounts returns the valid, stale and invalid shares of address over the last day
+func (p *Pool) ShareCounts(ctx context.Context, address string) (counts pool.ShareCounts, err error) {
+	stats, err := p.minerStats(ctx, address)
+	if err != nil {
+		return
+	}
+	counts.Valid = stats.Result.ValidShares
+	counts.Stale = stats.Result.StaleShares
+	counts.Invalid = stats.Result.InvalidShares
+	return
+}
+
+// ReportedHashrate returns the hashrate the rigs of address report
+func (p *Pool) ReportedHashrate(ctx context.Context, address string) (hashrate float64, err error) {
+	stats, err := p.minerStats(ctx, address)
+	if err != nil {
+		return
+	}
+	return stats.Result.ReportedHashrate, nil
+}
@@ -0,0 +1,99 @@
+package flexpool
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/pool"
+)
+
+func Test_Pool(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	p := NewPool(NewClient(WithBaseURL("http://test.com/v2/"), WithHTTPClient(mockClient)))
+	ctx := context.Background()
+	mockClient.On("Do", "http://test.com/v2/miner/balance?address=0x01&coin=eth&countervalue=USD").Return(miner0x01Balance, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/balance?address=0x02&coin=eth&countervalue=USD").Return(miner0x02Error, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/stats?address=0x01&coin=eth").Return(miner0x01Stats, nil).Once()
+	mockClient.On("Do", "http://test.com/v2/miner/workers?address=0x01&coin=eth").Return(MinerWorkers{Result: []MinerWorkerResult{{Name: "rig1", CurrentEffectiveHashrate: 95500000, AverageEffectiveHashrate: 90000000, LastSeen: 1609459200}}}, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/chart?address=0x01&coin=eth").Return(MinerChart{Result: []MinerChartResult{{Timestamp: 1609459200, ValidShares: 32}}}, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/payments?address=0x01&coin=eth&page=0").Return(MinerPayments{Result: MinerPaymentsResult{Data: []MinerPaymentsData{{Hash: "0x02", Timestamp: 1609459200, Value: big.NewInt(100000000000000000), Confirmed: true}}}}, nil)
+	mockClient.On("Do", "http://test.com/v2/miner/details?address=0x01&coin=eth").Return(MinerDetails{Result: MinerDetailsResult{PayoutLimit: big.NewInt(200000000000000000)}}, nil)
+	at := time.Unix(1609459200, 0).UTC()
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name:     "Balance01",
+			call:     func() (interface{}, error) { return p.Balance(ctx, "0x01") },
+			wantData: pool.Balance{Confirmed: big.NewRat(142, 1000), Unconfirmed: new(big.Rat)},
+		},
+		{
+			name:     "Balance02",
+			call:     func() (interface{}, error) { return p.Balance(ctx, "0x02") },
+			wantData: pool.Balance{},
+			wantErr:  pool.ErrAccountNotFound,
+		},
+		{
+			name:     "Prices01",
+			call:     func() (interface{}, error) { return p.Prices(ctx, "0x01") },
+			wantData: pool.Prices{USD: big.NewRat(73051, 100)},
+		},
+		{
+			name:     "Hashrate01",
+			call:     func() (interface{}, error) { return p.Hashrate(ctx, "0x01") },
+			wantData: pool.Hashrate{Current: 190500000, Average: 185000000},
+		},
+		{
+			name:     "ReportedHashrate01",
+			call:     func() (interface{}, error) { return p.ReportedHashrate(ctx, "0x01") },
+			wantData: float64(200400000),
+		},
+		{
+			name:     "ShareCounts01",
+			call:     func() (interface{}, error) { return p.ShareCounts(ctx, "0x01") },
+			wantData: pool.ShareCounts{Valid: 4500, Stale: 45, Invalid: 2},
+		},
+		{
+			name:     "Workers01",
+			call:     func() (interface{}, error) { return p.Workers(ctx, "0x01") },
+			wantData: []pool.Worker{{Name: "rig1", Hashrate: 95500000, AverageHashrate: 90000000, LastShare: at}},
+		},
+		{
+			name:     "ShareHistory01",
+			call:     func() (interface{}, error) { return p.ShareHistory(ctx, "0x01") },
+			wantData: []pool.ShareSlot{{Time: at, Shares: 32}},
+		},
+		{
+			name:     "Payments01",
+			call:     func() (interface{}, error) { return p.Payments(ctx, "0x01") },
+			wantData: []pool.Payment{{Time: at, TXHash: "0x02", Amount: big.NewRat(1, 10), Confirmed: true}},
+		},
+		{
+			name:     "PayoutThreshold01",
+			call:     func() (interface{}, error) { return p.PayoutThreshold(ctx, "0x01") },
+			wantData: big.NewRat(1, 5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+	// miner/stats is only requested once thanks to the memo, a second request would fail the Once expectation
+	mockClient.AssertNumberOfCalls(t, "Do", 8)
+}
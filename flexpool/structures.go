@@ -0,0 +1,131 @@
+package flexpool
+
+import "math/big"
+
+// ErrorResponse is a struct for marshaling json of any error coming from Flexpool's API, error is null on success
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// MinerBalance is for decoding json from a successful response of the flexpool miner balance api endpoint
+type MinerBalance struct {
+	Error  string             `json:"error"`
+	Result MinerBalanceResult `json:"result"`
+}
+
+// MinerBalanceResult is for decoding json from a successful response of the flexpool miner balance api endpoint,
+// Balance is in the smallest unit of the coin and BalanceCountervalue and Price in the countervalue currency
+type MinerBalanceResult struct {
+	Balance             *big.Int `json:"balance"`
+	BalanceCountervalue float64  `json:"balanceCountervalue"`
+	Price               float64  `json:"price"`
+}
+
+// MinerStats is for decoding json from a successful response of the flexpool miner stats api endpoint
+type MinerStats struct {
+	Error  string           `json:"error"`
+	Result MinerStatsResult `json:"result"`
+}
+
+// MinerStatsResult is for decoding json from a successful response of the flexpool miner stats api endpoint,
+// hashrates are in H/s and share counts cover the last day
+type MinerStatsResult struct {
+	ReportedHashrate         float64 `json:"reportedHashrate"`
+	CurrentEffectiveHashrate float64 `json:"currentEffectiveHashrate"`
+	AverageEffectiveHashrate float64 `json:"averageEffectiveHashrate"`
+	ValidShares              int64   `json:"validShares"`
+	StaleShares              int64   `json:"staleShares"`
+	InvalidShares            int64   `json:"invalidShares"`
+}
+
+// MinerWorkers is for decoding json from a successful response of the flexpool miner workers api endpoint
+type MinerWorkers struct {
+	Error  string              `json:"error"`
+	Result []MinerWorkerResult `json:"result"`
+}
+
+// MinerWorkerResult is for decoding json from a successful response of the flexpool miner workers api endpoint,
+// Flexpool spells the last share time lastSteen
+type MinerWorkerResult struct {
+	Name                     string  `json:"name"`
+	IsOnline                 bool    `json:"isOnline"`
+	Count                    int64   `json:"count"`
+	ReportedHashrate         float64 `json:"reportedHashrate"`
+	CurrentEffectiveHashrate float64 `json:"currentEffectiveHashrate"`
+	AverageEffectiveHashrate float64 `json:"averageEffectiveHashrate"`
+	ValidShares              int64   `json:"validShares"`
+	StaleShares              int64   `json:"staleShares"`
+	InvalidShares            int64   `json:"invalidShares"`
+	LastSeen                 int64   `json:"lastSteen"`
+}
+
+// MinerPayments is for decoding json from a successful response of the flexpool miner payments api endpoint
+type MinerPayments struct {
+	Error  string              `json:"error"`
+	Result MinerPaymentsResult `json:"result"`
+}
+
+// MinerPaymentsResult is for decoding json from a successful response of the flexpool miner payments api endpoint
+type MinerPaymentsResult struct {
+	Data       []MinerPaymentsData `json:"data"`
+	TotalItems int64               `json:"totalItems"`
+	TotalPages int64               `json:"totalPages"`
+}
+
+// MinerPaymentsData is for decoding json from a successful response of the flexpool miner payments api endpoint,
+// Value and Fee are in the smallest unit of the coin
+type MinerPaymentsData struct {
+	Hash               string   `json:"hash"`
+	Timestamp          int64    `json:"timestamp"`
+	Value              *big.Int `json:"value"`
+	Fee                *big.Int `json:"fee"`
+	FeePercent         float64  `json:"feePercent"`
+	FeePrice           int64    `json:"feePrice"`
+	Duration           int64    `json:"duration"`
+	Confirmed          bool     `json:"confirmed"`
+	ConfirmedTimestamp int64    `json:"confirmedTimestamp"`
+	Network            string   `json:"network"`
+}
+
+// MinerRoundShare is for decoding json from a successful response of the flexpool miner round share api endpoint,
+// Result is the fraction of the current round's shares submitted by the miner
+type MinerRoundShare struct {
+	Error  string  `json:"error"`
+	Result float64 `json:"result"`
+}
+
+// MinerDetails is for decoding json from a successful response of the flexpool miner details api endpoint
+type MinerDetails struct {
+	Error  string             `json:"error"`
+	Result MinerDetailsResult `json:"result"`
+}
+
+// MinerDetailsResult is for decoding json from a successful response of the flexpool miner details api endpoint,
+// PayoutLimit is the balance, in the smallest unit of the coin, at which the miner is paid out
+type MinerDetailsResult struct {
+	ClientIPAddress        string   `json:"clientIPAddress"`
+	CurrentNetworkFeeLimit int64    `json:"currentNetworkFeeLimit"`
+	FirstJoined            int64    `json:"firstJoined"`
+	IPAddress              string   `json:"ipAddress"`
+	MaxFeePrice            int64    `json:"maxFeePrice"`
+	PayoutLimit            *big.Int `json:"payoutLimit"`
+	Network                string   `json:"network"`
+}
+
+// MinerChart is for decoding json from a successful response of the flexpool miner chart api endpoint
+type MinerChart struct {
+	Error  string             `json:"error"`
+	Result []MinerChartResult `json:"result"`
+}
+
+// MinerChartResult is for decoding json from a successful response of the flexpool miner chart api endpoint,
+// one entry is kept for every 10 minutes of the last day
+type MinerChartResult struct {
+	Timestamp                int64   `json:"timestamp"`
+	ReportedHashrate         float64 `json:"reportedHashrate"`
+	AverageEffectiveHashrate float64 `json:"averageEffectiveHashrate"`
+	EffectiveHashrate        float64 `json:"effectiveHashrate"`
+	ValidShares              int64   `json:"validShares"`
+	StaleShares              int64   `json:"staleShares"`
+	InvalidShares            int64   `json:"invalidShares"`
+}
@@ -0,0 +1,26 @@
+package flexpool
+
+import "math/big"
+
+var (
+	miner0x01Stats = &MinerStats{
+		Result: MinerStatsResult{
+			ReportedHashrate:         200400000,
+			CurrentEffectiveHashrate: 190500000,
+			AverageEffectiveHashrate: 185000000,
+			ValidShares:              4500,
+			StaleShares:              45,
+			InvalidShares:            2,
+		},
+	}
+	miner0x01Balance = &MinerBalance{
+		Result: MinerBalanceResult{
+			Balance:             big.NewInt(142000000000000000),
+			BalanceCountervalue: 103.73,
+			Price:               730.51,
+		},
+	}
+	miner0x02Error = &ErrorResponse{
+		Error: "Invalid address",
+	}
+)
@@ -0,0 +1,123 @@
+// Package graphite writes points to Carbon, the Graphite daemon, over its plaintext TCP protocol. Every numeric field
+// of a point is sent as "<path> <value> <timestamp>" with a path built from a Template
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"mining-tools/lineprotocol"
+	"mining-tools/sink"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultAddress is the host:port Carbon's plaintext listener binds by default
+	DefaultAddress = "127.0.0.1:2003"
+	// DefaultTimeout is the timeout a Sink applies to each write when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+)
+
+// Dialer is an interface to abstract net.Dialer to support testing
+type Dialer interface {
+	DialContext(ctx context.Context, network string, address string) (conn net.Conn, err error)
+}
+
+// Sink sends each batch of points over a connection of its own, the plaintext protocol has no replies so a write
+// that reached the listener is as much as can be known
+type Sink struct {
+	address  string
+	template *Template
+	dialer   Dialer
+	timeout  time.Duration
+	now      func() time.Time
+}
+
+// Option configures a Sink created by NewSink
+type Option func(*Sink)
+
+// WithTemplate sets how metric paths are built, DefaultTemplate is used otherwise
+func WithTemplate(template *Template) Option {
+	return func(s *Sink) {
+		s.template = template
+	}
+}
+
+// WithDialer sets how connections to the listener are opened
+func WithDialer(dialer Dialer) Option {
+	return func(s *Sink) {
+		s.dialer = dialer
+	}
+}
+
+// WithTimeout sets the timeout applied to each write, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Sink) {
+		s.timeout = timeout
+	}
+}
+
+// NewSink returns a Sink for the plaintext listener at address, a host:port, adjusted by options
+func NewSink(address string, options ...Option) *Sink {
+	template, _ := ParseTemplate(DefaultTemplate)
+	s := &Sink{
+		address:  address,
+		template: template,
+		dialer:   &net.Dialer{},
+		timeout:  DefaultTimeout,
+		now:      time.Now,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Address returns the host:port of the listener
+func (s *Sink) Address() string {
+	return s.address
+}
+
+// Write sends the numeric fields of points with timestamps in seconds, points without a time are stamped with the
+// time of the write
+func (s *Sink) Write(ctx context.Context, points []*lineprotocol.Point) (err error) {
+	metrics := s.template.Metrics(points)
+	if len(metrics) == 0 {
+		log.Debugln("Sink.Write: no numeric fields to write")
+		return nil
+	}
+	var payload bytes.Buffer
+	now := s.now()
+	for _, m := range metrics {
+		at := m.Time
+		if at.IsZero() {
+			at = now
+		}
+		fmt.Fprintf(&payload, "%s %s %d\n", m.Path, strconv.FormatFloat(m.Value, 'f', -1, 64), at.Unix())
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	conn, err := s.dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		log.Errorf("Sink.Write: DialContext(tcp, %s); returned err=%s\n", s.address, err.Error())
+		return fmt.Errorf("graphite %s: %w: %s", s.address, sink.ErrUnreachable, err.Error())
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	log.Debugf("Sink.Write: sending %d metrics to %s - %s", len(metrics), s.address, payload.Bytes())
+	if _, err = conn.Write(payload.Bytes()); err != nil {
+		log.Errorf("Sink.Write: conn.Write(payload); returned err=%s\n", err.Error())
+		return fmt.Errorf("graphite %s: %w", s.address, err)
+	}
+	return
+}
@@ -0,0 +1,73 @@
+package graphite
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"mining-tools/lineprotocol"
+	"mining-tools/sink"
+)
+
+// listen accepts one connection on a random local port and sends everything written to it on the returned channel
+func listen(t *testing.T) (address string, received chan []byte) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	received = make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		payload, _ := ioutil.ReadAll(conn)
+		received <- payload
+	}()
+	return listener.Addr().String(), received
+}
+
+func Test_Write(t *testing.T) {
+	address, received := listen(t)
+	at := time.Unix(1609459200, 0).UTC()
+	points := []*lineprotocol.Point{
+		lineprotocol.NewPoint("rig", at).AddTag("Location", "ethminer").AddTag("Rig", "rig 1").
+			AddField("Hashrate", 95500000.0).AddField("Miner", "ethminer-0.19.0"),
+		lineprotocol.NewPoint("network", time.Time{}).AddTag("Location", "nanopool").AddField("PoolShare", 0.0000076),
+	}
+	template, _ := ParseTemplate("mining.{location}.{rig}.{field}")
+	s := NewSink(address, WithTemplate(template), WithTimeout(time.Second))
+	s.now = func() time.Time { return at.Add(time.Minute) }
+	if err := s.Write(context.Background(), points); err != nil {
+		t.Fatalf("Sink.Write() error = %v", err)
+	}
+	want := "mining.ethminer.rig_1.Hashrate 95500000 1609459200\nmining.nanopool.PoolShare 0.0000076 1609459260\n"
+	select {
+	case payload := <-received:
+		if string(payload) != want {
+			t.Errorf("Sink.Write() sent %q, want %q", payload, want)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Sink.Write() sent nothing")
+	}
+}
+
+func Test_WriteUnreachable(t *testing.T) {
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	address := closed.Addr().String()
+	closed.Close()
+	points := []*lineprotocol.Point{lineprotocol.NewPoint("pool", time.Now()).AddField("Balance", 0.142)}
+	err = NewSink(address, WithTimeout(time.Second)).Write(context.Background(), points)
+	if !errors.Is(err, sink.ErrUnreachable) {
+		t.Errorf("Sink.Write() error = %v, want %v", err, sink.ErrUnreachable)
+	}
+}
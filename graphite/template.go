@@ -0,0 +1,115 @@
+package graphite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"mining-tools/lineprotocol"
+)
+
+// DefaultTemplate names every field of the points the metrics command collects uniquely, including those of several
+// accounts on one pool or network stats of several coins. Segments of tags a point does not have are left out
+const DefaultTemplate = "mining.{measurement}.{location}.{pool}.{account}.{coin}.{rig}.{gpu}.{field}"
+
+// ErrInvalidTemplate is matched by the errors of templates that cannot be parsed
+var ErrInvalidTemplate = errors.New("invalid template")
+
+var (
+	placeholder  = regexp.MustCompile(`\{([^{}]*)\}`)
+	invalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+)
+
+// Template builds dotted metric paths from points. {measurement} and {field} are replaced with the measurement and
+// the field, any other placeholder with the tag of that name matched regardless of case, e.g. {location}
+type Template struct {
+	segments []string
+}
+
+// Metric is one numeric field of a point under its path
+type Metric struct {
+	Path  string
+	Value float64
+	Time  time.Time
+}
+
+// ParseTemplate parses a template such as mining.{location}.{field}, which must include {field} so the fields of a
+// point get paths of their own
+func ParseTemplate(template string) (t *Template, err error) {
+	if !strings.Contains(template, "{field}") {
+		return nil, fmt.Errorf("%w %q: {field} is missing", ErrInvalidTemplate, template)
+	}
+	t = &Template{}
+	for _, segment := range strings.Split(template, ".") {
+		rest := placeholder.ReplaceAllString(segment, "")
+		if strings.ContainsAny(rest, "{}") {
+			return nil, fmt.Errorf("%w %q: unbalanced braces in %q", ErrInvalidTemplate, template, segment)
+		}
+		for _, match := range placeholder.FindAllStringSubmatch(segment, -1) {
+			if match[1] == "" {
+				return nil, fmt.Errorf("%w %q: empty placeholder in %q", ErrInvalidTemplate, template, segment)
+			}
+		}
+		if segment != "" {
+			t.segments = append(t.segments, segment)
+		}
+	}
+	return
+}
+
+// String returns the template t was parsed from, less empty segments
+func (t *Template) String() string {
+	return strings.Join(t.segments, ".")
+}
+
+// Path returns the path of field of p. Values are sanitized to letters, digits, _ and -, and segments with a tag p
+// does not have are dropped
+func (t *Template) Path(p *lineprotocol.Point, field string) string {
+	segments := make([]string, 0, len(t.segments))
+	for _, segment := range t.segments {
+		missing := false
+		rendered := placeholder.ReplaceAllStringFunc(segment, func(match string) string {
+			name := match[1 : len(match)-1]
+			switch name {
+			case "measurement":
+				return sanitize(p.Measurement)
+			case "field":
+				return sanitize(field)
+			}
+			for _, tag := range p.Tags {
+				if strings.EqualFold(tag.Key, name) {
+					return sanitize(tag.Value)
+				}
+			}
+			missing = true
+			return ""
+		})
+		if !missing && rendered != "" {
+			segments = append(segments, rendered)
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// Metrics returns the numeric fields of points under their paths, strings and values Graphite cannot store, NaN and
+// the infinities, are left out
+func (t *Template) Metrics(points []*lineprotocol.Point) (metrics []Metric) {
+	for _, p := range points {
+		for _, f := range p.Fields {
+			value, ok := f.Float64()
+			if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			metrics = append(metrics, Metric{Path: t.Path(p, f.Key), Value: value, Time: p.Time})
+		}
+	}
+	return
+}
+
+// sanitize replaces the characters that would split or break a path segment
+func sanitize(value string) string {
+	return invalidChars.ReplaceAllString(value, "_")
+}
@@ -0,0 +1,92 @@
+package graphite
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/lineprotocol"
+)
+
+func Test_ParseTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  error
+	}{
+		{name: "Success01", template: "mining.{location}.{field}", want: "mining.{location}.{field}"},
+		{name: "Success02", template: "mining..gpu{gpu}.{field}.", want: "mining.gpu{gpu}.{field}"},
+		{name: "NoField01", template: "mining.{location}", wantErr: ErrInvalidTemplate},
+		{name: "Unbalanced01", template: "mining.{location.{field}", wantErr: ErrInvalidTemplate},
+		{name: "Empty01", template: "mining.{}.{field}", wantErr: ErrInvalidTemplate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTemplate(tt.template)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("ParseTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseTemplate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Metrics(t *testing.T) {
+	at := time.Unix(1609459200, 0).UTC()
+	points := []*lineprotocol.Point{
+		lineprotocol.NewPoint("pool", at).AddTag("Location", "nanopool").AddTag("Pool", "eth main").AddTag("Account", "0x01").
+			AddField("Balance", 0.142).AddField("Shares", int64(12)),
+		lineprotocol.NewPoint("pool", at).AddTag("Location", "nanopool").AddTag("Pool", "eth main").AddTag("Account", "0x02").
+			AddField("Balance", 0.5),
+		lineprotocol.NewPoint("financial", at).AddTag("Location", "wallet").
+			AddField("BalanceUSD", big.NewRat(90123, 1000)).AddField("Miner", "ethminer-0.19.0"),
+		lineprotocol.NewPoint("gpu", at).AddTag("Location", "ethminer").AddTag("Rig", "rig1.lan").AddTag("GPU", "0").
+			AddField("Hashrate", 30000000.0).AddField("Efficiency", math.NaN()),
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     []Metric
+	}{
+		{
+			name:     "Default01",
+			template: DefaultTemplate,
+			want: []Metric{
+				// two accounts on one pool must not share a path
+				{Path: "mining.pool.nanopool.eth_main.0x01.Balance", Value: 0.142, Time: at},
+				{Path: "mining.pool.nanopool.eth_main.0x01.Shares", Value: 12, Time: at},
+				{Path: "mining.pool.nanopool.eth_main.0x02.Balance", Value: 0.5, Time: at},
+				{Path: "mining.financial.wallet.BalanceUSD", Value: 90.123, Time: at},
+				{Path: "mining.gpu.ethminer.rig1_lan.0.Hashrate", Value: 30000000, Time: at},
+			},
+		},
+		{
+			name:     "Custom01",
+			template: "farm.{LOCATION}.gpu{gpu}.{field}",
+			want: []Metric{
+				{Path: "farm.nanopool.Balance", Value: 0.142, Time: at},
+				{Path: "farm.nanopool.Shares", Value: 12, Time: at},
+				{Path: "farm.nanopool.Balance", Value: 0.5, Time: at},
+				{Path: "farm.wallet.BalanceUSD", Value: 90.123, Time: at},
+				{Path: "farm.ethminer.gpu0.Hashrate", Value: 30000000, Time: at},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := ParseTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseTemplate() error = %v", err)
+			}
+			if got := template.Metrics(points); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Template.Metrics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package influxdb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"mining-tools/sink"
+)
+
+var (
+	// ErrUnauthorized is returned when InfluxDB rejected the token or the token may not write to the bucket
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is returned when InfluxDB does not know the org or the bucket
+	ErrNotFound = errors.New("org or bucket not found")
+	// ErrInvalidPoints is returned when InfluxDB rejected some or all lines of a batch, the valid lines were written
+	ErrInvalidPoints = errors.New("invalid points")
+	// ErrTooLarge is returned when a batch exceeded InfluxDB's request size limit, lower the batch size
+	ErrTooLarge = errors.New("batch too large")
+	// ErrRateLimited is returned when InfluxDB rejected the write for exceeding a quota
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer is returned when InfluxDB answered with a 5xx status
+	ErrServer = errors.New("server error")
+	// ErrUnreachable is returned when InfluxDB could not be reached
+	ErrUnreachable = errors.New("unreachable")
+	// ErrRequestFailed is returned for any other status than 204
+	ErrRequestFailed = errors.New("request failed")
+)
+
+// APIError describes a failed write of one batch, Err is one of the Err* sentinels above so callers can branch with
+// errors.Is. Code, Message and Line come from InfluxDB's error body, Line is the first rejected line of the batch
+type APIError struct {
+	Batch      int
+	StatusCode int
+	Code       string
+	Message    string
+	Line       int
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("influxdb write of batch %d: %s (HTTP %d)", e.Batch, e.Err, e.StatusCode)
+	}
+	return fmt.Sprintf("influxdb write of batch %d: %s (HTTP %d): %s", e.Batch, e.Err, e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error describing the kind of failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets callers match sink.ErrUnreachable, so code written against sink.Sink can tell a down database apart
+func (e *APIError) Is(target error) bool {
+	return target == sink.ErrUnreachable && e.Err == ErrUnreachable
+}
+
+// PartialWriteError reports the batches InfluxDB rejected points of while the rest of the write went through
+type PartialWriteError struct {
+	Batches int
+	Errs    []*APIError
+}
+
+func (e *PartialWriteError) Error() string {
+	return fmt.Sprintf("influxdb rejected points of %d of %d batches, first: %s", len(e.Errs), e.Batches, e.Errs[0])
+}
+
+// Unwrap returns the error of the first rejected batch
+func (e *PartialWriteError) Unwrap() error {
+	return e.Errs[0]
+}
+
+// checkResponse turns the status of a write and its decoded error body in to an *APIError, or nil if the batch was
+// written
+func checkResponse(batch int, statusCode int, body *ErrorResponse) (err error) {
+	if statusCode == http.StatusNoContent || statusCode == http.StatusOK {
+		return nil
+	}
+	apiErr := &APIError{Batch: batch, StatusCode: statusCode}
+	if body != nil {
+		apiErr.Code = body.Code
+		apiErr.Message = body.Message
+		apiErr.Line = body.Line
+	}
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		apiErr.Err = ErrUnauthorized
+	case statusCode == http.StatusNotFound:
+		apiErr.Err = ErrNotFound
+	case statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity:
+		apiErr.Err = ErrInvalidPoints
+	case statusCode == http.StatusRequestEntityTooLarge:
+		apiErr.Err = ErrTooLarge
+	case statusCode == http.StatusTooManyRequests:
+		apiErr.Err = ErrRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		apiErr.Err = ErrServer
+	default:
+		apiErr.Err = ErrRequestFailed
+	}
+	return apiErr
+}
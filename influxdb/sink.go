@@ -0,0 +1,197 @@
+// Package influxdb writes points to the /api/v2/write endpoint of InfluxDB 2.x, and of InfluxDB 1.8 and later through
+// its 2.x compatibility API
+package influxdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"mining-tools/lineprotocol"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultURL is where InfluxDB 2.x listens by default
+	DefaultURL = "http://127.0.0.1:8086"
+	// DefaultBatchSize is the number of points sent per request, the batch size InfluxDB recommends
+	DefaultBatchSize = 5000
+	// DefaultTimeout is the per-request timeout a Sink applies when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+)
+
+// HTTPClient is an interface to abstract http.client to support testing using mocks
+type HTTPClient interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+var (
+	apiClient = HTTPClient(&http.Client{})
+)
+
+// ErrorResponse is the body InfluxDB answers a failed write with
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Line is the first line the write failed on, for lines that could not be parsed
+	Line int `json:"line,omitempty"`
+}
+
+// Sink writes points to one bucket of an InfluxDB org
+type Sink struct {
+	url        string
+	org        string
+	bucket     string
+	token      string
+	precision  lineprotocol.Precision
+	batchSize  int
+	httpClient HTTPClient
+	timeout    time.Duration
+}
+
+// Option configures a Sink created by NewSink
+type Option func(*Sink)
+
+// WithToken sets the API token sent with every write, InfluxDB 1.x compatibility takes username:password
+func WithToken(token string) Option {
+	return func(s *Sink) {
+		s.token = token
+	}
+}
+
+// WithPrecision sets the precision timestamps are written in, coarser precisions make smaller requests
+func WithPrecision(precision lineprotocol.Precision) Option {
+	return func(s *Sink) {
+		s.precision = precision
+	}
+}
+
+// WithBatchSize sets the most points sent in one request, zero or less sends every point in one request
+func WithBatchSize(batchSize int) Option {
+	return func(s *Sink) {
+		s.batchSize = batchSize
+	}
+}
+
+// WithHTTPClient sets the transport used for every request
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(s *Sink) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout applied to each request, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Sink) {
+		s.timeout = timeout
+	}
+}
+
+// NewSink returns a Sink writing to bucket of org on the InfluxDB at serverURL, e.g. http://127.0.0.1:8086, adjusted
+// by options
+func NewSink(serverURL string, org string, bucket string, options ...Option) *Sink {
+	s := &Sink{
+		url:        strings.TrimSuffix(serverURL, "/"),
+		org:        org,
+		bucket:     bucket,
+		precision:  lineprotocol.Nanosecond,
+		batchSize:  DefaultBatchSize,
+		httpClient: apiClient,
+		timeout:    DefaultTimeout,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// URL returns the root of the InfluxDB API
+func (s *Sink) URL() string {
+	return s.url
+}
+
+// Write sends points in batches. A batch InfluxDB rejects points of does not stop the batches after it, their errors
+// are returned together as a *PartialWriteError once every batch was sent. Any other failure stops the write
+func (s *Sink) Write(ctx context.Context, points []*lineprotocol.Point) (err error) {
+	size := s.batchSize
+	if size <= 0 || size > len(points) {
+		size = len(points)
+	}
+	partial := &PartialWriteError{}
+	for start := 0; start < len(points); start += size {
+		end := start + size
+		if end > len(points) {
+			end = len(points)
+		}
+		partial.Batches++
+		payload, err := lineprotocol.Encode(points[start:end], s.precision)
+		if err != nil {
+			return err
+		}
+		err = s.post(ctx, partial.Batches, payload)
+		var apiErr *APIError
+		if errors.Is(err, ErrInvalidPoints) && errors.As(err, &apiErr) {
+			log.Warnf("Sink.Write: InfluxDB rejected points of batch %d: %s\n", partial.Batches, apiErr.Message)
+			partial.Errs = append(partial.Errs, apiErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(partial.Errs) > 0 {
+		return partial
+	}
+	return nil
+}
+
+// post sends one batch of lines to the write endpoint
+func (s *Sink) post(ctx context.Context, batch int, payload []byte) (err error) {
+	query := url.Values{}
+	query.Set("org", s.org)
+	query.Set("bucket", s.bucket)
+	query.Set("precision", s.precision.String())
+	fullPath := s.url + "/api/v2/write?" + query.Encode()
+	log.Debugf("Sink.post(fullPath=%s, batch=%d) called\n", fullPath, batch)
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullPath, bytes.NewReader(payload))
+	if err != nil {
+		log.Errorf("Sink.post: http.NewRequestWithContext(POST, %s); returned err=%s\n", fullPath, err.Error())
+		return
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Accept", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Token "+s.token)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		log.Errorf("Sink.post: httpClient.Do(%s); returned err=%s\n", fullPath, err.Error())
+		return &APIError{Batch: batch, Message: err.Error(), Err: ErrUnreachable}
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("Sink.post: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return &APIError{Batch: batch, StatusCode: resp.StatusCode, Message: err.Error(), Err: ErrUnreachable}
+	}
+	var body *ErrorResponse
+	if len(bytes.TrimSpace(respBody)) > 0 {
+		body = new(ErrorResponse)
+		if json.Unmarshal(respBody, body) != nil {
+			body = &ErrorResponse{Message: strings.TrimSpace(string(respBody))}
+		}
+	}
+	return checkResponse(batch, resp.StatusCode, body)
+}
@@ -0,0 +1,170 @@
+package influxdb
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mining-tools/lineprotocol"
+	"mining-tools/sink"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+// fakeInfluxDB records the writes it receives and answers each with the next status and body of replies, 204 once
+// they run out
+type fakeInfluxDB struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+	replies  []reply
+}
+
+type reply struct {
+	status int
+	body   string
+}
+
+func (f *fakeInfluxDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r)
+	f.bodies = append(f.bodies, string(body))
+	if len(f.replies) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	next := f.replies[0]
+	f.replies = f.replies[1:]
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(next.status)
+	w.Write([]byte(next.body))
+}
+
+func testPoints(n int) (points []*lineprotocol.Point) {
+	at := time.Unix(1609459200, 0).UTC()
+	for i := 0; i < n; i++ {
+		points = append(points, lineprotocol.NewPoint("gpu", at).AddTag("Rig", "rig 1").AddField("GPU", i))
+	}
+	return
+}
+
+func Test_Write(t *testing.T) {
+	fake := &fakeInfluxDB{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	s := NewSink(server.URL+"/", "mining", "metrics", WithToken("secret"), WithPrecision(lineprotocol.Second), WithBatchSize(2))
+	if err := s.Write(context.Background(), testPoints(3)); err != nil {
+		t.Fatalf("Sink.Write() error = %v", err)
+	}
+	if len(fake.requests) != 2 {
+		t.Fatalf("Sink.Write() sent %d requests, want 2 batches", len(fake.requests))
+	}
+	r := fake.requests[0]
+	if r.Method != http.MethodPost || r.URL.Path != "/api/v2/write" || r.URL.Query().Get("org") != "mining" ||
+		r.URL.Query().Get("bucket") != "metrics" || r.URL.Query().Get("precision") != "s" {
+		t.Errorf("Sink.Write() requested %s %s, want POST /api/v2/write to org mining, bucket metrics in seconds", r.Method, r.URL)
+	}
+	if r.Header.Get("Authorization") != "Token secret" {
+		t.Errorf("Sink.Write() Authorization = %q, want Token secret", r.Header.Get("Authorization"))
+	}
+	want := []string{
+		"gpu,Rig=rig\\ 1 GPU=0i 1609459200\ngpu,Rig=rig\\ 1 GPU=1i 1609459200\n",
+		"gpu,Rig=rig\\ 1 GPU=2i 1609459200\n",
+	}
+	for i := range want {
+		if fake.bodies[i] != want[i] {
+			t.Errorf("Sink.Write() batch %d = %q, want %q", i+1, fake.bodies[i], want[i])
+		}
+	}
+}
+
+func Test_WriteErrors(t *testing.T) {
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	tests := []struct {
+		name         string
+		url          string
+		replies      []reply
+		wantErr      error
+		wantRequests int
+		wantPartial  int
+	}{
+		{
+			name:         "Unauthorized01",
+			replies:      []reply{{http.StatusUnauthorized, `{"code":"unauthorized","message":"unauthorized access"}`}},
+			wantErr:      ErrUnauthorized,
+			wantRequests: 1,
+		},
+		{
+			name:         "NotFound01",
+			replies:      []reply{{http.StatusNotFound, `{"code":"not found","message":"bucket \"metrics\" not found"}`}},
+			wantErr:      ErrNotFound,
+			wantRequests: 1,
+		},
+		{
+			name: "PartialWrite01",
+			replies: []reply{
+				{http.StatusBadRequest, `{"code":"invalid","message":"partial write error (1 written): unable to parse 'gpu': missing fields","line":2}`},
+				{http.StatusNoContent, ""},
+			},
+			wantErr:      ErrInvalidPoints,
+			wantRequests: 2,
+			wantPartial:  1,
+		},
+		{
+			name:         "TooLarge01",
+			replies:      []reply{{http.StatusRequestEntityTooLarge, `{"code":"request too large","message":"request too large"}`}},
+			wantErr:      ErrTooLarge,
+			wantRequests: 1,
+		},
+		{
+			name:         "Server01",
+			replies:      []reply{{http.StatusServiceUnavailable, "upstream unavailable"}},
+			wantErr:      ErrServer,
+			wantRequests: 1,
+		},
+		{
+			name:    "Unreachable01",
+			url:     unreachable.URL,
+			wantErr: sink.ErrUnreachable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeInfluxDB{replies: tt.replies}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			url := server.URL
+			if tt.url != "" {
+				url = tt.url
+			}
+			err := NewSink(url, "mining", "metrics", WithBatchSize(2)).Write(context.Background(), testPoints(4))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sink.Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(fake.requests) != tt.wantRequests {
+				t.Errorf("Sink.Write() sent %d requests, want %d", len(fake.requests), tt.wantRequests)
+			}
+			var partial *PartialWriteError
+			if errors.As(err, &partial) != (tt.wantPartial > 0) {
+				t.Fatalf("Sink.Write() error = %#v, want a partial write error: %t", err, tt.wantPartial > 0)
+			}
+			if partial != nil && (len(partial.Errs) != tt.wantPartial || partial.Batches != 2 || partial.Errs[0].Line != 2 ||
+				!strings.Contains(partial.Error(), "partial write error")) {
+				t.Errorf("Sink.Write() error = %v, want batch 1 of 2 rejected from line 2", partial)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package lineprotocol
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse parses one line, with or without its newline, reading its timestamp in precision. Fields are typed the way
+// the database would type them: int64 for the i suffix, uint64 for u, string, bool and float64 for everything else
+func Parse(line []byte, precision Precision) (p *Point, err error) {
+	s := strings.TrimRight(string(line), "\r\n")
+	end := indexUnescaped(s, ' ', false)
+	if end <= 0 {
+		return nil, fmt.Errorf("%w: no fields in %q", ErrSyntax, s)
+	}
+	series, rest := s[:end], s[end+1:]
+	end = indexUnescaped(rest, ' ', true)
+	fields, timestamp := rest, ""
+	if end >= 0 {
+		fields, timestamp = rest[:end], rest[end+1:]
+	}
+	keys := splitUnescaped(series, ',', false)
+	p = &Point{Measurement: unescape(keys[0])}
+	if p.Measurement == "" {
+		return nil, fmt.Errorf("%w: no measurement in %q", ErrSyntax, s)
+	}
+	for _, tag := range keys[1:] {
+		key, value, err := splitPair(tag)
+		if err != nil || value == "" {
+			return nil, fmt.Errorf("%w: invalid tag %q in %q", ErrSyntax, tag, s)
+		}
+		p.Tags = append(p.Tags, Tag{Key: key, Value: unescape(value)})
+	}
+	for _, field := range splitUnescaped(fields, ',', true) {
+		key, text, err := splitPair(field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid field %q in %q", ErrSyntax, field, s)
+		}
+		value, err := parseValue(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid value of field %s in %q: %s", ErrSyntax, key, s, err.Error())
+		}
+		p.Fields = append(p.Fields, Field{Key: key, Value: value})
+	}
+	if timestamp = strings.TrimSpace(timestamp); timestamp != "" {
+		units, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid timestamp %q in %q", ErrSyntax, timestamp, s)
+		}
+		p.Time = time.Unix(0, units*int64(precision.Duration())).UTC()
+	}
+	return
+}
+
+// ParseLines parses every line of payload, skipping blank lines and comments
+func ParseLines(payload []byte, precision Precision) (points []*Point, err error) {
+	for _, line := range bytes.Split(payload, []byte("\n")) {
+		if trimmed := bytes.TrimSpace(line); len(trimmed) == 0 || trimmed[0] == '#' {
+			continue
+		}
+		p, err := Parse(line, precision)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return
+}
+
+// parseValue types a field value by its syntax
+func parseValue(text string) (value interface{}, err error) {
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		return unescapeString(text[1 : len(text)-1]), nil
+	}
+	switch text {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+	if strings.HasSuffix(text, "i") {
+		return strconv.ParseInt(strings.TrimSuffix(text, "i"), 10, 64)
+	}
+	if strings.HasSuffix(text, "u") {
+		return strconv.ParseUint(strings.TrimSuffix(text, "u"), 10, 64)
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+// splitPair splits key=value at the first unescaped equals sign and unescapes the key
+func splitPair(pair string) (key string, value string, err error) {
+	i := indexUnescaped(pair, '=', false)
+	if i <= 0 {
+		return "", "", ErrSyntax
+	}
+	return unescape(pair[:i]), pair[i+1:], nil
+}
+
+// indexUnescaped returns the index of the first sep in s that is not escaped by a backslash, nor inside double quotes
+// when quoted is set, or -1
+func indexUnescaped(s string, sep byte, quoted bool) int {
+	inQuotes := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case quoted && s[i] == '"':
+			inQuotes = !inQuotes
+		case s[i] == sep && !inQuotes:
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnescaped splits s at every sep indexUnescaped would find
+func splitUnescaped(s string, sep byte, quoted bool) (parts []string) {
+	for {
+		i := indexUnescaped(s, sep, quoted)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+}
+
+// unescape removes the backslashes escaping the characters special in measurements, keys and tag values
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(`\,= `, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// unescapeString removes the backslashes escaping double quotes and backslashes in a string field value
+func unescapeString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package lineprotocol
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_RoundTrip(t *testing.T) {
+	at := time.Unix(1609459200, 0).UTC()
+	points := []*Point{
+		NewPoint("pool", at).AddTag("Location", "nanopool").AddTag("Pool", "my pool").AddTag("Account", "0x01").
+			AddField("Balance", 0.142).AddField("Shares", int64(12)).AddField("Online", true),
+		NewPoint("gpu", at).AddTag("Rig", `rig,1=a\b`).AddField("Hashrate", 30000000.5).AddField("Count", uint64(3)),
+		NewPoint("notes, and more", at).AddField(`key with=,`, `"quoted", spaced, \ escaped = text`),
+		NewPoint("untimed", time.Time{}).AddField("f", -1.5e-7),
+	}
+	for _, precision := range []Precision{Nanosecond, Second} {
+		payload, err := Encode(points, precision)
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		parsed, err := ParseLines(payload, precision)
+		if err != nil {
+			t.Fatalf("ParseLines(%q) error = %v", payload, err)
+		}
+		if !reflect.DeepEqual(parsed, points) {
+			t.Errorf("ParseLines(Encode()) = %+v, want %+v", parsed, points)
+		}
+	}
+}
+
+func Test_RoundTripRat(t *testing.T) {
+	// exact amounts are floats once written, they come back as the closest float64
+	payload, err := Encode([]*Point{NewPoint("financial", time.Time{}).AddField("BalanceETH", big.NewRat(123456789, 1000))}, Nanosecond)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	points, err := ParseLines(payload, Nanosecond)
+	if err != nil || len(points) != 1 {
+		t.Fatalf("ParseLines(%q) = %v, %v", payload, points, err)
+	}
+	if value, _ := points[0].Field("BalanceETH"); value != 123456.789 {
+		t.Errorf("BalanceETH = %v, want 123456.789", value)
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *Point
+		wantErr error
+	}{
+		{
+			name: "Booleans01",
+			line: "m a=t,b=FALSE,c=True",
+			want: &Point{Measurement: "m", Fields: []Field{{"a", true}, {"b", false}, {"c", true}}},
+		},
+		{
+			name: "Timestamp01",
+			line: "m,Rig=rig1 f=1i 1609459200000000000\r\n",
+			want: &Point{Measurement: "m", Tags: []Tag{{"Rig", "rig1"}}, Fields: []Field{{"f", int64(1)}}, Time: time.Unix(1609459200, 0).UTC()},
+		},
+		{
+			name: "QuotedSpace01",
+			line: `m s="a b" 10`,
+			want: &Point{Measurement: "m", Fields: []Field{{"s", "a b"}}, Time: time.Unix(0, 10).UTC()},
+		},
+		{name: "NoFields01", line: "m", wantErr: ErrSyntax},
+		{name: "NoMeasurement01", line: ",Rig=rig1 f=1", wantErr: ErrSyntax},
+		{name: "EmptyTag01", line: "m,Rig= f=1", wantErr: ErrSyntax},
+		{name: "BadField01", line: "m f", wantErr: ErrSyntax},
+		{name: "BadValue01", line: "m f=1.5i", wantErr: ErrSyntax},
+		{name: "BadTimestamp01", line: "m f=1 soon", wantErr: ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.line), Nanosecond)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
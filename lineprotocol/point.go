@@ -0,0 +1,261 @@
+// Package lineprotocol encodes and parses the InfluxDB line protocol that QuestDB, InfluxDB and Telegraf ingest, so
+// names with spaces, commas or equals signs and integer fields reach the database as they were meant
+package lineprotocol
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"mining-tools/wei"
+)
+
+// Precision is the unit of the timestamp ending a line
+type Precision int
+
+const (
+	// Nanosecond is the default precision of InfluxDB and QuestDB
+	Nanosecond Precision = iota
+	// Microsecond precision
+	Microsecond
+	// Millisecond precision
+	Millisecond
+	// Second precision
+	Second
+)
+
+// ratDecimals is the number of decimals exact amounts are written with, enough for wei
+const ratDecimals = 18
+
+var (
+	// ErrInvalidPoint is matched by the errors of points that cannot be written as a line
+	ErrInvalidPoint = errors.New("invalid point")
+	// ErrSyntax is matched by the errors of lines that cannot be parsed
+	ErrSyntax = errors.New("line protocol syntax error")
+)
+
+var (
+	measurementEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, " ", `\ `)
+	keyEscaper         = strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `)
+	stringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// ParsePrecision parses the precision names InfluxDB uses, ns, us, ms and s
+func ParsePrecision(s string) (precision Precision, err error) {
+	switch strings.ToLower(s) {
+	case "ns", "":
+		return Nanosecond, nil
+	case "us", "µs":
+		return Microsecond, nil
+	case "ms":
+		return Millisecond, nil
+	case "s":
+		return Second, nil
+	}
+	return Nanosecond, fmt.Errorf("unknown precision %q, expected one of ns, us, ms or s", s)
+}
+
+// String returns the name InfluxDB uses for the precision
+func (p Precision) String() string {
+	switch p {
+	case Microsecond:
+		return "us"
+	case Millisecond:
+		return "ms"
+	case Second:
+		return "s"
+	}
+	return "ns"
+}
+
+// Duration returns the length of one unit of the precision
+func (p Precision) Duration() time.Duration {
+	switch p {
+	case Microsecond:
+		return time.Microsecond
+	case Millisecond:
+		return time.Millisecond
+	case Second:
+		return time.Second
+	}
+	return time.Nanosecond
+}
+
+// Tag is a key and value indexing a point
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Field is a key and value of a point. Values are float64, int64 (written with the i suffix), uint64 (the u suffix),
+// string, bool or an exact *big.Rat written as a float with up to 18 decimals
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Point is one line of the line protocol, tags and fields are written in the order they were added and a zero Time
+// leaves the timestamp to the database
+type Point struct {
+	Measurement string
+	Tags        []Tag
+	Fields      []Field
+	Time        time.Time
+}
+
+// NewPoint returns a Point of measurement observed at
+func NewPoint(measurement string, at time.Time) *Point {
+	return &Point{Measurement: measurement, Time: at}
+}
+
+// AddTag adds a tag to p, the line protocol has no empty tag values so a tag without a value is left out
+func (p *Point) AddTag(key string, value string) *Point {
+	if value != "" {
+		p.Tags = append(p.Tags, Tag{Key: key, Value: value})
+	}
+	return p
+}
+
+// AddField adds a field to p, the integer and float kinds are widened to int64, uint64 and float64
+func (p *Point) AddField(key string, value interface{}) *Point {
+	switch v := value.(type) {
+	case int:
+		value = int64(v)
+	case int32:
+		value = int64(v)
+	case uint:
+		value = uint64(v)
+	case uint32:
+		value = uint64(v)
+	case float32:
+		value = float64(v)
+	}
+	p.Fields = append(p.Fields, Field{Key: key, Value: value})
+	return p
+}
+
+// Tag returns the value of the tag key and whether p has it
+func (p *Point) Tag(key string) (value string, ok bool) {
+	for _, t := range p.Tags {
+		if t.Key == key {
+			return t.Value, true
+		}
+	}
+	return
+}
+
+// Field returns the value of the field key and whether p has it
+func (p *Point) Field(key string) (value interface{}, ok bool) {
+	for _, f := range p.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return
+}
+
+// Float64 returns the value of f as a float for databases that only store numbers, bools are 1 or 0 and strings have
+// no numeric value
+func (f Field) Float64() (value float64, ok bool) {
+	switch v := f.Value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case *big.Rat:
+		value, _ = v.Float64()
+		return value, true
+	}
+	return 0, false
+}
+
+// AppendLine appends p to dst as a line ending in a newline with its timestamp in precision
+func (p *Point) AppendLine(dst []byte, precision Precision) (line []byte, err error) {
+	if p.Measurement == "" {
+		return dst, fmt.Errorf("%w: no measurement", ErrInvalidPoint)
+	}
+	if len(p.Fields) == 0 {
+		return dst, fmt.Errorf("%w: %s has no fields", ErrInvalidPoint, p.Measurement)
+	}
+	line = append(dst, measurementEscaper.Replace(p.Measurement)...)
+	for _, t := range p.Tags {
+		if t.Key == "" || t.Value == "" {
+			return dst, fmt.Errorf("%w: %s has an empty tag %q=%q", ErrInvalidPoint, p.Measurement, t.Key, t.Value)
+		}
+		line = append(line, ',')
+		line = append(line, keyEscaper.Replace(t.Key)...)
+		line = append(line, '=')
+		line = append(line, keyEscaper.Replace(t.Value)...)
+	}
+	for i, f := range p.Fields {
+		if f.Key == "" {
+			return dst, fmt.Errorf("%w: %s has a field without a key", ErrInvalidPoint, p.Measurement)
+		}
+		if i == 0 {
+			line = append(line, ' ')
+		} else {
+			line = append(line, ',')
+		}
+		line = append(line, keyEscaper.Replace(f.Key)...)
+		line = append(line, '=')
+		if line, err = appendValue(line, f.Value); err != nil {
+			return dst, fmt.Errorf("%w: field %s of %s: %s", ErrInvalidPoint, f.Key, p.Measurement, err.Error())
+		}
+	}
+	if strings.ContainsRune(string(line[len(dst):]), '\n') {
+		return dst, fmt.Errorf("%w: %s contains a newline", ErrInvalidPoint, p.Measurement)
+	}
+	if !p.Time.IsZero() {
+		line = append(line, ' ')
+		line = strconv.AppendInt(line, p.Time.UnixNano()/int64(precision.Duration()), 10)
+	}
+	return append(line, '\n'), nil
+}
+
+// appendValue appends a field value in the syntax of its type
+func appendValue(dst []byte, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return dst, fmt.Errorf("%v cannot be written", v)
+		}
+		return strconv.AppendFloat(dst, v, 'f', -1, 64), nil
+	case int64:
+		return append(strconv.AppendInt(dst, v, 10), 'i'), nil
+	case uint64:
+		return append(strconv.AppendUint(dst, v, 10), 'u'), nil
+	case string:
+		dst = append(dst, '"')
+		dst = append(dst, stringEscaper.Replace(v)...)
+		return append(dst, '"'), nil
+	case bool:
+		return strconv.AppendBool(dst, v), nil
+	case *big.Rat:
+		if v == nil {
+			return append(dst, '0'), nil
+		}
+		return append(dst, wei.FormatRat(v, ratDecimals)...), nil
+	}
+	return dst, fmt.Errorf("unsupported type %T", value)
+}
+
+// Encode writes points as lines with timestamps in precision, stopping at the first point that cannot be written
+func Encode(points []*Point, precision Precision) (payload []byte, err error) {
+	for _, p := range points {
+		if payload, err = p.AppendLine(payload, precision); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
@@ -0,0 +1,128 @@
+package lineprotocol
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func Test_AppendLine(t *testing.T) {
+	at := time.Unix(1609459200, 123456789).UTC()
+	tests := []struct {
+		name      string
+		point     *Point
+		precision Precision
+		want      string
+		wantErr   error
+	}{
+		{
+			name:  "Success01",
+			point: NewPoint("rig", at).AddTag("Location", "trex").AddTag("Rig", "rig1").AddField("Hashrate", 95500000.0).AddField("AcceptedShares", 95),
+			want:  "rig,Location=trex,Rig=rig1 Hashrate=95500000,AcceptedShares=95i 1609459200123456789\n",
+		},
+		{
+			name: "Types01",
+			point: NewPoint("types", time.Time{}).AddField("float", 0.00000762).AddField("int", int64(-3)).AddField("uint", uint64(7)).
+				AddField("string", "ok").AddField("bool", true).AddField("rat", big.NewRat(1, 3)).AddField("float32", float32(0.5)),
+			want: "types float=0.00000762,int=-3i,uint=7u,string=\"ok\",bool=true,rat=0.333333333333333333,float32=0.5\n",
+		},
+		{
+			name:  "Escaping01",
+			point: NewPoint("my rig,stats", time.Time{}).AddTag("Worker", "rig 1,a=b").AddTag(`Back\slash`, "x").AddField("note=1", `say "hi" \o/`),
+			want:  `my\ rig\,stats,Worker=rig\ 1\,a\=b,Back\\slash=x note\=1="say \"hi\" \\o/"` + "\n",
+		},
+		{
+			name:  "EmptyTag01",
+			point: NewPoint("gpu", time.Time{}).AddTag("Worker", "").AddField("Hashrate", 1.0),
+			want:  "gpu Hashrate=1\n",
+		},
+		{
+			name:      "Precision01",
+			point:     NewPoint("m", at).AddField("f", 1.0),
+			precision: Microsecond,
+			want:      "m f=1 1609459200123456\n",
+		},
+		{
+			name:      "Precision02",
+			point:     NewPoint("m", at).AddField("f", 1.0),
+			precision: Millisecond,
+			want:      "m f=1 1609459200123\n",
+		},
+		{
+			name:      "Precision03",
+			point:     NewPoint("m", at).AddField("f", 1.0),
+			precision: Second,
+			want:      "m f=1 1609459200\n",
+		},
+		{
+			name:    "NoFields01",
+			point:   NewPoint("m", at),
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "NoMeasurement01",
+			point:   NewPoint("", at).AddField("f", 1.0),
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "NaN01",
+			point:   NewPoint("m", at).AddField("f", math.NaN()),
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "Unsupported01",
+			point:   NewPoint("m", at).AddField("f", []int{1}),
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "Newline01",
+			point:   NewPoint("m", at).AddTag("Rig", "rig\n1").AddField("f", 1.0),
+			wantErr: ErrInvalidPoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, err := tt.point.AppendLine([]byte("prefix\n"), tt.precision)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Point.AppendLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if string(line) != "prefix\n" {
+					t.Errorf("Point.AppendLine() = %q, want dst untouched on error", line)
+				}
+				return
+			}
+			if string(line) != "prefix\n"+tt.want {
+				t.Errorf("Point.AppendLine() = %q, want %q", line[len("prefix\n"):], tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParsePrecision(t *testing.T) {
+	for _, precision := range []Precision{Nanosecond, Microsecond, Millisecond, Second} {
+		if got, err := ParsePrecision(precision.String()); err != nil || got != precision {
+			t.Errorf("ParsePrecision(%s) = %v, %v, want %v", precision, got, err, precision)
+		}
+	}
+	if _, err := ParsePrecision("h"); err == nil {
+		t.Errorf("ParsePrecision(h) error = nil, want an error")
+	}
+}
+
+func Test_FieldFloat64(t *testing.T) {
+	p := NewPoint("m", time.Time{}).AddField("f", float32(1.5)).AddField("i", 3).AddField("u", uint64(4)).
+		AddField("b", true).AddField("r", big.NewRat(1, 4)).AddField("s", "ethminer")
+	want := []float64{1.5, 3, 4, 1, 0.25}
+	for i, f := range p.Fields {
+		value, ok := f.Float64()
+		if i < len(want) && (!ok || value != want[i]) {
+			t.Errorf("Field(%s).Float64() = %v, %v, want %v", f.Key, value, ok, want[i])
+		}
+		if i >= len(want) && ok {
+			t.Errorf("Field(%s).Float64() = %v, %v, want no value", f.Key, value, ok)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package lolminer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"mining-tools/rig"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAPIClient struct {
+	mock.Mock
+}
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func (mac *mockAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	args := mac.Called(req.URL.String())
+	resp = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(args.String(0))),
+	}
+	return resp, args.Error(1)
+}
+
+type cannedAPIClient struct {
+	statusCode int
+	body       string
+	err        error
+}
+
+func (cac *cannedAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	if cac.err != nil {
+		return nil, cac.err
+	}
+	resp = &http.Response{
+		StatusCode: cac.statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(cac.body)),
+	}
+	return
+}
+
+func Test_getErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *cannedAPIClient
+		wantErr error
+	}{
+		{
+			name:    "Success01",
+			client:  &cannedAPIClient{statusCode: http.StatusOK, body: summaryRig4},
+			wantErr: nil,
+		},
+		{
+			name:    "Unreachable01",
+			client:  &cannedAPIClient{err: errors.New("connect: connection refused")},
+			wantErr: rig.ErrUnreachable,
+		},
+		{
+			name:    "Malformed01",
+			client:  &cannedAPIClient{statusCode: http.StatusOK, body: `<html>lolMiner</html>`},
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "RequestFailed01",
+			client:  &cannedAPIClient{statusCode: http.StatusNotFound, body: `not found`},
+			wantErr: ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient("127.0.0.1:8020", WithHTTPClient(tt.client)).GetSummary(context.Background())
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetSummary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.client.statusCode) {
+				t.Errorf("Client.GetSummary() error = %#v, want *APIError with StatusCode %d", err, tt.client.statusCode)
+			}
+		})
+	}
+}
+
+func Test_GetSummary(t *testing.T) {
+	mockClient := &mockAPIClient{}
+	mockClient.On("Do", "http://10.0.0.4:8020/summary").Return(summaryRig4, nil)
+	summary, err := NewClient("10.0.0.4:8020", WithHTTPClient(mockClient)).GetSummary(context.Background())
+	if err != nil {
+		t.Fatalf("Client.GetSummary() error = %v", err)
+	}
+	want := SummaryGPU{Index: 1, Name: "AMD Radeon RX 6700 XT", Performance: 28.8, Consumption: 120, FanSpeed: 60, Temperature: 65,
+		MemTemperature: 82, SessionAccepted: 90, SessionSubmitted: 91, SessionHWErr: 1, PCIEAddress: "6:0"}
+	if len(summary.GPUs) != 2 || summary.GPUs[1] != want || summary.Session.PerformanceUnit != "mh/s" {
+		t.Errorf("Client.GetSummary() = %+v, want GPU 1 %+v in mh/s", summary, want)
+	}
+}
@@ -0,0 +1,84 @@
+package lolminer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mining-tools/rig"
+)
+
+// units are the hashes per second in each Performance_Unit lolMiner reports, solvers of Equihash and Cuckoo style
+// algorithms count solutions or graphs instead
+var units = map[string]float64{
+	"h/s":   1,
+	"kh/s":  1e3,
+	"mh/s":  1e6,
+	"gh/s":  1e9,
+	"sol/s": 1,
+	"g/s":   1,
+}
+
+// Rig adapts a Client to the rig.Rig interface
+type Rig struct {
+	client *Client
+}
+
+// NewRig returns a rig.Rig backed by client
+func NewRig(client *Client) *Rig {
+	return &Rig{client: client}
+}
+
+// Client returns the lolMiner Client behind the Rig
+func (r *Rig) Client() *Client {
+	return r.client
+}
+
+// Stats returns the hashrate, power, shares and sensor readings of the rig and each of its GPUs from the summary
+// endpoint, along with the worker name taken from the pool login
+func (r *Rig) Stats(ctx context.Context) (stats rig.Stats, err error) {
+	summary, err := r.client.GetSummary(ctx)
+	if err != nil {
+		return
+	}
+	unit, ok := units[strings.ToLower(summary.Session.PerformanceUnit)]
+	if !ok {
+		message := fmt.Sprintf("unknown Performance_Unit %q", summary.Session.PerformanceUnit)
+		return stats, &APIError{Endpoint: "summary", StatusCode: 200, Message: message, Err: ErrMalformedResponse}
+	}
+	// lolMiner sends its name and version separated by a space, e.g. "lolMiner 1.42"
+	stats.Miner = strings.ToLower(strings.Join(strings.Fields(summary.Software), "-"))
+	stats.Worker = worker(summary.Stratum.CurrentUser)
+	stats.Uptime = time.Duration(summary.Session.Uptime) * time.Second
+	stats.Hashrate = summary.Session.PerformanceSummary * unit
+	stats.Power = summary.Session.TotalPower
+	for _, gpu := range summary.GPUs {
+		stats.Shares.Invalid += gpu.SessionHWErr
+		stats.GPUs = append(stats.GPUs, rig.GPU{
+			Index:             gpu.Index,
+			Name:              gpu.Name,
+			Hashrate:          gpu.Performance * unit,
+			Temperature:       gpu.Temperature,
+			MemoryTemperature: gpu.MemTemperature,
+			FanSpeed:          gpu.FanSpeed,
+			Power:             gpu.Consumption,
+			Shares: rig.Shares{
+				Accepted: gpu.SessionAccepted,
+				Rejected: gpu.SessionSubmitted - gpu.SessionAccepted,
+				Invalid:  gpu.SessionHWErr,
+			},
+		})
+	}
+	stats.Shares.Accepted = summary.Session.Accepted
+	stats.Shares.Rejected = summary.Session.Submitted - summary.Session.Accepted
+	return
+}
+
+// worker returns the worker name of a pool login such as 0x01.rig1 or 0x01/rig1, or nothing when the login has none
+func worker(login string) string {
+	if i := strings.LastIndexAny(login, "./"); i >= 0 {
+		return login[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package lolminer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"mining-tools/rig"
+)
+
+func Test_Rig(t *testing.T) {
+	mockClient := &mockAPIClient{}
+	mockClient.On("Do", "http://10.0.0.4:8020/summary").Return(summaryRig4, nil)
+	mockClient.On("Do", "http://10.0.0.5:8020/summary").Return(strings.Replace(summaryRig4, `"mh/s"`, `"ph/s"`, 1), nil)
+	stats, err := NewRig(NewClient("10.0.0.4:8020", WithHTTPClient(mockClient))).Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Rig.Stats() error = %v", err)
+	}
+	want := rig.Stats{
+		Miner:    "lolminer-1.42",
+		Worker:   "rig4",
+		Uptime:   time.Hour,
+		Hashrate: 58800000,
+		Power:    250,
+		Shares:   rig.Shares{Accepted: 185, Rejected: 2, Invalid: 1},
+		GPUs: []rig.GPU{
+			{Index: 0, Name: "AMD Radeon RX 6700 XT", Hashrate: 30000000, Temperature: 61, MemoryTemperature: 78, FanSpeed: 55, Power: 130, Shares: rig.Shares{Accepted: 95, Rejected: 1}},
+			{Index: 1, Name: "AMD Radeon RX 6700 XT", Hashrate: 28800000, Temperature: 65, MemoryTemperature: 82, FanSpeed: 60, Power: 120, Shares: rig.Shares{Accepted: 90, Rejected: 1, Invalid: 1}},
+		},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("Rig.Stats() = %+v, want %+v", stats, want)
+	}
+	if _, err = NewRig(NewClient("10.0.0.5:8020", WithHTTPClient(mockClient))).Stats(context.Background()); !errors.Is(err, ErrMalformedResponse) {
+		t.Errorf("Rig.Stats() error = %v, want %v for an unknown unit", err, ErrMalformedResponse)
+	}
+}
+
+func Test_worker(t *testing.T) {
+	for login, want := range map[string]string{"0x01.rig4": "rig4", "0x01/rig4": "rig4", "0x01": "", "": ""} {
+		if got := worker(login); got != want {
+			t.Errorf("worker(%q) = %q, want %q", login, got, want)
+		}
+	}
+}
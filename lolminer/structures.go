@@ -0,0 +1,54 @@
+package lolminer
+
+// Summary is for decoding json from a successful response of the lolMiner summary endpoint, hashrates are in the
+// Performance_Unit of the session, power in W, temperatures in °C and Uptime in seconds
+type Summary struct {
+	Software string         `json:"Software"`
+	Mining   SummaryMining  `json:"Mining"`
+	Session  SummarySession `json:"Session"`
+	Stratum  SummaryStratum `json:"Stratum"`
+	GPUs     []SummaryGPU   `json:"GPUs"`
+}
+
+// SummaryMining is for decoding json from a successful response of the lolMiner summary endpoint
+type SummaryMining struct {
+	Algorithm string `json:"Algorithm"`
+}
+
+// SummarySession is for decoding json from a successful response of the lolMiner summary endpoint, shares that were
+// submitted but not accepted were rejected by the pool
+type SummarySession struct {
+	Startup            int64   `json:"Startup"`
+	Uptime             int64   `json:"Uptime"`
+	LastUpdate         int64   `json:"Last_Update"`
+	ActiveGPUs         int     `json:"Active_GPUs"`
+	PerformanceSummary float64 `json:"Performance_Summary"`
+	PerformanceUnit    string  `json:"Performance_Unit"`
+	Accepted           int64   `json:"Accepted"`
+	Submitted          int64   `json:"Submitted"`
+	TotalPower         float64 `json:"TotalPower"`
+}
+
+// SummaryStratum is for decoding json from a successful response of the lolMiner summary endpoint, CurrentUser is
+// the login sent to the pool, usually the wallet address and the worker name joined by a dot or a slash
+type SummaryStratum struct {
+	CurrentPool    string  `json:"Current_Pool"`
+	CurrentUser    string  `json:"Current_User"`
+	AverageLatency float64 `json:"Average_Latency"`
+}
+
+// SummaryGPU is for decoding json from a successful response of the lolMiner summary endpoint, HWErr counts the shares
+// that failed verification on the rig
+type SummaryGPU struct {
+	Index            int     `json:"Index"`
+	Name             string  `json:"Name"`
+	Performance      float64 `json:"Performance"`
+	Consumption      float64 `json:"Consumption (W)"`
+	FanSpeed         float64 `json:"Fan Speed (%)"`
+	Temperature      float64 `json:"Temp (deg C)"`
+	MemTemperature   float64 `json:"Mem Temp (deg C)"`
+	SessionAccepted  int64   `json:"Session_Accepted"`
+	SessionSubmitted int64   `json:"Session_Submitted"`
+	SessionHWErr     int64   `json:"Session_HWErr"`
+	PCIEAddress      string  `json:"PCIE_Address"`
+}
@@ -0,0 +1,12 @@
+package lolminer
+
+// summaryRig4 is a summary response of lolMiner 1.42 mining ETH on two GPUs
+const summaryRig4 = `{"Software":"lolMiner 1.42","Mining":{"Algorithm":"Ethash"},` +
+	`"Session":{"Startup":1609455600,"Startup_String":"2021-01-01 00:00:00","Uptime":3600,"Last_Update":1609459200,` +
+	`"Active_GPUs":2,"Performance_Summary":58.8,"Performance_Unit":"mh/s","Accepted":185,"Submitted":187,"TotalPower":250},` +
+	`"Stratum":{"Current_Pool":"eth-eu1.nanopool.org:9999","Current_User":"0x01.rig4","Average_Latency":31.5},` +
+	`"GPUs":[` +
+	`{"Index":0,"Name":"AMD Radeon RX 6700 XT","Performance":30,"Consumption (W)":130,"Fan Speed (%)":55,` +
+	`"Temp (deg C)":61,"Mem Temp (deg C)":78,"Session_Accepted":95,"Session_Submitted":96,"Session_HWErr":0,"PCIE_Address":"3:0"},` +
+	`{"Index":1,"Name":"AMD Radeon RX 6700 XT","Performance":28.8,"Consumption (W)":120,"Fan Speed (%)":60,` +
+	`"Temp (deg C)":65,"Mem Temp (deg C)":82,"Session_Accepted":90,"Session_Submitted":91,"Session_HWErr":1,"PCIE_Address":"6:0"}]}`
@@ -0,0 +1,106 @@
+package nanopool
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// Cache keeps successful responses for a TTL keyed by request URL, which is the API root plus endpoint and address,
+// so repeated calls within a run, or across runs when persisted, only reach nanopool once. A nil *Cache caches nothing
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	Body    json.RawMessage `json:"body"`
+	Expires time.Time       `json:"expires"`
+}
+
+// NewCache returns an empty in-memory Cache keeping responses for ttl
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// LoadCache returns a Cache keeping responses for ttl, filled with the unexpired entries saved at path.
+// A missing file is not an error and gives an empty Cache
+func LoadCache(path string, ttl time.Duration) (cache *Cache, err error) {
+	cache = NewCache(ttl)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return
+	}
+	entries := make(map[string]cacheEntry)
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return
+	}
+	now := time.Now()
+	for key, entry := range entries {
+		if entry.Expires.After(now) {
+			cache.entries[key] = entry
+		}
+	}
+	return
+}
+
+// Save writes the unexpired entries to path so a later run can LoadCache them
+func (c *Cache) Save(path string) (err error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	now := time.Now()
+	entries := make(map[string]cacheEntry, len(c.entries))
+	for key, entry := range c.entries {
+		if entry.Expires.After(now) {
+			entries[key] = entry
+		}
+	}
+	c.mu.Unlock()
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// Get returns the cached body for key if it has not expired
+func (c *Cache) Get(key string) (body []byte, ok bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	if !entry.Expires.After(time.Now()) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.Body, true
+}
+
+// Set stores body under key until the TTL passes
+func (c *Cache) Set(key string, body []byte) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{
+		Body:    append(json.RawMessage(nil), body...),
+		Expires: time.Now().Add(c.ttl),
+	}
+}
@@ -1,142 +1,458 @@
 package nanopool
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
+	"mining-tools/throttle"
+
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// DefaultBaseURL is the root of the nanopool API, the coin is appended to it to form the API root
+	DefaultBaseURL = "https://api.nanopool.org/v1/"
+	// DefaultCoin is the coin a Client talks to when WithCoin is not given
+	DefaultCoin = ETH
+	// DefaultTimeout is the per-call timeout a Client applies when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+)
+
 // HTTPClient is an interface to abstract http.client to support testing using mocks
 type HTTPClient interface {
-	Get(url string) (resp *http.Response, err error)
+	Do(req *http.Request) (resp *http.Response, err error)
 }
 
 var (
-	apiClient = HTTPClient(&http.Client{Timeout: 10 * time.Second})
+	apiClient = HTTPClient(&http.Client{})
 )
 
-func get(fullPath string, output interface{}) (err error) {
-	log.Debugf("get(fullPath=%s, output interface{}) called\n", fullPath)
-	resp, err := apiClient.Get(fullPath)
+// Client talks to a single nanopool API root using its own transport, user agent and timeout
+type Client struct {
+	baseURL    string
+	coin       Coin
+	apiRoot    string
+	httpClient HTTPClient
+	userAgent  string
+	timeout    time.Duration
+	limiter    *throttle.Limiter
+	retry      throttle.Policy
+	cache      *Cache
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithBaseURL sets the URL the coin is appended to, e.g. https://api.nanopool.org/v1/
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithCoin sets the coin the Client talks to, which picks the path segment of the API root and the coin's quirks
+func WithCoin(coin Coin) Option {
+	return func(c *Client) {
+		c.coin = coin
+	}
+}
+
+// WithAPIRoot sets the full API root, e.g. https://api.nanopool.org/v1/eth/, ignoring base URL and coin when building URLs.
+// WithCoin should still be given for non-ETH roots so the coin's quirks are applied
+func WithAPIRoot(apiRoot string) Option {
+	return func(c *Client) {
+		c.apiRoot = apiRoot
+	}
+}
+
+// WithHTTPClient sets the transport used for every request
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the timeout applied to each call, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRateLimiter sets the limiter every request waits on, share one limiter between all clients of the same API
+func WithRateLimiter(limiter *throttle.Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried, by default a request is attempted once
+func WithRetryPolicy(policy throttle.Policy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithCache sets the cache successful responses are kept in, share one cache between clients to share responses
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// NewClient returns a Client for the default nanopool ethereum API root adjusted by options
+func NewClient(options ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		coin:       DefaultCoin,
+		httpClient: apiClient,
+		timeout:    DefaultTimeout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Coin returns the coin the Client talks to
+func (c *Client) Coin() Coin {
+	return c.coin
+}
+
+// APIRoot returns the URL every endpoint path is appended to
+func (c *Client) APIRoot() string {
+	if c.apiRoot != "" {
+		return c.apiRoot
+	}
+	return fmt.Sprintf("%s%s/", c.baseURL, c.coin)
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, output interface{}) (err error) {
+	fullPath := c.APIRoot() + endpoint
+	log.Debugf("Client.get(fullPath=%s, output interface{}) called\n", fullPath)
+	if body, ok := c.cache.Get(fullPath); ok {
+		log.Debugf("Client.get: using cached response for %s\n", fullPath)
+		return json.Unmarshal(body, output)
+	}
+	resp, attempts, err := c.retry.Do(ctx, c.limiter, func(ctx context.Context) (*http.Response, error) {
+		return c.send(ctx, fullPath)
+	})
+	defer func() {
+		if err != nil && attempts > 1 {
+			err = &throttle.Error{Attempts: attempts, Err: err}
+		}
+	}()
 	if err != nil {
-		fmt.Println(err)
-		log.Errorf("get: apiClient.Get(%s); returned err=%s\n", fullPath, err.Error())
-		// TODO: handle error
+		log.Errorf("Client.get: c.send(ctx, %s); returned err=%s after %d attempts\n", fullPath, err.Error(), attempts)
 		return
 	}
 	defer resp.Body.Close()
-	err = json.NewDecoder(resp.Body).Decode(output)
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Println(err)
-		log.Errorf("get: json.NewDecoder(resp.Body).Decode(output); returned err=%s\n", err.Error())
-		// TODO: handle error
+		log.Errorf("Client.get: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
 		return
 	}
-	return
-}
-
-// GetMinerGeneralInfo calls the Miner:General Info endpoint user/:address and forms the response in to a usable Struct
-func GetMinerGeneralInfo(apiRoot string, address string) (info MinerGeneralInfo, err error) {
-	log.Debugln("GetMinerGeneralInfo called")
-	resp, err := apiClient.Get(fmt.Sprintf("%s%s%s", apiRoot, "user/", address))
+	envelope := new(ErrorResponse)
+	if json.Unmarshal(body, envelope) != nil {
+		envelope = nil
+	}
+	err = checkResponse(endpoint, resp.StatusCode, envelope)
 	if err != nil {
-		fmt.Println(err)
-		log.Errorf("GetMinerGeneralInfo: apiClient.Get(%suser/%s); returned err=%s\n", apiRoot, address, err.Error())
-		// TODO: handle error
+		log.Errorf("Client.get: checkResponse(%s, %d, envelope); returned err=%s after %d attempts\n", endpoint, resp.StatusCode, err.Error(), attempts)
 		return
 	}
-	defer resp.Body.Close()
-	err = json.NewDecoder(resp.Body).Decode(&info)
+	err = json.Unmarshal(body, output)
 	if err != nil {
-		fmt.Println(err)
-		log.Errorf("GetMinerGeneralInfo: json.NewDecoder(resp.Body).Decode(&info); returned err=%s\n", err.Error())
-		// TODO: handle error
+		log.Errorf("Client.get: json.Unmarshal(body, output); returned err=%s\n", err.Error())
+		err = &APIError{Endpoint: endpoint, StatusCode: resp.StatusCode, Message: err.Error(), Err: ErrMalformedResponse}
 		return
 	}
+	c.cache.Set(fullPath, body)
 	return
 }
 
-// GetMinerPayments calls the Miner:Payments endpoint payments/:address and forms the response in to a usable Struct
-func GetMinerPayments(apiRoot string, address string) (payments MinerPayments, err error) {
-	resp, err := apiClient.Get(fmt.Sprintf("%s%s%s", apiRoot, "payments/", address))
+// send makes a single attempt at fullPath within the per-call timeout, the body is read before the timeout is released
+func (c *Client) send(ctx context.Context, fullPath string) (resp *http.Response, err error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath, nil)
 	if err != nil {
-		// TODO: Log error
-		fmt.Println(err)
-		// TODO: handle error
+		log.Errorf("Client.send: http.NewRequestWithContext(ctx, GET, %s, nil); returned err=%s\n", fullPath, err.Error())
 		return
 	}
-	defer resp.Body.Close()
-	err = json.NewDecoder(resp.Body).Decode(&payments)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err = c.httpClient.Do(req)
 	if err != nil {
-		// TODO: Log error
-		fmt.Println(err)
-		// TODO: handle error
+		log.Errorf("Client.send: c.httpClient.Do(%s); returned err=%s\n", fullPath, err.Error())
 		return
 	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		log.Errorf("Client.send: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return
+}
+
+// GetMinerGeneralInfo calls the Miner:General Info endpoint user/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerGeneralInfo(ctx context.Context, address string) (info MinerGeneralInfo, err error) {
+	err = c.get(ctx, "user/"+address, &info)
+	return
+}
+
+// GetMinerPayments calls the Miner:Payments endpoint payments/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerPayments(ctx context.Context, address string) (payments MinerPayments, err error) {
+	err = c.get(ctx, "payments/"+address, &payments)
 	return
 }
 
 // GetMinerShareRate calls the Miner:Share Rate History endpoint shareratehistory/:address and forms the response in to a usable Struct
-func GetMinerShareRate(apiRoot string, address string) (shareRate MinerShareRate, err error) {
-	resp, err := apiClient.Get(fmt.Sprintf("%s%s%s", apiRoot, "shareratehistory/", address))
-	if err != nil {
-		// TODO: Log error
-		fmt.Println(err)
-		// TODO: handle error
-		return
-	}
-	defer resp.Body.Close()
-	err = json.NewDecoder(resp.Body).Decode(&shareRate)
-	if err != nil {
-		// TODO: Log error
-		fmt.Println(err)
-		// TODO: handle error
+func (c *Client) GetMinerShareRate(ctx context.Context, address string) (shareRate MinerShareRate, err error) {
+	err = c.get(ctx, "shareratehistory/"+address, &shareRate)
+	return
+}
+
+// GetMinerBalance calls the Miner:Balance endpoint balance/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerBalance(ctx context.Context, address string) (minerBalance MinerBalance, err error) {
+	err = c.get(ctx, "balance/"+address, &minerBalance)
+	return
+}
+
+// GetOtherPrices calls the Other:Prices endpoint prices/ and forms the response in to a usable Struct
+func (c *Client) GetOtherPrices(ctx context.Context) (prices OtherPrices, err error) {
+	err = c.get(ctx, "prices/", &prices)
+	return
+}
+
+// GetMinerAccountExist calls the Miner:Check Account endpoint accountexist/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerAccountExist(ctx context.Context, address string) (accountExist MinerAccountExist, err error) {
+	err = c.get(ctx, "accountexist/"+address, &accountExist)
+	return
+}
+
+// GetMinerHashrate calls the Miner:Current Hashrate endpoint hashrate/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerHashrate(ctx context.Context, address string) (hashrate MinerHashrate, err error) {
+	err = c.get(ctx, "hashrate/"+address, &hashrate)
+	return
+}
+
+// GetMinerHashrateChart calls the Miner:Chart Data endpoint hashratechart/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerHashrateChart(ctx context.Context, address string) (chart MinerHashrateChart, err error) {
+	err = c.get(ctx, "hashratechart/"+address, &chart)
+	return
+}
+
+// GetMinerHistory calls the Miner:Hashrate History endpoint history/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerHistory(ctx context.Context, address string) (history MinerHistory, err error) {
+	err = c.get(ctx, "history/"+address, &history)
+	return
+}
+
+// GetMinerBalanceHashrate calls the Miner:Balance and Hashrate endpoint balance_hashrate/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerBalanceHashrate(ctx context.Context, address string) (balanceHashrate MinerBalanceHashrate, err error) {
+	err = c.get(ctx, "balance_hashrate/"+address, &balanceHashrate)
+	return
+}
+
+// GetMinerAvgHashrate calls the Miner:Average Hashrate endpoint avghashrate/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerAvgHashrate(ctx context.Context, address string) (avgHashrate MinerAvgHashrates, err error) {
+	err = c.get(ctx, "avghashrate/"+address, &avgHashrate)
+	return
+}
+
+// GetMinerAvgHashrateLimited calls the Miner:Average Hashrate for N hours endpoint avghashratelimited/:address/:hours and
+// forms the response in to a usable Struct
+func (c *Client) GetMinerAvgHashrateLimited(ctx context.Context, address string, hours int64) (avgHashrate MinerHashrate, err error) {
+	err = c.get(ctx, fmt.Sprintf("avghashratelimited/%s/%d", address, hours), &avgHashrate)
+	return
+}
+
+// GetMinerReportedHashrate calls the Miner:Reported Hashrate endpoint reportedhashrate/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerReportedHashrate(ctx context.Context, address string) (reportedHashrate MinerHashrate, err error) {
+	if err = c.requireReportedHashrate("reportedhashrate/"); err != nil {
 		return
 	}
+	err = c.get(ctx, "reportedhashrate/"+address, &reportedHashrate)
 	return
 }
 
-// GetMinerBalance calls the Miner:Balance endpoint balance/:address and forms the response in to a usable Struct
-func GetMinerBalance(apiRoot string, address string) (minerBalance MinerBalance, err error) {
-	resp, err := apiClient.Get(fmt.Sprintf("%s%s%s", apiRoot, "balance/", address))
-	if err != nil {
-		fmt.Println(err)
-		log.Errorf("GetMinerBalance: apiClient.Get(%sbalance/%s); returned err=%s\n", apiRoot, address, err.Error())
-		// TODO: handle error
+// GetMinerReportedHashrates calls the Miner:Workers Reported Hashrate endpoint reportedhashrates/:address and forms the
+// response in to a usable Struct
+func (c *Client) GetMinerReportedHashrates(ctx context.Context, address string) (reportedHashrates MinerReportedHashrates, err error) {
+	if err = c.requireReportedHashrate("reportedhashrates/"); err != nil {
 		return
 	}
-	defer resp.Body.Close()
-	err = json.NewDecoder(resp.Body).Decode(&minerBalance)
-	if err != nil {
-		fmt.Println(err)
-		log.Errorf("GetMinerBalance: json.NewDecoder(resp.Body).Decode(&minerBalance); returned err=%s\n", err.Error())
-		// TODO: handle error
+	err = c.get(ctx, "reportedhashrates/"+address, &reportedHashrates)
+	return
+}
+
+// GetMinerWorkers calls the Miner:Workers endpoint workers/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerWorkers(ctx context.Context, address string) (workers MinerWorkers, err error) {
+	err = c.get(ctx, "workers/"+address, &workers)
+	return
+}
+
+// GetMinerPaymentsDay calls the Miner:Payments by Day endpoint paymentsday/:address and forms the response in to a usable Struct
+func (c *Client) GetMinerPaymentsDay(ctx context.Context, address string) (paymentsDay MinerPaymentsDay, err error) {
+	err = c.get(ctx, "paymentsday/"+address, &paymentsDay)
+	return
+}
+
+// GetUserSettings calls the Miner:User Settings endpoint usersettings/:address and forms the response in to a usable Struct
+func (c *Client) GetUserSettings(ctx context.Context, address string) (settings UserSettings, err error) {
+	err = c.get(ctx, "usersettings/"+address, &settings)
+	return
+}
+
+// GetWorkerHashrate calls the Worker:Current Hashrate endpoint hashrate/:address/:worker and forms the response in to a usable Struct
+func (c *Client) GetWorkerHashrate(ctx context.Context, address string, worker string) (hashrate MinerHashrate, err error) {
+	err = c.get(ctx, workerEndpoint("hashrate/", address, worker), &hashrate)
+	return
+}
+
+// GetWorkerReportedHashrate calls the Worker:Reported Hashrate endpoint reportedhashrate/:address/:worker and forms the
+// response in to a usable Struct
+func (c *Client) GetWorkerReportedHashrate(ctx context.Context, address string, worker string) (reportedHashrate MinerHashrate, err error) {
+	if err = c.requireReportedHashrate("reportedhashrate/"); err != nil {
 		return
 	}
+	err = c.get(ctx, workerEndpoint("reportedhashrate/", address, worker), &reportedHashrate)
 	return
 }
 
-// GetOtherPrices calls the Miner:Balance endpoint prices/ and forms the response in to a usable Struct
-func GetOtherPrices(apiRoot string) (prices OtherPrices, err error) {
-	fullPath := fmt.Sprintf("%s%s", apiRoot, "prices/")
-	err = get(fullPath, &prices)
-	// resp, err := apiClient.Get(fmt.Sprintf("%s%s", apiRoot, "prices/"))
-	// if err != nil {
-	// 	fmt.Println(err)
-	// 	log.Errorf("GetOtherPrices: apiClient.Get(%sbalance/); returned err=%s\n", apiRoot, err.Error())
-	// 	// TODO: handle error
-	// 	return
-	// }
-	// defer resp.Body.Close()
-	// err = json.NewDecoder(resp.Body).Decode(&prices)
-	// if err != nil {
-	// 	fmt.Println(err)
-	// 	log.Errorf("GetOtherPrices: json.NewDecoder(resp.Body).Decode(&prices); returned err=%s\n", err.Error())
-	// 	// TODO: handle error
-	// 	return
-	// }
+// GetWorkerHistory calls the Worker:Hashrate History endpoint history/:address/:worker and forms the response in to a usable Struct
+func (c *Client) GetWorkerHistory(ctx context.Context, address string, worker string) (history MinerHistory, err error) {
+	err = c.get(ctx, workerEndpoint("history/", address, worker), &history)
+	return
+}
+
+// GetWorkerHashrateChart calls the Worker:Chart Data endpoint hashratechart/:address/:worker and forms the response in to a usable Struct
+func (c *Client) GetWorkerHashrateChart(ctx context.Context, address string, worker string) (chart MinerHashrateChart, err error) {
+	err = c.get(ctx, workerEndpoint("hashratechart/", address, worker), &chart)
+	return
+}
+
+// GetWorkerShareRate calls the Worker:Share Rate History endpoint shareratehistory/:address/:worker and forms the response in to a usable Struct
+func (c *Client) GetWorkerShareRate(ctx context.Context, address string, worker string) (shareRate MinerShareRate, err error) {
+	err = c.get(ctx, workerEndpoint("shareratehistory/", address, worker), &shareRate)
+	return
+}
+
+// GetApproximatedEarnings calls the Other:Approximated Earnings endpoint approximated_earnings/:hashrate and forms the
+// response in to a usable Struct, hashrate is in the unit nanopool reports for the coin
+func (c *Client) GetApproximatedEarnings(ctx context.Context, hashrate Hashrate) (earnings ApproximatedEarnings, err error) {
+	err = c.get(ctx, "approximated_earnings/"+strconv.FormatFloat(float64(hashrate), 'f', -1, 64), &earnings)
+	return
+}
+
+// GetPoolActiveMiners calls the Pool:Active Miners endpoint pool/activeminers and forms the response in to a usable Struct
+func (c *Client) GetPoolActiveMiners(ctx context.Context) (activeMiners PoolCount, err error) {
+	err = c.get(ctx, "pool/activeminers", &activeMiners)
+	return
+}
+
+// GetPoolActiveWorkers calls the Pool:Active Workers endpoint pool/activeworkers and forms the response in to a usable Struct
+func (c *Client) GetPoolActiveWorkers(ctx context.Context) (activeWorkers PoolCount, err error) {
+	err = c.get(ctx, "pool/activeworkers", &activeWorkers)
 	return
 }
+
+// GetPoolHashrate calls the Pool:Hashrate endpoint pool/hashrate and forms the response in to a usable Struct
+func (c *Client) GetPoolHashrate(ctx context.Context) (hashrate PoolHashrate, err error) {
+	err = c.get(ctx, "pool/hashrate", &hashrate)
+	return
+}
+
+// GetPoolTopMiners calls the Pool:Top Miners endpoint pool/topminers and forms the response in to a usable Struct
+func (c *Client) GetPoolTopMiners(ctx context.Context) (topMiners PoolTopMiners, err error) {
+	err = c.get(ctx, "pool/topminers", &topMiners)
+	return
+}
+
+// GetPoolPayments calls the Pool:Payments endpoint payments/:offset/:count and forms the response in to a usable Struct
+func (c *Client) GetPoolPayments(ctx context.Context, offset int64, count int64) (payments PoolPayments, err error) {
+	err = c.get(ctx, fmt.Sprintf("payments/%d/%d", offset, count), &payments)
+	return
+}
+
+// GetNetworkAvgBlockTime calls the Network:Average Block Time endpoint network/avgblocktime and forms the response in to a usable Struct
+func (c *Client) GetNetworkAvgBlockTime(ctx context.Context) (avgBlockTime NetworkAvgBlockTime, err error) {
+	err = c.get(ctx, "network/avgblocktime", &avgBlockTime)
+	return
+}
+
+// GetNetworkLastBlockNumber calls the Network:Last Block Number endpoint network/lastblocknumber and forms the response in to a usable Struct
+func (c *Client) GetNetworkLastBlockNumber(ctx context.Context) (lastBlockNumber NetworkLastBlockNumber, err error) {
+	err = c.get(ctx, "network/lastblocknumber", &lastBlockNumber)
+	return
+}
+
+// GetBlockStats calls the Network:Block Stats endpoint block_stats/:offset/:count and forms the response in to a usable Struct
+func (c *Client) GetBlockStats(ctx context.Context, offset int64, count int64) (blockStats BlockStats, err error) {
+	err = c.get(ctx, fmt.Sprintf("block_stats/%d/%d", offset, count), &blockStats)
+	return
+}
+
+// GetBlocks calls the Pool:Blocks endpoint blocks/:offset/:count and forms the response in to a usable Struct
+func (c *Client) GetBlocks(ctx context.Context, offset int64, count int64) (blocks Blocks, err error) {
+	err = c.get(ctx, fmt.Sprintf("blocks/%d/%d", offset, count), &blocks)
+	return
+}
+
+func (c *Client) requireReportedHashrate(endpoint string) error {
+	if c.coin.Info().ReportedHashrate {
+		return nil
+	}
+	return &APIError{Endpoint: endpoint, Message: fmt.Sprintf("%s miners do not report hashrate", c.coin.Info().Name), Err: ErrUnsupported}
+}
+
+func workerEndpoint(endpoint string, address string, worker string) string {
+	return fmt.Sprintf("%s%s/%s", endpoint, address, url.PathEscape(worker))
+}
+
+// GetMinerGeneralInfo calls the Miner:General Info endpoint user/:address and forms the response in to a usable Struct
+func GetMinerGeneralInfo(apiRoot string, address string) (info MinerGeneralInfo, err error) {
+	log.Debugln("GetMinerGeneralInfo called")
+	return NewClient(WithAPIRoot(apiRoot)).GetMinerGeneralInfo(context.Background(), address)
+}
+
+// GetMinerPayments calls the Miner:Payments endpoint payments/:address and forms the response in to a usable Struct
+func GetMinerPayments(apiRoot string, address string) (payments MinerPayments, err error) {
+	return NewClient(WithAPIRoot(apiRoot)).GetMinerPayments(context.Background(), address)
+}
+
+// GetMinerShareRate calls the Miner:Share Rate History endpoint shareratehistory/:address and forms the response in to a usable Struct
+func GetMinerShareRate(apiRoot string, address string) (shareRate MinerShareRate, err error) {
+	return NewClient(WithAPIRoot(apiRoot)).GetMinerShareRate(context.Background(), address)
+}
+
+// GetMinerBalance calls the Miner:Balance endpoint balance/:address and forms the response in to a usable Struct
+func GetMinerBalance(apiRoot string, address string) (minerBalance MinerBalance, err error) {
+	return NewClient(WithAPIRoot(apiRoot)).GetMinerBalance(context.Background(), address)
+}
+
+// GetOtherPrices calls the Other:Prices endpoint prices/ and forms the response in to a usable Struct
+func GetOtherPrices(apiRoot string) (prices OtherPrices, err error) {
+	return NewClient(WithAPIRoot(apiRoot)).GetOtherPrices(context.Background())
+}
@@ -2,12 +2,18 @@ package nanopool
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
+
+	"mining-tools/throttle"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
@@ -21,11 +27,20 @@ func init() {
 	log.SetLevel(log.DebugLevel)
 }
 
-func (mac *mockAPIClient) Get(url string) (resp *http.Response, err error) {
-	args := mac.Called(url)
+func mustParseAmount(s string) Amount {
+	amount, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+func (mac *mockAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	args := mac.Called(req.URL.String())
 	body, _ := json.Marshal(args.Get(0))
 	resp = &http.Response{
-		Body: ioutil.NopCloser(bytes.NewBuffer(body)),
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBuffer(body)),
 	}
 	return resp, args.Error(1)
 }
@@ -36,9 +51,9 @@ func Test_getMinerShareRate(t *testing.T) {
 	apiClient = mockClient
 	success01 := GenerateShareRate(true, 10, 24, true)
 	success02 := GenerateShareRate(true, 10, 24, true)
-	mockClient.On("Get", "http://test.com/shareratehistory/0x01").Return(success01, nil)
-	mockClient.On("Get", "http://test.com/shareratehistory/").Return(*new(MinerShareRate), errors.New("Timedout"))
-	mockClient.On("Get", "http://test.com/shareratehistory/0x02").Return(success02, nil)
+	mockClient.On("Do", "http://test.com/shareratehistory/0x01").Return(success01, nil)
+	mockClient.On("Do", "http://test.com/shareratehistory/").Return(*new(MinerShareRate), errors.New("Timedout"))
+	mockClient.On("Do", "http://test.com/shareratehistory/0x02").Return(success02, nil)
 	type args struct {
 		apiRoot string
 		address string
@@ -95,7 +110,7 @@ func Test_getMinerGeneralInfo(t *testing.T) {
 	// Set up
 	mockClient := &mockAPIClient{}
 	apiClient = mockClient
-	mockClient.On("Get", "http://test.com/user/0x01").Return(user0x01Success, nil)
+	mockClient.On("Do", "http://test.com/user/0x01").Return(user0x01Success, nil)
 	type args struct {
 		apiRoot string
 		address string
@@ -129,3 +144,637 @@ func Test_getMinerGeneralInfo(t *testing.T) {
 		})
 	}
 }
+
+type recordingAPIClient struct {
+	req *http.Request
+}
+
+func (rac *recordingAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	rac.req = req
+	if _, ok := req.Context().Deadline(); ok {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	resp = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"status":true,"data":1.5}`)),
+	}
+	return
+}
+
+func Test_ClientOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []Option
+		wantURL       string
+		wantUserAgent string
+		wantErr       bool
+	}{
+		{
+			name:    "Defaults",
+			options: []Option{WithTimeout(0)},
+			wantURL: "https://api.nanopool.org/v1/eth/balance/0x01",
+		},
+		{
+			name:    "BaseURLAndCoin",
+			options: []Option{WithBaseURL("http://test.com/v1/"), WithCoin("etc"), WithTimeout(0)},
+			wantURL: "http://test.com/v1/etc/balance/0x01",
+		},
+		{
+			name:          "APIRootAndUserAgent",
+			options:       []Option{WithAPIRoot("http://test.com/"), WithCoin("etc"), WithUserAgent("mining-tools"), WithTimeout(0)},
+			wantURL:       "http://test.com/balance/0x01",
+			wantUserAgent: "mining-tools",
+		},
+		{
+			name:    "Timeout",
+			options: []Option{WithTimeout(time.Millisecond)},
+			wantURL: "https://api.nanopool.org/v1/eth/balance/0x01",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rac := &recordingAPIClient{}
+			c := NewClient(append(tt.options, WithHTTPClient(rac))...)
+			gotBalance, err := c.GetMinerBalance(context.Background(), "0x01")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.GetMinerBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotURL := rac.req.URL.String(); gotURL != tt.wantURL {
+				t.Errorf("Client.GetMinerBalance() url = %v, want %v", gotURL, tt.wantURL)
+			}
+			if gotUserAgent := rac.req.Header.Get("User-Agent"); gotUserAgent != tt.wantUserAgent {
+				t.Errorf("Client.GetMinerBalance() User-Agent = %v, want %v", gotUserAgent, tt.wantUserAgent)
+			}
+			if !tt.wantErr && gotBalance.Data != mustParseAmount("1.5") {
+				t.Errorf("Client.GetMinerBalance() = %v, want %v", gotBalance.Data, 1.5)
+			}
+		})
+	}
+}
+
+type cannedAPIClient struct {
+	statusCode int
+	body       string
+}
+
+func (cac *cannedAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	resp = &http.Response{
+		StatusCode: cac.statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(cac.body)),
+	}
+	return
+}
+
+func Test_getErrors(t *testing.T) {
+	errorBody, _ := json.Marshal(user0x02Error)
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    error
+	}{
+		{
+			name:       "Success01",
+			statusCode: http.StatusOK,
+			body:       `{"status":true,"data":0.142}`,
+			wantErr:    nil,
+		},
+		{
+			name:       "AddressNotFound01",
+			statusCode: http.StatusOK,
+			body:       string(errorBody),
+			wantErr:    ErrAddressNotFound,
+		},
+		{
+			name:       "AddressNotFound02",
+			statusCode: http.StatusNotFound,
+			body:       `{"status":false,"error":"Unknown"}`,
+			wantErr:    ErrAddressNotFound,
+		},
+		{
+			name:       "AddressNotFound03",
+			statusCode: http.StatusNotFound,
+			body:       `<html>404 Not Found</html>`,
+			wantErr:    ErrAddressNotFound,
+		},
+		{
+			name:       "RequestFailed02",
+			statusCode: http.StatusForbidden,
+			body:       `Forbidden`,
+			wantErr:    ErrRequestFailed,
+		},
+		{
+			name:       "RateLimited01",
+			statusCode: http.StatusTooManyRequests,
+			body:       `Too Many Requests`,
+			wantErr:    ErrRateLimited,
+		},
+		{
+			name:       "ServerError01",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>Bad Gateway</html>`,
+			wantErr:    ErrServer,
+		},
+		{
+			name:       "Malformed01",
+			statusCode: http.StatusOK,
+			body:       `<html>maintenance</html>`,
+			wantErr:    ErrMalformedResponse,
+		},
+		{
+			name:       "Malformed02",
+			statusCode: http.StatusOK,
+			body:       `{"status":true,"data":"lots"}`,
+			wantErr:    ErrMalformedResponse,
+		},
+		{
+			name:       "RequestFailed01",
+			statusCode: http.StatusOK,
+			body:       `{"status":false,"error":"Something else"}`,
+			wantErr:    ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(WithAPIRoot("http://test.com/"), WithHTTPClient(&cannedAPIClient{tt.statusCode, tt.body}))
+			_, err := c.GetMinerBalance(context.Background(), "0x02")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetMinerBalance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.statusCode) {
+				t.Errorf("Client.GetMinerBalance() error = %#v, want *APIError with StatusCode %d", err, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_minerEndpoints(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	c := NewClient(WithAPIRoot("http://test.com/"), WithHTTPClient(mockClient))
+	ctx := context.Background()
+	hashrate := MinerHashrate{Status: true, Data: 95.5}
+	chart := MinerHashrateChart{Status: true, Data: []MinerHashrateChartData{{Date: 1609459200, Shares: 12, Hashrate: 95.5}}}
+	history := MinerHistory{Status: true, Data: []MinerHistoryData{{Date: 1609459200, Hashrate: 95.5}}}
+	shareRate := GenerateShareRate(true, 10, 1, false)
+	mockClient.On("Do", "http://test.com/accountexist/0x01").Return(MinerAccountExist{Status: true, Data: "Account exists"}, nil)
+	mockClient.On("Do", "http://test.com/accountexist/0x02").Return(user0x02Error, nil)
+	mockClient.On("Do", "http://test.com/hashrate/0x01").Return(hashrate, nil)
+	mockClient.On("Do", "http://test.com/hashratechart/0x01").Return(chart, nil)
+	mockClient.On("Do", "http://test.com/history/0x01").Return(history, nil)
+	mockClient.On("Do", "http://test.com/balance_hashrate/0x01").Return(MinerBalanceHashrate{Status: true, Data: MinerBalanceHashrateData{Hashrate: 95.5, Balance: mustParseAmount("0.142")}}, nil)
+	mockClient.On("Do", "http://test.com/avghashrate/0x01").Return(MinerAvgHashrates{Status: true, Data: MinerAvgHashrate{H1: 95.5, H24: 90.1}}, nil)
+	mockClient.On("Do", "http://test.com/avghashratelimited/0x01/6").Return(hashrate, nil)
+	mockClient.On("Do", "http://test.com/reportedhashrate/0x01").Return(hashrate, nil)
+	mockClient.On("Do", "http://test.com/reportedhashrates/0x01").Return(MinerReportedHashrates{Status: true, Data: []MinerReportedHashrateData{{Worker: "rig1", Hashrate: 95.5}}}, nil)
+	mockClient.On("Do", "http://test.com/workers/0x01").Return(MinerWorkers{Status: true, Data: user0x01Success.Data.Workers}, nil)
+	mockClient.On("Do", "http://test.com/paymentsday/0x01").Return(MinerPaymentsDay{Status: true, Data: []MinerPaymentsDayData{{Date: 1609459200, Amount: mustParseAmount("0.1")}}}, nil)
+	mockClient.On("Do", "http://test.com/usersettings/0x01").Return(UserSettings{Status: true, Data: UserSettingsData{Payout: mustParseAmount("0.2")}}, nil)
+	mockClient.On("Do", "http://test.com/hashrate/0x01/rig%201").Return(hashrate, nil)
+	mockClient.On("Do", "http://test.com/reportedhashrate/0x01/rig1").Return(hashrate, nil)
+	mockClient.On("Do", "http://test.com/history/0x01/rig1").Return(history, nil)
+	mockClient.On("Do", "http://test.com/hashratechart/0x01/rig1").Return(chart, nil)
+	mockClient.On("Do", "http://test.com/shareratehistory/0x01/rig1").Return(shareRate, nil)
+	mockClient.On("Do", "http://test.com/approximated_earnings/95.5").Return(ApproximatedEarnings{Status: true, Data: ApproximatedEarningsData{Day: ApproximatedEarningsPeriod{Coins: 0.01, Dollars: 7.3}}}, nil)
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name:     "AccountExist01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerAccountExist(ctx, "0x01"); return r.Data, err },
+			wantData: "Account exists",
+		},
+		{
+			name:     "AccountExist02",
+			call:     func() (interface{}, error) { r, err := c.GetMinerAccountExist(ctx, "0x02"); return r.Data, err },
+			wantData: "",
+			wantErr:  ErrAddressNotFound,
+		},
+		{
+			name:     "Hashrate01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerHashrate(ctx, "0x01"); return r, err },
+			wantData: hashrate,
+		},
+		{
+			name:     "HashrateChart01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerHashrateChart(ctx, "0x01"); return r, err },
+			wantData: chart,
+		},
+		{
+			name:     "History01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerHistory(ctx, "0x01"); return r, err },
+			wantData: history,
+		},
+		{
+			name:     "BalanceHashrate01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerBalanceHashrate(ctx, "0x01"); return r.Data, err },
+			wantData: MinerBalanceHashrateData{Hashrate: 95.5, Balance: mustParseAmount("0.142")},
+		},
+		{
+			name:     "UserSettings01",
+			call:     func() (interface{}, error) { r, err := c.GetUserSettings(ctx, "0x01"); return r.Data, err },
+			wantData: UserSettingsData{Payout: mustParseAmount("0.2")},
+		},
+		{
+			name:     "AvgHashrate01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerAvgHashrate(ctx, "0x01"); return r.Data, err },
+			wantData: MinerAvgHashrate{H1: 95.5, H24: 90.1},
+		},
+		{
+			name:     "AvgHashrateLimited01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerAvgHashrateLimited(ctx, "0x01", 6); return r, err },
+			wantData: hashrate,
+		},
+		{
+			name:     "ReportedHashrate01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerReportedHashrate(ctx, "0x01"); return r, err },
+			wantData: hashrate,
+		},
+		{
+			name:     "ReportedHashrates01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerReportedHashrates(ctx, "0x01"); return r.Data, err },
+			wantData: []MinerReportedHashrateData{{Worker: "rig1", Hashrate: 95.5}},
+		},
+		{
+			name:     "Workers01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerWorkers(ctx, "0x01"); return r.Data, err },
+			wantData: user0x01Success.Data.Workers,
+		},
+		{
+			name:     "PaymentsDay01",
+			call:     func() (interface{}, error) { r, err := c.GetMinerPaymentsDay(ctx, "0x01"); return r.Data, err },
+			wantData: []MinerPaymentsDayData{{Date: 1609459200, Amount: mustParseAmount("0.1")}},
+		},
+		{
+			name:     "WorkerHashrate01",
+			call:     func() (interface{}, error) { r, err := c.GetWorkerHashrate(ctx, "0x01", "rig 1"); return r, err },
+			wantData: hashrate,
+		},
+		{
+			name:     "WorkerReportedHashrate01",
+			call:     func() (interface{}, error) { r, err := c.GetWorkerReportedHashrate(ctx, "0x01", "rig1"); return r, err },
+			wantData: hashrate,
+		},
+		{
+			name:     "WorkerHistory01",
+			call:     func() (interface{}, error) { r, err := c.GetWorkerHistory(ctx, "0x01", "rig1"); return r, err },
+			wantData: history,
+		},
+		{
+			name:     "WorkerHashrateChart01",
+			call:     func() (interface{}, error) { r, err := c.GetWorkerHashrateChart(ctx, "0x01", "rig1"); return r, err },
+			wantData: chart,
+		},
+		{
+			name:     "WorkerShareRate01",
+			call:     func() (interface{}, error) { r, err := c.GetWorkerShareRate(ctx, "0x01", "rig1"); return r, err },
+			wantData: shareRate,
+		},
+		{
+			name:     "ApproximatedEarnings01",
+			call:     func() (interface{}, error) { r, err := c.GetApproximatedEarnings(ctx, 95.5); return r.Data.Day, err },
+			wantData: ApproximatedEarningsPeriod{Coins: 0.01, Dollars: 7.3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+}
+
+func Test_poolEndpoints(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	c := NewClient(WithAPIRoot("http://test.com/"), WithHTTPClient(mockClient))
+	ctx := context.Background()
+	topMiners := []PoolTopMinersData{{Number: 1, Address: "0x01", Hashrate: 95000.5}}
+	payments := []PoolPaymentsData{{Date: 1609459200, Address: "0x01", TXHash: "0xabc", Amount: mustParseAmount("0.1"), Confirmed: true}}
+	blockStats := []BlockStatsData{{Date: 1609459200, Difficulty: 3.5e15, BlockTime: 13.2}}
+	blocks := []BlocksData{{Number: 11565019, Hash: "0xdef", Date: 1609459200, Value: mustParseAmount("2.1"), Miner: "0x01", Status: 1}}
+	mockClient.On("Do", "http://test.com/pool/activeminers").Return(PoolCount{Status: true, Data: 12000}, nil)
+	mockClient.On("Do", "http://test.com/pool/activeworkers").Return(PoolCount{Status: true, Data: 40000}, nil)
+	mockClient.On("Do", "http://test.com/pool/hashrate").Return(PoolHashrate{Status: true, Data: 25000000.5}, nil)
+	mockClient.On("Do", "http://test.com/pool/topminers").Return(PoolTopMiners{Status: true, Data: topMiners}, nil)
+	mockClient.On("Do", "http://test.com/payments/0/10").Return(PoolPayments{Status: true, Data: payments}, nil)
+	mockClient.On("Do", "http://test.com/network/avgblocktime").Return(NetworkAvgBlockTime{Status: true, Data: 13.2}, nil)
+	mockClient.On("Do", "http://test.com/network/lastblocknumber").Return(NetworkLastBlockNumber{Status: true, Data: 11565019}, nil)
+	mockClient.On("Do", "http://test.com/block_stats/0/10").Return(BlockStats{Status: true, Data: blockStats}, nil)
+	mockClient.On("Do", "http://test.com/blocks/0/10").Return(Blocks{Status: true, Data: blocks}, nil)
+	mockClient.On("Do", "http://test.com/blocks/10/10").Return(ErrorResponse{Status: false, Error: "No data"}, nil)
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name:     "ActiveMiners01",
+			call:     func() (interface{}, error) { r, err := c.GetPoolActiveMiners(ctx); return r.Data, err },
+			wantData: int64(12000),
+		},
+		{
+			name:     "ActiveWorkers01",
+			call:     func() (interface{}, error) { r, err := c.GetPoolActiveWorkers(ctx); return r.Data, err },
+			wantData: int64(40000),
+		},
+		{
+			name:     "Hashrate01",
+			call:     func() (interface{}, error) { r, err := c.GetPoolHashrate(ctx); return r.Data, err },
+			wantData: Hashrate(25000000.5),
+		},
+		{
+			name:     "TopMiners01",
+			call:     func() (interface{}, error) { r, err := c.GetPoolTopMiners(ctx); return r.Data, err },
+			wantData: topMiners,
+		},
+		{
+			name:     "Payments01",
+			call:     func() (interface{}, error) { r, err := c.GetPoolPayments(ctx, 0, 10); return r.Data, err },
+			wantData: payments,
+		},
+		{
+			name:     "AvgBlockTime01",
+			call:     func() (interface{}, error) { r, err := c.GetNetworkAvgBlockTime(ctx); return r.Data, err },
+			wantData: 13.2,
+		},
+		{
+			name:     "LastBlockNumber01",
+			call:     func() (interface{}, error) { r, err := c.GetNetworkLastBlockNumber(ctx); return r.Data, err },
+			wantData: int64(11565019),
+		},
+		{
+			name:     "BlockStats01",
+			call:     func() (interface{}, error) { r, err := c.GetBlockStats(ctx, 0, 10); return r.Data, err },
+			wantData: blockStats,
+		},
+		{
+			name:     "Blocks01",
+			call:     func() (interface{}, error) { r, err := c.GetBlocks(ctx, 0, 10); return r.Data, err },
+			wantData: blocks,
+		},
+		{
+			name:     "Blocks02",
+			call:     func() (interface{}, error) { r, err := c.GetBlocks(ctx, 10, 10); return r.Data, err },
+			wantData: []BlocksData(nil),
+			wantErr:  ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+}
+
+func Test_ParseCoin(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		wantCoin Coin
+		wantErr  bool
+	}{
+		{name: "Default01", s: "", wantCoin: ETH},
+		{name: "Ticker01", s: "ETC", wantCoin: ETC},
+		{name: "Alias01", s: "erg", wantCoin: ERG},
+		{name: "Path01", s: "ergo", wantCoin: ERG},
+		{name: "Unsupported01", s: "btc", wantCoin: Coin("btc"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCoin, err := ParseCoin(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCoin(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+				return
+			}
+			if gotCoin != tt.wantCoin {
+				t.Errorf("ParseCoin(%q) = %v, want %v", tt.s, gotCoin, tt.wantCoin)
+			}
+		})
+	}
+}
+
+func Test_coinQuirks(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	mockClient.On("Do", "https://api.nanopool.org/v1/rvn/reportedhashrate/R01").Return(MinerHashrate{Status: true, Data: 30.5}, nil)
+	tests := []struct {
+		name    string
+		coin    Coin
+		address string
+		wantErr error
+	}{
+		{name: "Reported01", coin: RVN, address: "R01"},
+		{name: "Unsupported01", coin: XMR, address: "4A01", wantErr: ErrUnsupported},
+		{name: "Unsupported02", coin: ZEC, address: "t1", wantErr: ErrUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(WithCoin(tt.coin), WithHTTPClient(mockClient))
+			_, err := c.GetMinerReportedHashrate(context.Background(), tt.address)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetMinerReportedHashrate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_getRetries(t *testing.T) {
+	retry := throttle.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	tests := []struct {
+		name         string
+		statusCode   int
+		body         string
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name:         "ServerError01",
+			statusCode:   http.StatusServiceUnavailable,
+			body:         `<html>Service Unavailable</html>`,
+			wantErr:      ErrServer,
+			wantAttempts: 3,
+		},
+		{
+			name:         "AddressNotFound01",
+			statusCode:   http.StatusOK,
+			body:         `{"status":false,"error":"Address does not exist"}`,
+			wantErr:      ErrAddressNotFound,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(WithAPIRoot("http://test.com/"), WithHTTPClient(&cannedAPIClient{tt.statusCode, tt.body}),
+				WithRetryPolicy(retry), WithRateLimiter(throttle.NewLimiter(6000, 10)))
+			_, err := c.GetMinerBalance(context.Background(), "0x02")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Client.GetMinerBalance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			gotAttempts := 1
+			var retryErr *throttle.Error
+			if errors.As(err, &retryErr) {
+				gotAttempts = retryErr.Attempts
+			}
+			if gotAttempts != tt.wantAttempts {
+				t.Errorf("Client.GetMinerBalance() attempts = %v, want %v", gotAttempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func Test_getCache(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	mockClient.On("Do", "http://test.com/balance/0x01").Return(MinerBalance{Status: true, Data: mustParseAmount("0.142")}, nil).Once()
+	mockClient.On("Do", "http://test.com/prices/").Return(OtherPrices{Status: true, Data: OtherPricesData{PriceUSD: 730.5}}, nil).Once()
+	path := filepath.Join(t.TempDir(), "nanopool-cache.json")
+	cache := NewCache(time.Minute)
+	c := NewClient(WithAPIRoot("http://test.com/"), WithHTTPClient(mockClient), WithCache(cache))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		gotBalance, err := c.GetMinerBalance(ctx, "0x01")
+		if err != nil || gotBalance.Data != mustParseAmount("0.142") {
+			t.Fatalf("Client.GetMinerBalance() call %d = %v, %v, want %v", i, gotBalance.Data, err, 0.142)
+		}
+	}
+	if _, err := c.GetOtherPrices(ctx); err != nil {
+		t.Fatalf("Client.GetOtherPrices() error = %v", err)
+	}
+	if err := cache.Save(path); err != nil {
+		t.Fatalf("Cache.Save() error = %v", err)
+	}
+	loaded, err := LoadCache(path, time.Minute)
+	if err != nil {
+		t.Fatalf("LoadCache() error = %v", err)
+	}
+	c = NewClient(WithAPIRoot("http://test.com/"), WithHTTPClient(mockClient), WithCache(loaded))
+	gotPrices, err := c.GetOtherPrices(ctx)
+	if err != nil || gotPrices.Data.PriceUSD != 730.5 {
+		t.Errorf("Client.GetOtherPrices() from loaded cache = %v, %v, want %v", gotPrices.Data.PriceUSD, err, 730.5)
+	}
+	mockClient.AssertExpectations(t)
+	if _, ok := NewCache(0).Get("http://test.com/prices/"); ok {
+		t.Errorf("Cache.Get() on an empty cache found an entry")
+	}
+	if _, err := LoadCache(filepath.Join(t.TempDir(), "missing.json"), time.Minute); err != nil {
+		t.Errorf("LoadCache() of a missing file error = %v, want nil", err)
+	}
+}
+
+func Test_AmountUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantAmount Amount
+		wantString string
+		wantErr    bool
+	}{
+		{name: "String01", data: `"0.142"`, wantAmount: 142000000000, wantString: "0.142"},
+		{name: "Number01", data: `0.142`, wantAmount: 142000000000, wantString: "0.142"},
+		{name: "Number02", data: `3`, wantAmount: 3000000000000, wantString: "3"},
+		{name: "Exponent01", data: `1e-7`, wantAmount: 100000, wantString: "0.0000001"},
+		{name: "Negative01", data: `"-0.5"`, wantAmount: -500000000000, wantString: "-0.5"},
+		{name: "Rounding01", data: `0.0000000000005`, wantAmount: 1, wantString: "0.000000000001"},
+		{name: "Precise01", data: `"12.345678901234"`, wantAmount: 12345678901234, wantString: "12.345678901234"},
+		{name: "Empty01", data: `""`, wantAmount: 0, wantString: "0"},
+		{name: "Null01", data: `null`, wantAmount: 0, wantString: "0"},
+		{name: "Invalid01", data: `"lots"`, wantErr: true},
+		{name: "Range01", data: `"99999999999"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAmount Amount
+			err := json.Unmarshal([]byte(tt.data), &gotAmount)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Amount.UnmarshalJSON(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotAmount != tt.wantAmount || gotAmount.String() != tt.wantString {
+				t.Errorf("Amount.UnmarshalJSON(%s) = %d (%s), want %d (%s)", tt.data, gotAmount, gotAmount, tt.wantAmount, tt.wantString)
+			}
+		})
+	}
+}
+
+func Test_AmountAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       Amount
+		b       Amount
+		want    Amount
+		wantErr error
+	}{
+		{name: "Success01", a: 142000000000, b: 58000000000, want: 200000000000},
+		{name: "Negative01", a: 142000000000, b: -500000000000, want: -358000000000},
+		{name: "Overflow01", a: math.MaxInt64 - 1, b: 2, wantErr: ErrAmountOverflow},
+		{name: "Underflow01", a: math.MinInt64 + 1, b: -2, wantErr: ErrAmountOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("Amount.Add() = %d, %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_HashrateUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantHashrate Hashrate
+		wantErr      bool
+	}{
+		{name: "String01", data: `"95.5"`, wantHashrate: 95.5},
+		{name: "Number01", data: `95.5`, wantHashrate: 95.5},
+		{name: "Empty01", data: `""`, wantHashrate: 0},
+		{name: "Null01", data: `null`, wantHashrate: 0},
+		{name: "Invalid01", data: `"fast"`, wantErr: true},
+		{name: "Invalid02", data: `true`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotHashrate Hashrate
+			err := json.Unmarshal([]byte(tt.data), &gotHashrate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Hashrate.UnmarshalJSON(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+				return
+			}
+			if gotHashrate != tt.wantHashrate {
+				t.Errorf("Hashrate.UnmarshalJSON(%s) = %v, want %v", tt.data, gotHashrate, tt.wantHashrate)
+			}
+		})
+	}
+	if got := Hashrate(95.5).HashesPerSecond(MegaHashesPerSecond); got != 95500000 {
+		t.Errorf("Hashrate.HashesPerSecond(MegaHashesPerSecond) = %v, want %v", got, 95500000)
+	}
+	if got := Hashrate(95.5).Format(ETH.Info().HashrateUnit); got != "95.5 MH/s" {
+		t.Errorf("Hashrate.Format(MegaHashesPerSecond) = %v, want %v", got, "95.5 MH/s")
+	}
+}
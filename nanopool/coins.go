@@ -0,0 +1,92 @@
+package nanopool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Coin identifies one of the pools nanopool runs, its value is the path segment used in the API root
+type Coin string
+
+// Coins supported by nanopool
+const (
+	ETH Coin = "eth"
+	ETC Coin = "etc"
+	RVN Coin = "rvn"
+	ERG Coin = "ergo"
+	XMR Coin = "xmr"
+	ZEC Coin = "zec"
+	CFX Coin = "cfx"
+)
+
+// HashrateUnit is the unit nanopool reports hashrates in for a coin
+type HashrateUnit struct {
+	Symbol          string
+	HashesPerSecond float64
+}
+
+// Hashrate units used by the nanopool API
+var (
+	HashesPerSecond     = HashrateUnit{Symbol: "H/s", HashesPerSecond: 1}
+	MegaHashesPerSecond = HashrateUnit{Symbol: "MH/s", HashesPerSecond: 1e6}
+	SolutionsPerSecond  = HashrateUnit{Symbol: "Sol/s", HashesPerSecond: 1}
+)
+
+func (u HashrateUnit) String() string {
+	return u.Symbol
+}
+
+// CoinInfo describes how the nanopool API for a coin differs from the others
+type CoinInfo struct {
+	Name string
+	// Decimals is the number of decimal places of the coin's smallest unit, e.g. 18 for wei
+	Decimals int
+	// HashrateUnit is the unit of every hashrate returned for the coin
+	HashrateUnit HashrateUnit
+	// ReportedHashrate is false for coins whose miners do not report hashrate to the pool, the reportedhashrate
+	// endpoints are not served for them
+	ReportedHashrate bool
+}
+
+var coins = map[Coin]CoinInfo{
+	ETH: {Name: "Ethereum", Decimals: 18, HashrateUnit: MegaHashesPerSecond, ReportedHashrate: true},
+	ETC: {Name: "Ethereum Classic", Decimals: 18, HashrateUnit: MegaHashesPerSecond, ReportedHashrate: true},
+	RVN: {Name: "Ravencoin", Decimals: 8, HashrateUnit: MegaHashesPerSecond, ReportedHashrate: true},
+	ERG: {Name: "Ergo", Decimals: 9, HashrateUnit: MegaHashesPerSecond, ReportedHashrate: true},
+	XMR: {Name: "Monero", Decimals: 12, HashrateUnit: HashesPerSecond, ReportedHashrate: false},
+	ZEC: {Name: "Zcash", Decimals: 8, HashrateUnit: SolutionsPerSecond, ReportedHashrate: false},
+	CFX: {Name: "Conflux", Decimals: 18, HashrateUnit: MegaHashesPerSecond, ReportedHashrate: true},
+}
+
+// Coins returns every coin supported by the nanopool API
+func Coins() []Coin {
+	return []Coin{ETH, ETC, RVN, ERG, XMR, ZEC, CFX}
+}
+
+// ParseCoin maps a ticker or API path segment such as "ETH", "erg" or "ergo" to a Coin, an empty string maps to DefaultCoin
+func ParseCoin(s string) (coin Coin, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "":
+		return DefaultCoin, nil
+	case "erg":
+		return ERG, nil
+	}
+	coin = Coin(s)
+	if _, ok := coins[coin]; !ok {
+		err = fmt.Errorf("unsupported nanopool coin %q, expected one of %v", s, Coins())
+	}
+	return
+}
+
+// Info returns the quirks of the coin, unknown coins are treated like ETH
+func (c Coin) Info() CoinInfo {
+	if info, ok := coins[c]; ok {
+		return info
+	}
+	return coins[ETH]
+}
+
+func (c Coin) String() string {
+	return string(c)
+}
@@ -0,0 +1,97 @@
+package nanopool
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"mining-tools/pool"
+)
+
+var (
+	// ErrAddressNotFound is returned when nanopool does not know the requested account
+	ErrAddressNotFound = errors.New("address not found")
+	// ErrRateLimited is returned when nanopool rejected the request for exceeding its request budget
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer is returned when nanopool answered with a 5xx status
+	ErrServer = errors.New("server error")
+	// ErrMalformedResponse is returned when the response body could not be decoded
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrRequestFailed is returned for any other non-200 status or status:false response
+	ErrRequestFailed = errors.New("request failed")
+	// ErrUnsupported is returned without calling the API when the endpoint is not served for the Client's coin
+	ErrUnsupported = errors.New("unsupported for coin")
+)
+
+// APIError describes a failed call to a nanopool endpoint, Err is one of the Err* sentinels above so
+// callers can branch with errors.Is
+type APIError struct {
+	Endpoint   string
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("nanopool %s: %s (HTTP %d)", e.Endpoint, e.Err, e.StatusCode)
+	}
+	return fmt.Sprintf("nanopool %s: %s (HTTP %d): %s", e.Endpoint, e.Err, e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error describing the kind of failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets callers match the pool package sentinels, so code written against pool.Pool can branch on nanopool failures
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case pool.ErrAccountNotFound:
+		return e.Err == ErrAddressNotFound
+	case pool.ErrRateLimited:
+		return e.Err == ErrRateLimited
+	}
+	return false
+}
+
+// checkResponse turns an HTTP status and a decoded {status,error} envelope in to an *APIError, or nil if the call succeeded.
+// A failed status is classified before the body, as error pages of proxies in front of nanopool are rarely JSON
+func checkResponse(endpoint string, statusCode int, envelope *ErrorResponse) (err error) {
+	apiErr := &APIError{
+		Endpoint:   endpoint,
+		StatusCode: statusCode,
+	}
+	if envelope != nil {
+		apiErr.Message = envelope.Error
+	}
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		apiErr.Err = ErrRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		apiErr.Err = ErrServer
+	case envelope == nil && (statusCode < 200 || statusCode >= 300):
+		apiErr.Err = classifyMessage(statusCode, "")
+	case envelope == nil:
+		apiErr.Err = ErrMalformedResponse
+	case statusCode < 200 || statusCode >= 300 || !envelope.Status:
+		apiErr.Err = classifyMessage(statusCode, envelope.Error)
+	default:
+		return nil
+	}
+	return apiErr
+}
+
+func classifyMessage(statusCode int, message string) error {
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "does not exist") || strings.Contains(message, "not found"):
+		return ErrAddressNotFound
+	case strings.Contains(message, "too many") || strings.Contains(message, "rate limit"):
+		return ErrRateLimited
+	case statusCode == http.StatusNotFound:
+		return ErrAddressNotFound
+	}
+	return ErrRequestFailed
+}
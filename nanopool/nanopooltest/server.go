@@ -0,0 +1,511 @@
+// Package nanopooltest provides a fake nanopool API for development and end to end tests, serving configurable data,
+// error responses, slow responses and share rate gaps for every endpoint wrapped by the nanopool package
+package nanopooltest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mining-tools/nanopool"
+)
+
+// Account is the data served for one miner address
+type Account struct {
+	Balance            nanopool.Amount
+	UnconfirmedBalance nanopool.Amount
+	// Hashrate is the current hashrate, the averages served are derived from it
+	Hashrate         nanopool.Hashrate
+	ReportedHashrate nanopool.Hashrate
+	Workers          []nanopool.MinerGeneralInfoWorker
+	Payments         []nanopool.MinerPaymentsData
+	// PayoutThreshold is the balance at which the account is paid out
+	PayoutThreshold nanopool.Amount
+	// SharesPerSlot is the number of shares in each 10 minute slot of the share rate history
+	SharesPerSlot int64
+	// ShareRateHours is how far back the share rate history goes
+	ShareRateHours int64
+	// ShareRateGaps lists the 10 minute slots missing from the share rate history, 0 being the current slot
+	ShareRateGaps []int64
+}
+
+// Config is the data served by a Server, along with the failures it injects
+type Config struct {
+	Accounts map[string]*Account
+	Prices   nanopool.OtherPricesData
+	// CoinsPerHashrateDay is what one unit of hashrate earns per day, used for approximated earnings
+	CoinsPerHashrateDay float64
+	PoolHashrate        nanopool.Hashrate
+	ActiveMiners        int64
+	ActiveWorkers       int64
+	AvgBlockTime        float64
+	LastBlockNumber     int64
+	// Errors maps an endpoint such as "balance/0x01" to a message served as {"status":false,"error":message}
+	Errors map[string]string
+	// StatusCodes maps an endpoint such as "prices/" to an HTTP status served instead of data
+	StatusCodes map[string]int
+	// Delay is applied to every response, Delays to the response of a single endpoint
+	Delay  time.Duration
+	Delays map[string]time.Duration
+}
+
+// DefaultAddress is the miner address DefaultConfig serves data for
+const DefaultAddress = "0x0000000000000000000000000000000000000001"
+
+// DefaultConfig returns realistic data for DefaultAddress, a two rig ETH miner with a few payments
+func DefaultConfig() Config {
+	now := time.Now().UTC().Truncate(time.Hour).Unix()
+	return Config{
+		Accounts: map[string]*Account{
+			DefaultAddress: {
+				Balance:            142000000000, // 0.142
+				UnconfirmedBalance: 1500000000,   // 0.0015
+				Hashrate:           190.5,
+				ReportedHashrate:   200.4,
+				Workers: []nanopool.MinerGeneralInfoWorker{
+					{ID: "rig1", UID: 1, Hashrate: 95.5, Lastshare: now, Rating: 120000},
+					{ID: "rig2", UID: 2, Hashrate: 95, Lastshare: now, Rating: 118000},
+				},
+				Payments: []nanopool.MinerPaymentsData{
+					{Date: now - 86400, TXHash: "0x02", Amount: 100000000000, Confirmed: true},
+					{Date: now - 8*86400, TXHash: "0x01", Amount: 100000000000, Confirmed: true},
+				},
+				PayoutThreshold: 200000000000, // 0.2
+				SharesPerSlot:   12,
+				ShareRateHours:  24,
+			},
+		},
+		Prices: nanopool.OtherPricesData{
+			PriceUSD: 730.51,
+			PriceEUR: 599.37,
+			PriceRUR: 54210.12,
+			PriceCNY: 4771.08,
+			PriceBTC: 0.0251,
+		},
+		CoinsPerHashrateDay: 0.00009,
+		PoolHashrate:        25000000.5,
+		ActiveMiners:        12000,
+		ActiveWorkers:       40000,
+		AvgBlockTime:        13.2,
+		LastBlockNumber:     11565019,
+	}
+}
+
+// Server is a fake nanopool API served over a local httptest.Server
+type Server struct {
+	*httptest.Server
+	handler *Handler
+}
+
+// NewServer starts a Server serving config, callers should Close it when done
+func NewServer(config Config) *Server {
+	handler := NewHandler(config)
+	return &Server{
+		Server:  httptest.NewServer(handler),
+		handler: handler,
+	}
+}
+
+// APIRoot returns the root to give nanopool.WithAPIRoot for coin
+func (s *Server) APIRoot(coin nanopool.Coin) string {
+	return fmt.Sprintf("%s/v1/%s/", s.URL, coin)
+}
+
+// BaseURL returns the root to give nanopool.WithBaseURL
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1/"
+}
+
+// Update changes the data and failures served from now on
+func (s *Server) Update(update func(config *Config)) {
+	s.handler.Update(update)
+}
+
+// Handler serves the fake nanopool API under /v1/:coin/, it can be mounted on any http.Server
+type Handler struct {
+	mu     sync.Mutex
+	config Config
+}
+
+// NewHandler returns a Handler serving config
+func NewHandler(config Config) *Handler {
+	return &Handler{config: config}
+}
+
+// Update changes the data and failures served from now on
+func (h *Handler) Update(update func(config *Config)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	update(&h.config)
+}
+
+type response struct {
+	Status bool        `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+var errAddressNotFound = errors.New("Address does not exist")
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "v1" {
+		http.NotFound(w, r)
+		return
+	}
+	args := parts[2:]
+	endpoint := strings.Join(args, "/")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if delay := h.config.Delay + h.config.Delays[endpoint]; delay > 0 {
+		// Release the lock while sleeping so other requests and Update are not held up
+		h.mu.Unlock()
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		}
+		h.mu.Lock()
+	}
+	if statusCode, ok := h.config.StatusCodes[endpoint]; ok {
+		http.Error(w, http.StatusText(statusCode), statusCode)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if message, ok := h.config.Errors[endpoint]; ok {
+		json.NewEncoder(w).Encode(response{Status: false, Error: message})
+		return
+	}
+	data, err := h.route(args)
+	if err != nil {
+		json.NewEncoder(w).Encode(response{Status: false, Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(response{Status: true, Data: data})
+}
+
+func (h *Handler) route(args []string) (data interface{}, err error) {
+	switch args[0] {
+	case "prices":
+		return h.config.Prices, nil
+	case "pool":
+		return h.routePool(args)
+	case "network":
+		return h.routeNetwork(args)
+	case "approximated_earnings":
+		return h.approximatedEarnings(args)
+	case "block_stats":
+		return h.blockStats(args)
+	case "blocks":
+		return h.blocks(args)
+	case "payments":
+		if len(args) == 3 {
+			return h.poolPayments(args)
+		}
+	}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("Unknown method %s", args[0])
+	}
+	account, ok := h.config.Accounts[args[1]]
+	if !ok {
+		return nil, errAddressNotFound
+	}
+	if len(args) == 3 && args[0] != "avghashratelimited" {
+		return h.routeWorker(account, args)
+	}
+	return h.routeMiner(account, args)
+}
+
+func (h *Handler) routeMiner(account *Account, args []string) (data interface{}, err error) {
+	switch args[0] {
+	case "user":
+		return nanopool.MinerGeneralInfoData{
+			Account:            args[1],
+			UnconfirmedBalance: account.UnconfirmedBalance,
+			Balance:            account.Balance,
+			Hashrate:           account.Hashrate,
+			AvgHashrate:        avgHashrate(account.Hashrate),
+			Workers:            workersWithAverages(account.Workers),
+		}, nil
+	case "accountexist":
+		return "Account exists", nil
+	case "balance":
+		return account.Balance, nil
+	case "hashrate", "avghashratelimited":
+		return account.Hashrate, nil
+	case "reportedhashrate":
+		return account.ReportedHashrate, nil
+	case "avghashrate":
+		return avgHashrate(account.Hashrate), nil
+	case "balance_hashrate":
+		return nanopool.MinerBalanceHashrateData{Hashrate: account.Hashrate, Balance: account.Balance}, nil
+	case "payments":
+		return account.Payments, nil
+	case "paymentsday":
+		return paymentsDay(account.Payments), nil
+	case "usersettings":
+		return nanopool.UserSettingsData{Payout: account.PayoutThreshold}, nil
+	case "workers":
+		return account.Workers, nil
+	case "reportedhashrates":
+		reported := []nanopool.MinerReportedHashrateData{}
+		for _, worker := range account.Workers {
+			reported = append(reported, nanopool.MinerReportedHashrateData{
+				Worker:   worker.ID,
+				Hashrate: reportedShare(account, worker.Hashrate),
+			})
+		}
+		return reported, nil
+	case "shareratehistory":
+		return shareRate(account, account.SharesPerSlot), nil
+	case "hashratechart":
+		return hashrateChart(account, account.SharesPerSlot, account.Hashrate), nil
+	case "history":
+		return history(account.Hashrate), nil
+	}
+	return nil, fmt.Errorf("Unknown method %s", args[0])
+}
+
+func (h *Handler) routeWorker(account *Account, args []string) (data interface{}, err error) {
+	var worker *nanopool.MinerGeneralInfoWorker
+	for i := range account.Workers {
+		if account.Workers[i].ID == args[2] {
+			worker = &account.Workers[i]
+		}
+	}
+	if worker == nil {
+		return nil, errors.New("Worker not found")
+	}
+	shares := account.SharesPerSlot / int64(len(account.Workers))
+	switch args[0] {
+	case "hashrate":
+		return worker.Hashrate, nil
+	case "reportedhashrate":
+		return reportedShare(account, worker.Hashrate), nil
+	case "history":
+		return history(worker.Hashrate), nil
+	case "hashratechart":
+		return hashrateChart(account, shares, worker.Hashrate), nil
+	case "shareratehistory":
+		return shareRate(account, shares), nil
+	}
+	return nil, fmt.Errorf("Unknown method %s", args[0])
+}
+
+func (h *Handler) routePool(args []string) (data interface{}, err error) {
+	if len(args) != 2 {
+		return nil, errors.New("Unknown pool method")
+	}
+	switch args[1] {
+	case "activeminers":
+		return h.config.ActiveMiners, nil
+	case "activeworkers":
+		return h.config.ActiveWorkers, nil
+	case "hashrate":
+		return h.config.PoolHashrate, nil
+	case "topminers":
+		topMiners := []nanopool.PoolTopMinersData{}
+		for address, account := range h.config.Accounts {
+			topMiners = append(topMiners, nanopool.PoolTopMinersData{Address: address, Hashrate: account.Hashrate})
+		}
+		sort.Slice(topMiners, func(i, j int) bool { return topMiners[i].Hashrate > topMiners[j].Hashrate })
+		for i := range topMiners {
+			topMiners[i].Number = int64(i + 1)
+		}
+		return topMiners, nil
+	}
+	return nil, errors.New("Unknown pool method")
+}
+
+func (h *Handler) routeNetwork(args []string) (data interface{}, err error) {
+	if len(args) == 2 && args[1] == "avgblocktime" {
+		return h.config.AvgBlockTime, nil
+	}
+	if len(args) == 2 && args[1] == "lastblocknumber" {
+		return h.config.LastBlockNumber, nil
+	}
+	return nil, errors.New("Unknown network method")
+}
+
+func (h *Handler) approximatedEarnings(args []string) (data interface{}, err error) {
+	if len(args) != 2 {
+		return nil, errors.New("Hashrate required")
+	}
+	hashrate, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return nil, errors.New("Invalid hashrate")
+	}
+	period := func(days float64) nanopool.ApproximatedEarningsPeriod {
+		coins := hashrate * h.config.CoinsPerHashrateDay * days
+		return nanopool.ApproximatedEarningsPeriod{
+			Coins:    coins,
+			Dollars:  coins * h.config.Prices.PriceUSD,
+			Yuan:     coins * h.config.Prices.PriceCNY,
+			Euros:    coins * h.config.Prices.PriceEUR,
+			Rubles:   coins * h.config.Prices.PriceRUR,
+			Bitcoins: coins * h.config.Prices.PriceBTC,
+		}
+	}
+	return nanopool.ApproximatedEarningsData{
+		Minute: period(1.0 / 1440),
+		Hour:   period(1.0 / 24),
+		Day:    period(1),
+		Week:   period(7),
+		Month:  period(30),
+	}, nil
+}
+
+func (h *Handler) blockStats(args []string) (data interface{}, err error) {
+	offset, count, err := offsetCount(args)
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC().Unix()
+	stats := []nanopool.BlockStatsData{}
+	for i := offset; i < offset+count; i++ {
+		stats = append(stats, nanopool.BlockStatsData{
+			Date:       now - int64(float64(i)*h.config.AvgBlockTime),
+			Difficulty: 3.5e15,
+			BlockTime:  h.config.AvgBlockTime,
+		})
+	}
+	return stats, nil
+}
+
+func (h *Handler) blocks(args []string) (data interface{}, err error) {
+	offset, count, err := offsetCount(args)
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC().Unix()
+	blocks := []nanopool.BlocksData{}
+	for i := offset; i < offset+count; i++ {
+		number := h.config.LastBlockNumber - i*50
+		blocks = append(blocks, nanopool.BlocksData{
+			Number: number,
+			Hash:   fmt.Sprintf("0x%064x", number),
+			Date:   now - i*50*int64(h.config.AvgBlockTime),
+			Value:  2000000000000, // 2
+			Miner:  DefaultAddress,
+			Status: 1,
+		})
+	}
+	return blocks, nil
+}
+
+func (h *Handler) poolPayments(args []string) (data interface{}, err error) {
+	offset, count, err := offsetCount(args)
+	if err != nil {
+		return
+	}
+	payments := []nanopool.PoolPaymentsData{}
+	for address, account := range h.config.Accounts {
+		for _, p := range account.Payments {
+			payments = append(payments, nanopool.PoolPaymentsData{
+				Date:      p.Date,
+				Address:   address,
+				TXHash:    p.TXHash,
+				Amount:    p.Amount,
+				Confirmed: p.Confirmed,
+			})
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].Date > payments[j].Date })
+	if offset > int64(len(payments)) {
+		offset = int64(len(payments))
+	}
+	if offset+count > int64(len(payments)) {
+		count = int64(len(payments)) - offset
+	}
+	return payments[offset : offset+count], nil
+}
+
+func offsetCount(args []string) (offset int64, count int64, err error) {
+	if len(args) != 3 {
+		return 0, 0, errors.New("Offset and count required")
+	}
+	offset, err = strconv.ParseInt(args[1], 10, 64)
+	if err != nil || offset < 0 {
+		return 0, 0, errors.New("Invalid offset")
+	}
+	count, err = strconv.ParseInt(args[2], 10, 64)
+	if err != nil || count < 0 {
+		return 0, 0, errors.New("Invalid count")
+	}
+	return
+}
+
+// shareRate builds a history ending at the current 10 minute slot, newest first like nanopool, leaving out the gaps
+func shareRate(account *Account, shares int64) []nanopool.MinerShareRateData {
+	history := []nanopool.MinerShareRateData{}
+	gaps := make(map[int64]bool, len(account.ShareRateGaps))
+	for _, gap := range account.ShareRateGaps {
+		gaps[gap] = true
+	}
+	now := time.Now().UTC().Truncate(10 * time.Minute).Unix()
+	for slot := int64(0); slot < account.ShareRateHours*6; slot++ {
+		if gaps[slot] {
+			continue
+		}
+		history = append(history, nanopool.MinerShareRateData{Date: now - slot*600, Shares: shares})
+	}
+	return history
+}
+
+func hashrateChart(account *Account, shares int64, hashrate nanopool.Hashrate) []nanopool.MinerHashrateChartData {
+	chart := []nanopool.MinerHashrateChartData{}
+	for _, sr := range shareRate(account, shares) {
+		chart = append(chart, nanopool.MinerHashrateChartData{Date: sr.Date, Shares: sr.Shares, Hashrate: hashrate})
+	}
+	return chart
+}
+
+func history(hashrate nanopool.Hashrate) []nanopool.MinerHistoryData {
+	history := []nanopool.MinerHistoryData{}
+	now := time.Now().UTC().Truncate(time.Hour).Unix()
+	for hour := int64(0); hour < 24; hour++ {
+		history = append(history, nanopool.MinerHistoryData{Date: now - hour*3600, Hashrate: hashrate})
+	}
+	return history
+}
+
+func avgHashrate(hashrate nanopool.Hashrate) nanopool.MinerAvgHashrate {
+	return nanopool.MinerAvgHashrate{H1: hashrate, H3: hashrate, H6: hashrate, H12: hashrate, H24: hashrate}
+}
+
+func workersWithAverages(workers []nanopool.MinerGeneralInfoWorker) []nanopool.MinerGeneralInfoWorker {
+	withAverages := make([]nanopool.MinerGeneralInfoWorker, len(workers))
+	for i, worker := range workers {
+		average := avgHashrate(worker.Hashrate)
+		worker.H1, worker.H3, worker.H6, worker.H12, worker.H24 = average.H1, average.H3, average.H6, average.H12, average.H24
+		withAverages[i] = worker
+	}
+	return withAverages
+}
+
+// reportedShare scales a worker's hashrate by how much the account reports over its effective hashrate
+func reportedShare(account *Account, hashrate nanopool.Hashrate) nanopool.Hashrate {
+	if account.Hashrate == 0 {
+		return hashrate
+	}
+	return hashrate * account.ReportedHashrate / account.Hashrate
+}
+
+func paymentsDay(payments []nanopool.MinerPaymentsData) []nanopool.MinerPaymentsDayData {
+	byDay := make(map[int64]nanopool.Amount)
+	for _, p := range payments {
+		byDay[p.Date-p.Date%86400] += p.Amount
+	}
+	days := []nanopool.MinerPaymentsDayData{}
+	for date, amount := range byDay {
+		days = append(days, nanopool.MinerPaymentsDayData{Date: date, Amount: amount})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date > days[j].Date })
+	return days
+}
@@ -0,0 +1,130 @@
+package nanopooltest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"mining-tools/nanopool"
+)
+
+func Test_ServerEndpoints(t *testing.T) {
+	server := NewServer(DefaultConfig())
+	defer server.Close()
+	c := nanopool.NewClient(nanopool.WithBaseURL(server.BaseURL()))
+	ctx := context.Background()
+	a := DefaultAddress
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "GeneralInfo", call: func() error { _, err := c.GetMinerGeneralInfo(ctx, a); return err }},
+		{name: "Payments", call: func() error { _, err := c.GetMinerPayments(ctx, a); return err }},
+		{name: "ShareRate", call: func() error { _, err := c.GetMinerShareRate(ctx, a); return err }},
+		{name: "Balance", call: func() error { _, err := c.GetMinerBalance(ctx, a); return err }},
+		{name: "Prices", call: func() error { _, err := c.GetOtherPrices(ctx); return err }},
+		{name: "AccountExist", call: func() error { _, err := c.GetMinerAccountExist(ctx, a); return err }},
+		{name: "Hashrate", call: func() error { _, err := c.GetMinerHashrate(ctx, a); return err }},
+		{name: "HashrateChart", call: func() error { _, err := c.GetMinerHashrateChart(ctx, a); return err }},
+		{name: "History", call: func() error { _, err := c.GetMinerHistory(ctx, a); return err }},
+		{name: "BalanceHashrate", call: func() error { _, err := c.GetMinerBalanceHashrate(ctx, a); return err }},
+		{name: "AvgHashrate", call: func() error { _, err := c.GetMinerAvgHashrate(ctx, a); return err }},
+		{name: "AvgHashrateLimited", call: func() error { _, err := c.GetMinerAvgHashrateLimited(ctx, a, 6); return err }},
+		{name: "ReportedHashrate", call: func() error { _, err := c.GetMinerReportedHashrate(ctx, a); return err }},
+		{name: "ReportedHashrates", call: func() error { _, err := c.GetMinerReportedHashrates(ctx, a); return err }},
+		{name: "Workers", call: func() error { _, err := c.GetMinerWorkers(ctx, a); return err }},
+		{name: "PaymentsDay", call: func() error { _, err := c.GetMinerPaymentsDay(ctx, a); return err }},
+		{name: "UserSettings", call: func() error { _, err := c.GetUserSettings(ctx, a); return err }},
+		{name: "WorkerHashrate", call: func() error { _, err := c.GetWorkerHashrate(ctx, a, "rig1"); return err }},
+		{name: "WorkerReportedHashrate", call: func() error { _, err := c.GetWorkerReportedHashrate(ctx, a, "rig1"); return err }},
+		{name: "WorkerHistory", call: func() error { _, err := c.GetWorkerHistory(ctx, a, "rig1"); return err }},
+		{name: "WorkerHashrateChart", call: func() error { _, err := c.GetWorkerHashrateChart(ctx, a, "rig1"); return err }},
+		{name: "WorkerShareRate", call: func() error { _, err := c.GetWorkerShareRate(ctx, a, "rig1"); return err }},
+		{name: "ApproximatedEarnings", call: func() error { _, err := c.GetApproximatedEarnings(ctx, 190.5); return err }},
+		{name: "PoolActiveMiners", call: func() error { _, err := c.GetPoolActiveMiners(ctx); return err }},
+		{name: "PoolActiveWorkers", call: func() error { _, err := c.GetPoolActiveWorkers(ctx); return err }},
+		{name: "PoolHashrate", call: func() error { _, err := c.GetPoolHashrate(ctx); return err }},
+		{name: "PoolTopMiners", call: func() error { _, err := c.GetPoolTopMiners(ctx); return err }},
+		{name: "PoolPayments", call: func() error { _, err := c.GetPoolPayments(ctx, 0, 10); return err }},
+		{name: "NetworkAvgBlockTime", call: func() error { _, err := c.GetNetworkAvgBlockTime(ctx); return err }},
+		{name: "NetworkLastBlockNumber", call: func() error { _, err := c.GetNetworkLastBlockNumber(ctx); return err }},
+		{name: "BlockStats", call: func() error { _, err := c.GetBlockStats(ctx, 0, 10); return err }},
+		{name: "Blocks", call: func() error { _, err := c.GetBlocks(ctx, 0, 10); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Errorf("%s error = %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func Test_ServerData(t *testing.T) {
+	config := DefaultConfig()
+	config.Accounts[DefaultAddress].ShareRateGaps = []int64{0, 5}
+	server := NewServer(config)
+	defer server.Close()
+	c := nanopool.NewClient(nanopool.WithAPIRoot(server.APIRoot(nanopool.ETH)))
+	ctx := context.Background()
+	info, err := c.GetMinerGeneralInfo(ctx, DefaultAddress)
+	if err != nil {
+		t.Fatalf("Client.GetMinerGeneralInfo() error = %v", err)
+	}
+	if info.Data.Balance.String() != "0.142" || len(info.Data.Workers) != 2 || info.Data.Workers[0].H24 != 95.5 {
+		t.Errorf("Client.GetMinerGeneralInfo() = %+v, want the DefaultConfig account", info.Data)
+	}
+	shareRate, err := c.GetMinerShareRate(ctx, DefaultAddress)
+	if err != nil {
+		t.Fatalf("Client.GetMinerShareRate() error = %v", err)
+	}
+	current := time.Now().UTC().Truncate(10 * time.Minute).Unix()
+	if len(shareRate.Data) != 24*6-2 || shareRate.Data[0].Date != current-600 {
+		t.Errorf("Client.GetMinerShareRate() returned %d slots starting at %d, want %d slots starting at %d",
+			len(shareRate.Data), shareRate.Data[0].Date, 24*6-2, current-600)
+	}
+}
+
+func Test_ServerFailures(t *testing.T) {
+	server := NewServer(DefaultConfig())
+	defer server.Close()
+	server.Update(func(config *Config) {
+		config.Errors = map[string]string{"balance/" + DefaultAddress: "Too many requests"}
+		config.StatusCodes = map[string]int{"prices/": http.StatusBadGateway}
+		config.Delays = map[string]time.Duration{"pool/hashrate": 200 * time.Millisecond}
+	})
+	c := nanopool.NewClient(nanopool.WithBaseURL(server.BaseURL()), nanopool.WithTimeout(50*time.Millisecond))
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "UnknownAddress", call: func() error { _, err := c.GetMinerBalance(ctx, "0x02"); return err }, wantErr: nanopool.ErrAddressNotFound},
+		{name: "UnknownWorker", call: func() error { _, err := c.GetWorkerHashrate(ctx, DefaultAddress, "rig9"); return err }, wantErr: nanopool.ErrAddressNotFound},
+		{name: "ErrorMessage", call: func() error { _, err := c.GetMinerBalance(ctx, DefaultAddress); return err }, wantErr: nanopool.ErrRateLimited},
+		{name: "StatusCode", call: func() error { _, err := c.GetOtherPrices(ctx); return err }, wantErr: nanopool.ErrServer},
+		{name: "Slow", call: func() error { _, err := c.GetPoolHashrate(ctx); return err }, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ServerOffsetCount(t *testing.T) {
+	server := NewServer(DefaultConfig())
+	defer server.Close()
+	c := nanopool.NewClient(nanopool.WithBaseURL(server.BaseURL()))
+	ctx := context.Background()
+	for _, tt := range []struct{ offset, count int64 }{{-1, 10}, {0, -1}, {1, -5}} {
+		if _, err := c.GetPoolPayments(ctx, tt.offset, tt.count); !errors.Is(err, nanopool.ErrRequestFailed) {
+			t.Errorf("Client.GetPoolPayments(%d, %d) error = %v, want %v", tt.offset, tt.count, err, nanopool.ErrRequestFailed)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package nanopool
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// AmountDecimals is the fixed precision of Amount, enough for the balances of every coin nanopool runs
+const AmountDecimals = 12
+
+var amountScale = new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(AmountDecimals), nil))
+
+// Amount is a coin amount held as an integer number of 10^-12 coins, so sums of balances and payments are exact.
+// It decodes from both the string and number encodings nanopool uses and encodes as a json number. Amounts hold up to
+// about 9.2 million coins, sum them with Add rather than + so an overflow is reported
+type Amount int64
+
+// ErrAmountOverflow is returned when a sum of amounts does not fit an Amount
+var ErrAmountOverflow = errors.New("amount overflow")
+
+// ParseAmount parses a decimal such as "0.142", "12" or "1e-7" in to an Amount, rounding below 10^-12
+func ParseAmount(s string) (amount Amount, err error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	r.Mul(r, amountScale)
+	// Round half away from zero
+	num, den := r.Num(), r.Denom()
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int))
+	if m.Abs(m).Mul(m, big.NewInt(2)).Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("amount %q out of range", s)
+	}
+	return Amount(q.Int64()), nil
+}
+
+// Add returns a + b, or ErrAmountOverflow if the sum does not fit an Amount
+func (a Amount) Add(b Amount) (sum Amount, err error) {
+	sum = a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, fmt.Errorf("%w: %s + %s", ErrAmountOverflow, a, b)
+	}
+	return
+}
+
+// Float64 returns the amount in whole coins as a float64, for math that tolerates rounding
+func (a Amount) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(big.NewInt(int64(a)), amountScale.Num()).Float64()
+	return f
+}
+
+// Rat returns the amount in whole coins as an exact big.Rat
+func (a Amount) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(int64(a)), amountScale.Num())
+}
+
+// String returns the amount in whole coins without trailing zeros, e.g. 0.142 or 3
+func (a Amount) String() string {
+	s := a.Rat().FloatString(AmountDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON encodes the amount as an exact json number
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a json number, a string holding a number, an empty string or null
+func (a *Amount) UnmarshalJSON(data []byte) (err error) {
+	s, ok, err := numericText(data)
+	if err != nil || !ok {
+		return
+	}
+	*a, err = ParseAmount(s)
+	return
+}
+
+// Hashrate is a hashrate in the unit nanopool reports for the coin, see CoinInfo.HashrateUnit.
+// It decodes from both the string and number encodings nanopool uses
+type Hashrate float64
+
+// HashesPerSecond converts a hashrate reported in unit to hashes (or solutions) per second
+func (h Hashrate) HashesPerSecond(unit HashrateUnit) float64 {
+	return float64(h) * unit.HashesPerSecond
+}
+
+// Format renders the hashrate with its unit, e.g. 95.5 MH/s
+func (h Hashrate) Format(unit HashrateUnit) string {
+	return fmt.Sprintf("%s %s", strconv.FormatFloat(float64(h), 'f', -1, 64), unit)
+}
+
+// UnmarshalJSON accepts a json number, a string holding a number, an empty string or null
+func (h *Hashrate) UnmarshalJSON(data []byte) (err error) {
+	s, ok, err := numericText(data)
+	if err != nil || !ok {
+		return
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("invalid hashrate %s", data)
+	}
+	*h = Hashrate(f)
+	return
+}
+
+// numericText returns the text of a json number or of a string holding one, ok is false for null and empty strings
+func numericText(data []byte) (s string, ok bool, err error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return
+	}
+	if len(data) > 0 && data[0] == '"' {
+		if err = json.Unmarshal(data, &s); err != nil {
+			return
+		}
+		s = strings.TrimSpace(s)
+		return s, s != "", nil
+	}
+	return string(data), true, nil
+}
@@ -0,0 +1,126 @@
+package nanopool
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"time"
+
+	"mining-tools/pool"
+)
+
+// Pool adapts a Client to the pool.Pool interface, converting hashrates from the coin's unit to hashes per second
+type Pool struct {
+	client *Client
+}
+
+// NewPool returns a pool.Pool backed by client
+func NewPool(client *Client) *Pool {
+	return &Pool{client: client}
+}
+
+// Coin returns the coin of the client, its API path segment such as eth or ergo
+func (p *Pool) Coin() string {
+	return p.client.Coin().String()
+}
+
+// Client returns the nanopool Client behind the Pool
+func (p *Pool) Client() *Client {
+	return p.client
+}
+
+// Balance returns the confirmed and unconfirmed balance of address from the Miner:General Info endpoint
+func (p *Pool) Balance(ctx context.Context, address string) (balance pool.Balance, err error) {
+	info, err := p.client.GetMinerGeneralInfo(ctx, address)
+	if err != nil {
+		return
+	}
+	balance.Confirmed = info.Data.Balance.Rat()
+	balance.Unconfirmed = info.Data.UnconfirmedBalance.Rat()
+	return
+}
+
+// Hashrate returns the current and 24 hour average hashrate of address
+func (p *Pool) Hashrate(ctx context.Context, address string) (hashrate pool.Hashrate, err error) {
+	info, err := p.client.GetMinerGeneralInfo(ctx, address)
+	if err != nil {
+		return
+	}
+	unit := p.client.Coin().Info().HashrateUnit
+	hashrate.Current = info.Data.Hashrate.HashesPerSecond(unit)
+	hashrate.Average = info.Data.AvgHashrate.H24.HashesPerSecond(unit)
+	return
+}
+
+// Workers returns the workers of address with their 24 hour average hashrate
+func (p *Pool) Workers(ctx context.Context, address string) (workers []pool.Worker, err error) {
+	info, err := p.client.GetMinerGeneralInfo(ctx, address)
+	if err != nil {
+		return
+	}
+	unit := p.client.Coin().Info().HashrateUnit
+	for _, w := range info.Data.Workers {
+		workers = append(workers, pool.Worker{
+			Name:            w.ID,
+			Hashrate:        w.Hashrate.HashesPerSecond(unit),
+			AverageHashrate: w.H24.HashesPerSecond(unit),
+			LastShare:       time.Unix(w.Lastshare, 0).UTC(),
+		})
+	}
+	return
+}
+
+// ShareHistory returns the shares of address in each 10 minute slot nanopool keeps
+func (p *Pool) ShareHistory(ctx context.Context, address string) (slots []pool.ShareSlot, err error) {
+	shareRate, err := p.client.GetMinerShareRate(ctx, address)
+	if err != nil {
+		return
+	}
+	for _, sr := range shareRate.Data {
+		slots = append(slots, pool.ShareSlot{Time: time.Unix(sr.Date, 0).UTC(), Shares: sr.Shares})
+	}
+	return
+}
+
+// Payments returns the payouts sent to address
+func (p *Pool) Payments(ctx context.Context, address string) (payments []pool.Payment, err error) {
+	mp, err := p.client.GetMinerPayments(ctx, address)
+	if err != nil {
+		return
+	}
+	for _, pd := range mp.Data {
+		payments = append(payments, pool.Payment{
+			Time:      time.Unix(pd.Date, 0).UTC(),
+			TXHash:    pd.TXHash,
+			Amount:    pd.Amount.Rat(),
+			Confirmed: pd.Confirmed,
+		})
+	}
+	return
+}
+
+// PayoutThreshold returns the balance at which address is paid out from the Miner:User Settings endpoint
+func (p *Pool) PayoutThreshold(ctx context.Context, address string) (threshold *big.Rat, err error) {
+	settings, err := p.client.GetUserSettings(ctx, address)
+	if err != nil {
+		return
+	}
+	return settings.Data.Payout.Rat(), nil
+}
+
+// Prices returns the price of the client's coin from the Prices endpoint, which is the same for every address
+func (p *Pool) Prices(ctx context.Context, address string) (prices pool.Prices, err error) {
+	op, err := p.client.GetOtherPrices(ctx)
+	if err != nil {
+		return
+	}
+	prices.USD = priceRat(op.Data.PriceUSD)
+	prices.BTC = priceRat(op.Data.PriceBTC)
+	return
+}
+
+// priceRat converts a price to a big.Rat through its shortest decimal form, so 730.51 stays exactly 730.51
+func priceRat(price float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(price, 'f', -1, 64))
+	return r
+}
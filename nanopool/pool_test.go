@@ -0,0 +1,106 @@
+package nanopool
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/pool"
+)
+
+func Test_Pool(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	p := NewPool(NewClient(WithAPIRoot("http://test.com/"), WithHTTPClient(mockClient)))
+	ctx := context.Background()
+	info := MinerGeneralInfo{
+		Status: true,
+		Data: MinerGeneralInfoData{
+			Account:            "0x01",
+			UnconfirmedBalance: mustParseAmount("0.0015"),
+			Balance:            mustParseAmount("0.142"),
+			Hashrate:           95.5,
+			AvgHashrate:        MinerAvgHashrate{H24: 90},
+			Workers:            []MinerGeneralInfoWorker{{ID: "rig1", Hashrate: 95.5, Lastshare: 1609459200, H24: 90}},
+		},
+	}
+	payments := MinerPayments{Status: true, Data: []MinerPaymentsData{
+		{Date: 1609459200, TXHash: "0x02", Amount: mustParseAmount("0.1"), Confirmed: true},
+	}}
+	mockClient.On("Do", "http://test.com/user/0x01").Return(info, nil)
+	mockClient.On("Do", "http://test.com/user/0x02").Return(user0x02Error, nil)
+	mockClient.On("Do", "http://test.com/shareratehistory/0x01").Return(MinerShareRate{Status: true, Data: []MinerShareRateData{{Date: 1609459200, Shares: 12}}}, nil)
+	mockClient.On("Do", "http://test.com/payments/0x01").Return(payments, nil)
+	mockClient.On("Do", "http://test.com/usersettings/0x01").Return(UserSettings{Status: true, Data: UserSettingsData{Payout: mustParseAmount("0.2")}}, nil)
+	mockClient.On("Do", "http://test.com/prices/").Return(OtherPrices{Status: true, Data: OtherPricesData{PriceUSD: 730.51, PriceBTC: 0.0251}}, nil)
+	at := time.Unix(1609459200, 0).UTC()
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name:     "Balance01",
+			call:     func() (interface{}, error) { return p.Balance(ctx, "0x01") },
+			wantData: pool.Balance{Confirmed: big.NewRat(142, 1000), Unconfirmed: big.NewRat(15, 10000)},
+		},
+		{
+			name:     "Balance02",
+			call:     func() (interface{}, error) { return p.Balance(ctx, "0x02") },
+			wantData: pool.Balance{},
+			wantErr:  ErrAddressNotFound,
+		},
+		{
+			name:     "Balance03",
+			call:     func() (interface{}, error) { return p.Balance(ctx, "0x02") },
+			wantData: pool.Balance{},
+			wantErr:  pool.ErrAccountNotFound,
+		},
+		{
+			name:     "Hashrate01",
+			call:     func() (interface{}, error) { return p.Hashrate(ctx, "0x01") },
+			wantData: pool.Hashrate{Current: 95.5e6, Average: 90e6},
+		},
+		{
+			name:     "Workers01",
+			call:     func() (interface{}, error) { return p.Workers(ctx, "0x01") },
+			wantData: []pool.Worker{{Name: "rig1", Hashrate: 95.5e6, AverageHashrate: 90e6, LastShare: at}},
+		},
+		{
+			name:     "ShareHistory01",
+			call:     func() (interface{}, error) { return p.ShareHistory(ctx, "0x01") },
+			wantData: []pool.ShareSlot{{Time: at, Shares: 12}},
+		},
+		{
+			name:     "Payments01",
+			call:     func() (interface{}, error) { return p.Payments(ctx, "0x01") },
+			wantData: []pool.Payment{{Time: at, TXHash: "0x02", Amount: big.NewRat(1, 10), Confirmed: true}},
+		},
+		{
+			name:     "PayoutThreshold01",
+			call:     func() (interface{}, error) { return p.PayoutThreshold(ctx, "0x01") },
+			wantData: big.NewRat(1, 5),
+		},
+		{
+			name:     "Prices01",
+			call:     func() (interface{}, error) { return p.Prices(ctx, "0x01") },
+			wantData: pool.Prices{USD: big.NewRat(73051, 100), BTC: big.NewRat(251, 10000)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+}
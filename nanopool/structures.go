@@ -15,37 +15,37 @@ type MinerGeneralInfo struct {
 // MinerGeneralInfoData is for decoding json from a successful response of the nanopool miner general info api endpoint
 type MinerGeneralInfoData struct {
 	Account            string                   `json:"account"`
-	UnconfirmedBalance string                   `json:"unconfirmed_balance"`
-	Balance            string                   `json:"balance"`
-	Hashrate           string                   `json:"hashrate"`
+	UnconfirmedBalance Amount                   `json:"unconfirmed_balance"`
+	Balance            Amount                   `json:"balance"`
+	Hashrate           Hashrate                 `json:"hashrate"`
 	AvgHashrate        MinerAvgHashrate         `json:"avgHashrate"`
 	Workers            []MinerGeneralInfoWorker `json:"workers"`
-	RewardPerShare     string                   `json:"rewardPerShare,omitempty"`
+	RewardPerShare     float64                  `json:"rewardPerShare,omitempty"`
 	SharesPerHour      int64                    `json:"sharesPerHour,omitempty"`
-	RewardPerHour      string                   `json:"rewardPerHour,omitempty"`
+	RewardPerHour      float64                  `json:"rewardPerHour,omitempty"`
 }
 
 // MinerAvgHashrate is for decoding json from a successful response of the nanopool miner general info api endpoint
 type MinerAvgHashrate struct {
-	H1  string `json:"h1"`
-	H3  string `json:"h3"`
-	H6  string `json:"h6"`
-	H12 string `json:"h12"`
-	H24 string `json:"h24"`
+	H1  Hashrate `json:"h1"`
+	H3  Hashrate `json:"h3"`
+	H6  Hashrate `json:"h6"`
+	H12 Hashrate `json:"h12"`
+	H24 Hashrate `json:"h24"`
 }
 
 // MinerGeneralInfoWorker is for decoding json from a successful response of the nanopool miner general info api endpoint
 type MinerGeneralInfoWorker struct {
-	ID        string `json:"id"`
-	UID       int64  `json:"uid"`
-	Hashrate  string `json:"hashrate"`
-	Lastshare int64  `json:"lastshare"`
-	Rating    int64  `json:"rating"`
-	H1        string `json:"h1"`
-	H3        string `json:"h3"`
-	H6        string `json:"h6"`
-	H12       string `json:"h12"`
-	H24       string `json:"h24"`
+	ID        string   `json:"id"`
+	UID       int64    `json:"uid"`
+	Hashrate  Hashrate `json:"hashrate"`
+	Lastshare int64    `json:"lastshare"`
+	Rating    int64    `json:"rating"`
+	H1        Hashrate `json:"h1"`
+	H3        Hashrate `json:"h3"`
+	H6        Hashrate `json:"h6"`
+	H12       Hashrate `json:"h12"`
+	H24       Hashrate `json:"h24"`
 }
 
 // MinerShareRate is for decoding json from a successful response of the nanopool miner general info api endpoint
@@ -68,16 +68,16 @@ type MinerPayments struct {
 
 // MinerPaymentsData is for decoding json from a successful response of the nanopool miner general info api endpoint
 type MinerPaymentsData struct {
-	Date      int64   `json:"date"`
-	TXHash    string  `json:"txHash"`
-	Amount    float64 `json:"amount"`
-	Confirmed bool    `json:"confirmed"`
+	Date      int64  `json:"date"`
+	TXHash    string `json:"txHash"`
+	Amount    Amount `json:"amount"`
+	Confirmed bool   `json:"confirmed"`
 }
 
 // MinerBalance is for decoding json from a successful response of the nanopool miner balance api endpoint
 type MinerBalance struct {
-	Status bool    `json:"status"`
-	Data   float64 `json:"data"`
+	Status bool   `json:"status"`
+	Data   Amount `json:"data"`
 }
 
 // OtherPrices is for decoding json from a successful response of the nanopool other prices api endpoint
@@ -94,3 +94,208 @@ type OtherPricesData struct {
 	PriceCNY float64 `json:"price_cny"`
 	PriceBTC float64 `json:"price_btc"`
 }
+
+// MinerAccountExist is for decoding json from a successful response of the nanopool miner check account api endpoint
+type MinerAccountExist struct {
+	Status bool   `json:"status"`
+	Data   string `json:"data"`
+}
+
+// MinerHashrate is for decoding json from a successful response of the nanopool endpoints that return a single hashrate,
+// such as miner current/reported hashrate, worker current/reported hashrate and miner limited average hashrate
+type MinerHashrate struct {
+	Status bool     `json:"status"`
+	Data   Hashrate `json:"data"`
+}
+
+// MinerHashrateChart is for decoding json from a successful response of the nanopool miner and worker hashrate chart api endpoints
+type MinerHashrateChart struct {
+	Status bool                     `json:"status"`
+	Data   []MinerHashrateChartData `json:"data"`
+}
+
+// MinerHashrateChartData is for decoding json from a successful response of the nanopool miner and worker hashrate chart api endpoints
+type MinerHashrateChartData struct {
+	Date     int64    `json:"date"`
+	Shares   int64    `json:"shares"`
+	Hashrate Hashrate `json:"hashrate"`
+}
+
+// MinerHistory is for decoding json from a successful response of the nanopool miner and worker hashrate history api endpoints
+type MinerHistory struct {
+	Status bool               `json:"status"`
+	Data   []MinerHistoryData `json:"data"`
+}
+
+// MinerHistoryData is for decoding json from a successful response of the nanopool miner and worker hashrate history api endpoints
+type MinerHistoryData struct {
+	Date     int64    `json:"date"`
+	Hashrate Hashrate `json:"hashrate"`
+}
+
+// MinerBalanceHashrate is for decoding json from a successful response of the nanopool miner balance and hashrate api endpoint
+type MinerBalanceHashrate struct {
+	Status bool                     `json:"status"`
+	Data   MinerBalanceHashrateData `json:"data"`
+}
+
+// MinerBalanceHashrateData is for decoding json from a successful response of the nanopool miner balance and hashrate api endpoint
+type MinerBalanceHashrateData struct {
+	Hashrate Hashrate `json:"hashrate"`
+	Balance  Amount   `json:"balance"`
+}
+
+// MinerAvgHashrates is for decoding json from a successful response of the nanopool miner average hashrate api endpoint
+type MinerAvgHashrates struct {
+	Status bool             `json:"status"`
+	Data   MinerAvgHashrate `json:"data"`
+}
+
+// MinerReportedHashrates is for decoding json from a successful response of the nanopool miner workers reported hashrate api endpoint
+type MinerReportedHashrates struct {
+	Status bool                        `json:"status"`
+	Data   []MinerReportedHashrateData `json:"data"`
+}
+
+// MinerReportedHashrateData is for decoding json from a successful response of the nanopool miner workers reported hashrate api endpoint
+type MinerReportedHashrateData struct {
+	Worker   string   `json:"worker"`
+	Hashrate Hashrate `json:"hashrate"`
+}
+
+// MinerWorkers is for decoding json from a successful response of the nanopool miner workers api endpoint, the per worker
+// averages H1 through H24 are only filled by the general info endpoint
+type MinerWorkers struct {
+	Status bool                     `json:"status"`
+	Data   []MinerGeneralInfoWorker `json:"data"`
+}
+
+// MinerPaymentsDay is for decoding json from a successful response of the nanopool miner payments by day api endpoint
+type MinerPaymentsDay struct {
+	Status bool                   `json:"status"`
+	Data   []MinerPaymentsDayData `json:"data"`
+}
+
+// MinerPaymentsDayData is for decoding json from a successful response of the nanopool miner payments by day api endpoint
+type MinerPaymentsDayData struct {
+	Date   int64  `json:"date"`
+	Amount Amount `json:"amount"`
+}
+
+// UserSettings is for decoding json from a successful response of the nanopool user settings api endpoint
+type UserSettings struct {
+	Status bool             `json:"status"`
+	Data   UserSettingsData `json:"data"`
+}
+
+// UserSettingsData is for decoding json from a successful response of the nanopool user settings api endpoint,
+// Payout is the balance at which nanopool pays the account out
+type UserSettingsData struct {
+	Payout Amount `json:"payout"`
+}
+
+// ApproximatedEarnings is for decoding json from a successful response of the nanopool approximated earnings api endpoint
+type ApproximatedEarnings struct {
+	Status bool                     `json:"status"`
+	Data   ApproximatedEarningsData `json:"data"`
+}
+
+// ApproximatedEarningsData is for decoding json from a successful response of the nanopool approximated earnings api endpoint
+type ApproximatedEarningsData struct {
+	Minute ApproximatedEarningsPeriod `json:"minute"`
+	Hour   ApproximatedEarningsPeriod `json:"hour"`
+	Day    ApproximatedEarningsPeriod `json:"day"`
+	Week   ApproximatedEarningsPeriod `json:"week"`
+	Month  ApproximatedEarningsPeriod `json:"month"`
+}
+
+// ApproximatedEarningsPeriod is for decoding json from a successful response of the nanopool approximated earnings api endpoint
+type ApproximatedEarningsPeriod struct {
+	Coins    float64 `json:"coins"`
+	Dollars  float64 `json:"dollars"`
+	Yuan     float64 `json:"yuan"`
+	Euros    float64 `json:"euros"`
+	Rubles   float64 `json:"rubles"`
+	Bitcoins float64 `json:"bitcoins"`
+}
+
+// PoolCount is for decoding json from a successful response of the nanopool pool active miners and active workers api endpoints
+type PoolCount struct {
+	Status bool  `json:"status"`
+	Data   int64 `json:"data"`
+}
+
+// PoolHashrate is for decoding json from a successful response of the nanopool pool hashrate api endpoint
+type PoolHashrate struct {
+	Status bool     `json:"status"`
+	Data   Hashrate `json:"data"`
+}
+
+// PoolTopMiners is for decoding json from a successful response of the nanopool pool top miners api endpoint
+type PoolTopMiners struct {
+	Status bool                `json:"status"`
+	Data   []PoolTopMinersData `json:"data"`
+}
+
+// PoolTopMinersData is for decoding json from a successful response of the nanopool pool top miners api endpoint
+type PoolTopMinersData struct {
+	Number   int64    `json:"number"`
+	Address  string   `json:"address"`
+	Hashrate Hashrate `json:"hashrate"`
+}
+
+// PoolPayments is for decoding json from a successful response of the nanopool pool payments api endpoint
+type PoolPayments struct {
+	Status bool               `json:"status"`
+	Data   []PoolPaymentsData `json:"data"`
+}
+
+// PoolPaymentsData is for decoding json from a successful response of the nanopool pool payments api endpoint
+type PoolPaymentsData struct {
+	Date      int64  `json:"date"`
+	Address   string `json:"address"`
+	TXHash    string `json:"txHash"`
+	Amount    Amount `json:"amount"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+// NetworkAvgBlockTime is for decoding json from a successful response of the nanopool network average block time api endpoint
+type NetworkAvgBlockTime struct {
+	Status bool    `json:"status"`
+	Data   float64 `json:"data"`
+}
+
+// NetworkLastBlockNumber is for decoding json from a successful response of the nanopool network last block number api endpoint
+type NetworkLastBlockNumber struct {
+	Status bool  `json:"status"`
+	Data   int64 `json:"data"`
+}
+
+// BlockStats is for decoding json from a successful response of the nanopool block stats api endpoint
+type BlockStats struct {
+	Status bool             `json:"status"`
+	Data   []BlockStatsData `json:"data"`
+}
+
+// BlockStatsData is for decoding json from a successful response of the nanopool block stats api endpoint
+type BlockStatsData struct {
+	Date       int64   `json:"date"`
+	Difficulty float64 `json:"difficulty"`
+	BlockTime  float64 `json:"block_time"`
+}
+
+// Blocks is for decoding json from a successful response of the nanopool blocks api endpoint
+type Blocks struct {
+	Status bool         `json:"status"`
+	Data   []BlocksData `json:"data"`
+}
+
+// BlocksData is for decoding json from a successful response of the nanopool blocks api endpoint
+type BlocksData struct {
+	Number int64  `json:"number"`
+	Hash   string `json:"hash"`
+	Date   int64  `json:"date"`
+	Value  Amount `json:"value"`
+	Miner  string `json:"miner"`
+	Status int64  `json:"status"`
+}
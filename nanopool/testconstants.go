@@ -5,8 +5,8 @@ var (
 		Status: true,
 		Data: MinerGeneralInfoData{
 			Account:            "0x01",
-			UnconfirmedBalance: "0.0",
-			Balance:            "0.142",
+			UnconfirmedBalance: 0,
+			Balance:            142000000000, // 0.142
 			Workers: []MinerGeneralInfoWorker{
 				{
 					Rating: 2000,
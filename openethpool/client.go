@@ -0,0 +1,233 @@
+// Package openethpool is a client for the API served by open-ethereum-pool, the pool software run by 2Miners and many
+// smaller pools, so one implementation can watch accounts on any of them given the pool's API root
+package openethpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"mining-tools/throttle"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultBaseURL is the API root of the 2Miners ethereum pool
+	DefaultBaseURL = "https://eth.2miners.com/api/"
+	// DefaultDecimals is the number of decimal places of the unit amounts are kept in, 9 for the Shannon used by
+	// ethereum pools
+	DefaultDecimals = 9
+	// DefaultTimeout is the per-call timeout a Client applies when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+)
+
+// HTTPClient is an interface to abstract http.client to support testing using mocks
+type HTTPClient interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+var (
+	apiClient = HTTPClient(&http.Client{})
+)
+
+// Client talks to a single open-ethereum-pool API root using its own transport, user agent and timeout
+type Client struct {
+	baseURL    string
+	coin       Coin
+	decimals   int
+	httpClient HTTPClient
+	userAgent  string
+	timeout    time.Duration
+	limiter    *throttle.Limiter
+	retry      throttle.Policy
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithBaseURL sets the API root every endpoint path is appended to, e.g. https://eth.2miners.com/api/
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithCoin sets the coin mined on the API along with the 2Miners API root and decimals it is served with, give
+// WithBaseURL after it to reach another open-ethereum-pool of the coin
+func WithCoin(coin Coin) Option {
+	return func(c *Client) {
+		c.coin = coin
+		c.baseURL = coin.Info().BaseURL
+		c.decimals = coin.Info().Decimals
+	}
+}
+
+// WithDecimals sets the decimal places of the unit amounts are kept in, for pools of coins not counted in Shannon
+func WithDecimals(decimals int) Option {
+	return func(c *Client) {
+		c.decimals = decimals
+	}
+}
+
+// WithHTTPClient sets the transport used for every request
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the timeout applied to each call, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRateLimiter sets the limiter every request waits on, share one limiter between all clients of the same API
+func WithRateLimiter(limiter *throttle.Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried, by default a request is attempted once
+func WithRetryPolicy(policy throttle.Policy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// NewClient returns a Client for the 2Miners ethereum pool adjusted by options
+func NewClient(options ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		coin:       ETH,
+		decimals:   DefaultDecimals,
+		httpClient: apiClient,
+		timeout:    DefaultTimeout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// BaseURL returns the URL every endpoint path is appended to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Coin returns the coin mined on the API
+func (c *Client) Coin() Coin {
+	return c.coin
+}
+
+// Decimals returns the decimal places of the unit amounts are kept in
+func (c *Client) Decimals() int {
+	return c.decimals
+}
+
+// GetAccount calls the accounts/:login endpoint and forms the response in to a usable Struct
+func (c *Client) GetAccount(ctx context.Context, login string) (account Account, err error) {
+	err = c.get(ctx, "accounts/"+login, &account)
+	return
+}
+
+// GetStats calls the stats endpoint and forms the response in to a usable Struct
+func (c *Client) GetStats(ctx context.Context) (stats Stats, err error) {
+	err = c.get(ctx, "stats", &stats)
+	return
+}
+
+// GetBlocks calls the blocks endpoint and forms the response in to a usable Struct
+func (c *Client) GetBlocks(ctx context.Context) (blocks Blocks, err error) {
+	err = c.get(ctx, "blocks", &blocks)
+	return
+}
+
+// GetPayments calls the payments endpoint, listing the latest payouts to every miner, and forms the response in to a
+// usable Struct
+func (c *Client) GetPayments(ctx context.Context) (payments Payments, err error) {
+	err = c.get(ctx, "payments", &payments)
+	return
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, output interface{}) (err error) {
+	fullPath := c.baseURL + endpoint
+	log.Debugf("Client.get(fullPath=%s, output interface{}) called\n", fullPath)
+	resp, attempts, err := c.retry.Do(ctx, c.limiter, func(ctx context.Context) (*http.Response, error) {
+		return c.send(ctx, fullPath)
+	})
+	defer func() {
+		if err != nil && attempts > 1 {
+			err = &throttle.Error{Attempts: attempts, Err: err}
+		}
+	}()
+	if err != nil {
+		log.Errorf("Client.get: c.send(ctx, %s); returned err=%s after %d attempts\n", fullPath, err.Error(), attempts)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("Client.get: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return
+	}
+	// Successful responses are plain objects, they decode to an envelope without an error
+	envelope := new(ErrorResponse)
+	if json.Unmarshal(body, envelope) != nil {
+		envelope = nil
+	}
+	err = checkResponse(endpoint, resp.StatusCode, envelope)
+	if err != nil {
+		log.Errorf("Client.get: checkResponse(%s, %d, envelope); returned err=%s after %d attempts\n", endpoint, resp.StatusCode, err.Error(), attempts)
+		return
+	}
+	err = json.Unmarshal(body, output)
+	if err != nil {
+		log.Errorf("Client.get: json.Unmarshal(body, output); returned err=%s\n", err.Error())
+		err = &APIError{Endpoint: endpoint, StatusCode: resp.StatusCode, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	return
+}
+
+// send makes a single attempt at fullPath within the per-call timeout, the body is read before the timeout is released
+func (c *Client) send(ctx context.Context, fullPath string) (resp *http.Response, err error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath, nil)
+	if err != nil {
+		log.Errorf("Client.send: http.NewRequestWithContext(ctx, GET, %s, nil); returned err=%s\n", fullPath, err.Error())
+		return
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err = c.httpClient.Do(req)
+	if err != nil {
+		log.Errorf("Client.send: c.httpClient.Do(%s); returned err=%s\n", fullPath, err.Error())
+		return
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		log.Errorf("Client.send: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return
+}
@@ -0,0 +1,199 @@
+package openethpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"mining-tools/pool"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAPIClient struct {
+	mock.Mock
+}
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func (mac *mockAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	args := mac.Called(req.URL.String())
+	body, _ := json.Marshal(args.Get(0))
+	resp = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBuffer(body)),
+	}
+	return resp, args.Error(1)
+}
+
+type cannedAPIClient struct {
+	statusCode int
+	body       string
+}
+
+func (cac *cannedAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	resp = &http.Response{
+		StatusCode: cac.statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(cac.body)),
+	}
+	return
+}
+
+func Test_getErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    error
+	}{
+		{
+			name:       "Success01",
+			statusCode: http.StatusOK,
+			body:       `{"currentHashrate":190500000,"stats":{"balance":142000000}}`,
+			wantErr:    nil,
+		},
+		{
+			name:       "AddressNotFound01",
+			statusCode: http.StatusNotFound,
+			body:       ``,
+			wantErr:    ErrAddressNotFound,
+		},
+		{
+			name:       "AddressNotFound02",
+			statusCode: http.StatusOK,
+			body:       `{"error":"Invalid login"}`,
+			wantErr:    pool.ErrAccountNotFound,
+		},
+		{
+			name:       "RateLimited01",
+			statusCode: http.StatusTooManyRequests,
+			body:       `Too Many Requests`,
+			wantErr:    pool.ErrRateLimited,
+		},
+		{
+			name:       "ServerError01",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>Bad Gateway</html>`,
+			wantErr:    ErrServer,
+		},
+		{
+			name:       "Malformed01",
+			statusCode: http.StatusOK,
+			body:       `<html>maintenance</html>`,
+			wantErr:    ErrMalformedResponse,
+		},
+		{
+			name:       "Malformed02",
+			statusCode: http.StatusOK,
+			body:       `{"stats":{"balance":"lots"}}`,
+			wantErr:    ErrMalformedResponse,
+		},
+		{
+			name:       "RequestFailed01",
+			statusCode: http.StatusForbidden,
+			body:       `{"error":"Something else"}`,
+			wantErr:    ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(WithBaseURL("http://test.com/api/"), WithHTTPClient(&cannedAPIClient{tt.statusCode, tt.body}))
+			_, err := c.GetAccount(context.Background(), "0x02")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.statusCode) {
+				t.Errorf("Client.GetAccount() error = %#v, want *APIError with StatusCode %d", err, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_endpoints(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	c := NewClient(WithBaseURL("http://test.com/api/"), WithHTTPClient(mockClient))
+	ctx := context.Background()
+	stats := Stats{Hashrate: 25000000000, MinersTotal: 1200, Nodes: []StatsNode{{Difficulty: "3000000000000000", Height: "11565019", Name: "main"}}, Stats: StatsPool{LastBlockFound: 1609459000, RoundShares: 9000}}
+	blocks := Blocks{Matured: []Block{{Height: 11565000, Timestamp: 1609459000, Hash: "0xab", Reward: "2000000000000000000"}}, MaturedTotal: 1}
+	payments := Payments{Payments: []Payment{{Address: "0x01", Amount: 100000000, Timestamp: 1609459200, TX: "0x02"}}, PaymentsTotal: 1}
+	mockClient.On("Do", "http://test.com/api/accounts/0x01").Return(login0x01Account, nil)
+	mockClient.On("Do", "http://test.com/api/stats").Return(stats, nil)
+	mockClient.On("Do", "http://test.com/api/blocks").Return(blocks, nil)
+	mockClient.On("Do", "http://test.com/api/payments").Return(payments, nil)
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+	}{
+		{
+			name:     "Account01",
+			call:     func() (interface{}, error) { r, err := c.GetAccount(ctx, "0x01"); return r, err },
+			wantData: *login0x01Account,
+		},
+		{
+			name:     "Stats01",
+			call:     func() (interface{}, error) { r, err := c.GetStats(ctx); return r, err },
+			wantData: stats,
+		},
+		{
+			name:     "Blocks01",
+			call:     func() (interface{}, error) { r, err := c.GetBlocks(ctx); return r, err },
+			wantData: blocks,
+		},
+		{
+			name:     "Payments01",
+			call:     func() (interface{}, error) { r, err := c.GetPayments(ctx); return r, err },
+			wantData: payments,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if err != nil {
+				t.Errorf("%s error = %v", tt.name, err)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+}
+
+func Test_ParseCoin(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		wantCoin Coin
+		wantErr  bool
+	}{
+		{name: "Default01", s: "", wantCoin: ETH},
+		{name: "Ticker01", s: "RVN", wantCoin: RVN},
+		{name: "Unsupported01", s: "xmr", wantCoin: Coin("xmr"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCoin, err := ParseCoin(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCoin(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+				return
+			}
+			if gotCoin != tt.wantCoin {
+				t.Errorf("ParseCoin(%q) = %v, want %v", tt.s, gotCoin, tt.wantCoin)
+			}
+		})
+	}
+	if got := NewClient(WithCoin(ETC)); got.BaseURL() != "https://etc.2miners.com/api/" || got.Decimals() != 9 {
+		t.Errorf("NewClient(WithCoin(ETC)) = %s with %d decimals, want the 2Miners ETC API with 9", got.BaseURL(), got.Decimals())
+	}
+}
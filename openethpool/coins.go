@@ -0,0 +1,60 @@
+package openethpool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Coin identifies a coin 2Miners runs an open-ethereum-pool API for, its value is the coin's ticker
+type Coin string
+
+// Coins supported by the 2Miners open-ethereum-pool APIs
+const (
+	ETH Coin = "eth"
+	ETC Coin = "etc"
+	RVN Coin = "rvn"
+)
+
+// CoinInfo is where the 2Miners API of a coin is served and the unit its amounts are kept in
+type CoinInfo struct {
+	Name    string
+	BaseURL string
+	// Decimals is the number of decimal places of the unit amounts are kept in, Shannon for 18 decimal coins
+	Decimals int
+}
+
+var coins = map[Coin]CoinInfo{
+	ETH: {Name: "Ethereum", BaseURL: DefaultBaseURL, Decimals: 9},
+	ETC: {Name: "Ethereum Classic", BaseURL: "https://etc.2miners.com/api/", Decimals: 9},
+	RVN: {Name: "Ravencoin", BaseURL: "https://rvn.2miners.com/api/", Decimals: 8},
+}
+
+// Coins returns every coin with a known open-ethereum-pool API
+func Coins() []Coin {
+	return []Coin{ETH, ETC, RVN}
+}
+
+// ParseCoin maps a ticker such as "ETH" or "rvn" to a Coin, an empty string maps to ETH
+func ParseCoin(s string) (coin Coin, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ETH, nil
+	}
+	coin = Coin(s)
+	if _, ok := coins[coin]; !ok {
+		err = fmt.Errorf("unsupported openethpool coin %q, expected one of %v", s, Coins())
+	}
+	return
+}
+
+// Info returns where the coin is served and its decimals, unknown coins are treated like ETH
+func (c Coin) Info() CoinInfo {
+	if info, ok := coins[c]; ok {
+		return info
+	}
+	return coins[ETH]
+}
+
+func (c Coin) String() string {
+	return string(c)
+}
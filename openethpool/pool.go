@@ -0,0 +1,166 @@
+package openethpool
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"mining-tools/pool"
+)
+
+// accountTTL is how long an accounts response is reused, every Pool method is answered from the same response
+const accountTTL = time.Minute
+
+// Pool adapts a Client to the pool.Pool interface, it also counts online and offline workers and reports the 24h reward
+type Pool struct {
+	client *Client
+	scale  *big.Int
+
+	mu       sync.Mutex
+	accounts map[string]cachedAccount
+}
+
+type cachedAccount struct {
+	account Account
+	expires time.Time
+}
+
+// NewPool returns a pool.Pool backed by client
+func NewPool(client *Client) *Pool {
+	return &Pool{
+		client:   client,
+		scale:    new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(client.Decimals())), nil),
+		accounts: make(map[string]cachedAccount),
+	}
+}
+
+// Coin returns the ticker of the coin mined on the client's API
+func (p *Pool) Coin() string {
+	return p.client.Coin().String()
+}
+
+// Client returns the open-ethereum-pool Client behind the Pool
+func (p *Pool) Client() *Client {
+	return p.client
+}
+
+// account returns the accounts response for login, reusing a response younger than accountTTL
+func (p *Pool) account(ctx context.Context, login string) (account Account, err error) {
+	p.mu.Lock()
+	cached, ok := p.accounts[login]
+	p.mu.Unlock()
+	if ok && cached.expires.After(time.Now()) {
+		return cached.account, nil
+	}
+	account, err = p.client.GetAccount(ctx, login)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	p.accounts[login] = cachedAccount{account: account, expires: time.Now().Add(accountTTL)}
+	p.mu.Unlock()
+	return
+}
+
+// coins converts an amount in the unit the pool keeps to whole coins
+func (p *Pool) coins(units int64) *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(units), p.scale)
+}
+
+// Balance returns the balance of login awaiting payout and its immature balance
+func (p *Pool) Balance(ctx context.Context, login string) (balance pool.Balance, err error) {
+	account, err := p.account(ctx, login)
+	if err != nil {
+		return
+	}
+	balance.Confirmed = p.coins(account.Stats.Balance)
+	balance.Unconfirmed = p.coins(account.Stats.Immature)
+	return
+}
+
+// Hashrate returns the hashrate of login over the pool's short and large windows
+func (p *Pool) Hashrate(ctx context.Context, login string) (hashrate pool.Hashrate, err error) {
+	account, err := p.account(ctx, login)
+	if err != nil {
+		return
+	}
+	hashrate.Current = account.CurrentHashrate
+	hashrate.Average = account.Hashrate
+	return
+}
+
+// Workers returns the workers of login sorted by name
+func (p *Pool) Workers(ctx context.Context, login string) (workers []pool.Worker, err error) {
+	account, err := p.account(ctx, login)
+	if err != nil {
+		return
+	}
+	for name, w := range account.Workers {
+		workers = append(workers, pool.Worker{
+			Name:            name,
+			Hashrate:        w.HR,
+			AverageHashrate: w.HR2,
+			LastShare:       time.Unix(w.LastBeat, 0).UTC(),
+		})
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].Name < workers[j].Name })
+	return
+}
+
+// ShareHistory is not published by open-ethereum-pool, which only counts the shares of the current round
+func (p *Pool) ShareHistory(ctx context.Context, login string) (slots []pool.ShareSlot, err error) {
+	return nil, fmt.Errorf("open-ethereum-pool share history: %w", pool.ErrUnsupported)
+}
+
+// Payments returns the latest payouts sent to login, a page of them as the pool lists them
+func (p *Pool) Payments(ctx context.Context, login string) (payments []pool.Payment, err error) {
+	account, err := p.account(ctx, login)
+	if err != nil {
+		return
+	}
+	for _, pd := range account.Payments {
+		payments = append(payments, pool.Payment{
+			Time:      time.Unix(pd.Timestamp, 0).UTC(),
+			TXHash:    pd.TX,
+			Amount:    p.coins(pd.Amount),
+			Confirmed: true,
+		})
+	}
+	return
+}
+
+// PayoutThreshold returns the payout threshold login picked, only pools such as 2Miners that serve it per account
+// support it
+func (p *Pool) PayoutThreshold(ctx context.Context, login string) (threshold *big.Rat, err error) {
+	account, err := p.account(ctx, login)
+	if err != nil {
+		return
+	}
+	if account.Config == nil {
+		return nil, fmt.Errorf("open-ethereum-pool payout threshold: %w", pool.ErrUnsupported)
+	}
+	return p.coins(account.Config.MinPayout), nil
+}
+
+// WorkerCounts returns the number of online and offline workers of login
+func (p *Pool) WorkerCounts(ctx context.Context, login string) (counts pool.WorkerCounts, err error) {
+	account, err := p.account(ctx, login)
+	if err != nil {
+		return
+	}
+	counts.Online = account.WorkersOnline
+	counts.Offline = account.WorkersOffline
+	return
+}
+
+// Reward24h returns the coins credited to login for blocks found in the last 24 hours
+func (p *Pool) Reward24h(ctx context.Context, login string) (reward *big.Rat, err error) {
+	account, err := p.account(ctx, login)
+	if err != nil {
+		return
+	}
+	return p.coins(account.Reward24h), nil
+}
@@ -0,0 +1,95 @@
+package openethpool
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/pool"
+)
+
+func Test_Pool(t *testing.T) {
+	// Set up
+	mockClient := &mockAPIClient{}
+	p := NewPool(NewClient(WithBaseURL("http://test.com/api/"), WithHTTPClient(mockClient)))
+	ctx := context.Background()
+	twoMiners := *login0x01Account
+	twoMiners.Config = &AccountConfig{MinPayout: 200000000}
+	mockClient.On("Do", "http://test.com/api/accounts/0x01").Return(login0x01Account, nil).Once()
+	mockClient.On("Do", "http://test.com/api/accounts/0x03").Return(twoMiners, nil).Once()
+	at := time.Unix(1609459200, 0).UTC()
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name:     "Balance01",
+			call:     func() (interface{}, error) { return p.Balance(ctx, "0x01") },
+			wantData: pool.Balance{Confirmed: big.NewRat(142, 1000), Unconfirmed: big.NewRat(15, 10000)},
+		},
+		{
+			name:     "Hashrate01",
+			call:     func() (interface{}, error) { return p.Hashrate(ctx, "0x01") },
+			wantData: pool.Hashrate{Current: 190500000, Average: 185000000},
+		},
+		{
+			name: "Workers01",
+			call: func() (interface{}, error) { return p.Workers(ctx, "0x01") },
+			wantData: []pool.Worker{
+				{Name: "rig1", Hashrate: 95500000, AverageHashrate: 90000000, LastShare: at},
+				{Name: "rig2", Hashrate: 0, AverageHashrate: 40000000, LastShare: time.Unix(1609459100, 0).UTC()},
+			},
+		},
+		{
+			name:     "WorkerCounts01",
+			call:     func() (interface{}, error) { return p.WorkerCounts(ctx, "0x01") },
+			wantData: pool.WorkerCounts{Online: 1, Offline: 1},
+		},
+		{
+			name:     "Reward24h01",
+			call:     func() (interface{}, error) { return p.Reward24h(ctx, "0x01") },
+			wantData: big.NewRat(9, 1000),
+		},
+		{
+			name:     "ShareHistory01",
+			call:     func() (interface{}, error) { return p.ShareHistory(ctx, "0x01") },
+			wantData: []pool.ShareSlot(nil),
+			wantErr:  pool.ErrUnsupported,
+		},
+		{
+			name:     "Payments01",
+			call:     func() (interface{}, error) { return p.Payments(ctx, "0x01") },
+			wantData: []pool.Payment{{Time: at, TXHash: "0x02", Amount: big.NewRat(1, 10), Confirmed: true}},
+		},
+		{
+			name:     "PayoutThreshold01",
+			call:     func() (interface{}, error) { return p.PayoutThreshold(ctx, "0x01") },
+			wantData: (*big.Rat)(nil),
+			wantErr:  pool.ErrUnsupported,
+		},
+		{
+			name:     "PayoutThreshold02",
+			call:     func() (interface{}, error) { return p.PayoutThreshold(ctx, "0x03") },
+			wantData: big.NewRat(1, 5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData, err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("%s = %v, want %v", tt.name, gotData, tt.wantData)
+			}
+		})
+	}
+	// Every method is answered from one accounts response per login
+	mockClient.AssertNumberOfCalls(t, "Do", 2)
+}
@@ -0,0 +1,114 @@
+package openethpool
+
+// ErrorResponse is a struct for marshaling json of any error coming from an open-ethereum-pool API
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Account is for decoding json from a successful response of the open-ethereum-pool accounts api endpoint,
+// amounts are in the smallest unit the pool keeps, Shannon (10^-9 ETH) for ethereum pools
+type Account struct {
+	CurrentHashrate float64                  `json:"currentHashrate"`
+	Hashrate        float64                  `json:"hashrate"`
+	PageSize        int64                    `json:"pageSize"`
+	Payments        []Payment                `json:"payments"`
+	PaymentsTotal   int64                    `json:"paymentsTotal"`
+	RoundShares     int64                    `json:"roundShares"`
+	Stats           AccountStats             `json:"stats"`
+	Workers         map[string]AccountWorker `json:"workers"`
+	WorkersOffline  int64                    `json:"workersOffline"`
+	WorkersOnline   int64                    `json:"workersOnline"`
+	WorkersTotal    int64                    `json:"workersTotal"`
+	Reward24h       int64                    `json:"24hreward"`
+	NumReward24h    int64                    `json:"24hnumreward"`
+	// Config is only served by pools, such as 2Miners, that let miners pick their payout threshold
+	Config *AccountConfig `json:"config,omitempty"`
+}
+
+// AccountStats is for decoding json from a successful response of the open-ethereum-pool accounts api endpoint
+type AccountStats struct {
+	Balance     int64 `json:"balance"`
+	BlocksFound int64 `json:"blocksFound"`
+	Immature    int64 `json:"immature"`
+	LastShare   int64 `json:"lastShare"`
+	Paid        int64 `json:"paid"`
+	Pending     bool  `json:"pending"`
+}
+
+// AccountWorker is for decoding json from a successful response of the open-ethereum-pool accounts api endpoint,
+// HR covers the short hashrate window and HR2 the large one
+type AccountWorker struct {
+	LastBeat int64   `json:"lastBeat"`
+	HR       float64 `json:"hr"`
+	Offline  bool    `json:"offline"`
+	HR2      float64 `json:"hr2"`
+}
+
+// AccountConfig is for decoding json from a successful response of the 2Miners accounts api endpoint
+type AccountConfig struct {
+	MinPayout int64 `json:"minPayout"`
+}
+
+// Payment is for decoding json from a successful response of the open-ethereum-pool accounts and payments api endpoints
+type Payment struct {
+	Address   string `json:"address,omitempty"`
+	Amount    int64  `json:"amount"`
+	Timestamp int64  `json:"timestamp"`
+	TX        string `json:"tx"`
+}
+
+// Payments is for decoding json from a successful response of the open-ethereum-pool payments api endpoint
+type Payments struct {
+	Payments      []Payment `json:"payments"`
+	PaymentsTotal int64     `json:"paymentsTotal"`
+}
+
+// Stats is for decoding json from a successful response of the open-ethereum-pool stats api endpoint
+type Stats struct {
+	CandidatesTotal int64       `json:"candidatesTotal"`
+	Hashrate        float64     `json:"hashrate"`
+	ImmatureTotal   int64       `json:"immatureTotal"`
+	MaturedTotal    int64       `json:"maturedTotal"`
+	MinersTotal     int64       `json:"minersTotal"`
+	Nodes           []StatsNode `json:"nodes"`
+	Now             int64       `json:"now"`
+	Stats           StatsPool   `json:"stats"`
+}
+
+// StatsNode is for decoding json from a successful response of the open-ethereum-pool stats api endpoint
+type StatsNode struct {
+	Difficulty string `json:"difficulty"`
+	Height     string `json:"height"`
+	LastBeat   string `json:"lastBeat"`
+	Name       string `json:"name"`
+}
+
+// StatsPool is for decoding json from a successful response of the open-ethereum-pool stats api endpoint
+type StatsPool struct {
+	LastBlockFound int64 `json:"lastBlockFound"`
+	RoundShares    int64 `json:"roundShares"`
+}
+
+// Blocks is for decoding json from a successful response of the open-ethereum-pool blocks api endpoint
+type Blocks struct {
+	Candidates      []Block `json:"candidates"`
+	CandidatesTotal int64   `json:"candidatesTotal"`
+	Immature        []Block `json:"immature"`
+	ImmatureTotal   int64   `json:"immatureTotal"`
+	Matured         []Block `json:"matured"`
+	MaturedTotal    int64   `json:"maturedTotal"`
+}
+
+// Block is for decoding json from a successful response of the open-ethereum-pool blocks api endpoint,
+// Reward is in wei
+type Block struct {
+	Height      int64  `json:"height"`
+	Timestamp   int64  `json:"timestamp"`
+	Difficulty  int64  `json:"difficulty"`
+	Shares      int64  `json:"shares"`
+	Uncle       bool   `json:"uncle"`
+	UncleHeight int64  `json:"uncleHeight"`
+	Orphan      bool   `json:"orphan"`
+	Hash        string `json:"hash"`
+	Reward      string `json:"reward"`
+}
@@ -0,0 +1,29 @@
+package openethpool
+
+var (
+	login0x01Account = &Account{
+		CurrentHashrate: 190500000,
+		Hashrate:        185000000,
+		PageSize:        30,
+		Payments: []Payment{
+			{Amount: 100000000, Timestamp: 1609459200, TX: "0x02"},
+		},
+		PaymentsTotal: 1,
+		RoundShares:   1234,
+		Stats: AccountStats{
+			Balance:   142000000, // 0.142
+			Immature:  1500000,   // 0.0015
+			LastShare: 1609459180,
+			Paid:      100000000,
+		},
+		Workers: map[string]AccountWorker{
+			"rig2": {LastBeat: 1609459100, HR: 0, Offline: true, HR2: 40000000},
+			"rig1": {LastBeat: 1609459200, HR: 95500000, HR2: 90000000},
+		},
+		WorkersOffline: 1,
+		WorkersOnline:  1,
+		WorkersTotal:   2,
+		Reward24h:      9000000, // 0.009
+		NumReward24h:   42,
+	}
+)
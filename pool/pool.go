@@ -0,0 +1,189 @@
+// Package pool describes a mining pool account in terms every pool API can answer, so the metrics command can watch
+// accounts on several pools without knowing which API each one speaks
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Pool is a mining pool API, every method takes the account (usually the payout address) to report on.
+// Amounts are in whole coins of the pool's coin and hashrates are in hashes (or solutions) per second.
+// Methods whose data the pool does not publish return an error matching ErrUnsupported
+type Pool interface {
+	// Coin returns the ticker of the coin mined on the pool, e.g. eth, to tell apart the stats of different coins
+	Coin() string
+	Balance(ctx context.Context, account string) (Balance, error)
+	Hashrate(ctx context.Context, account string) (Hashrate, error)
+	Workers(ctx context.Context, account string) ([]Worker, error)
+	ShareHistory(ctx context.Context, account string) ([]ShareSlot, error)
+	Payments(ctx context.Context, account string) ([]Payment, error)
+	PayoutThreshold(ctx context.Context, account string) (*big.Rat, error)
+}
+
+// ShareCounter is implemented by pools that report stale and invalid shares alongside valid ones
+type ShareCounter interface {
+	ShareCounts(ctx context.Context, account string) (ShareCounts, error)
+}
+
+// ReportedHashrateReader is implemented by pools that pass on the hashrate the mining software reports, so it can be
+// compared with the effective hashrate from Pool.Hashrate
+type ReportedHashrateReader interface {
+	// ReportedHashrate returns the hashrate the account's rigs report in hashes per second
+	ReportedHashrate(ctx context.Context, account string) (float64, error)
+}
+
+// EarningsEstimator is implemented by pools that estimate what an account earns at its current hashrate
+type EarningsEstimator interface {
+	// EstimatedDailyEarnings returns the coins the account is expected to earn in a day
+	EstimatedDailyEarnings(ctx context.Context, account string) (float64, error)
+}
+
+// WorkerCounter is implemented by pools that count the online and offline workers of an account
+type WorkerCounter interface {
+	WorkerCounts(ctx context.Context, account string) (WorkerCounts, error)
+}
+
+// RewardReporter is implemented by pools that report what an account was credited over the last day
+type RewardReporter interface {
+	// Reward24h returns the coins credited to the account in the last 24 hours
+	Reward24h(ctx context.Context, account string) (*big.Rat, error)
+}
+
+// PriceReader is implemented by pools that publish the price of the coin they mine, some only alongside an account's
+// stats so the account to ask about is passed as every Pool method takes it
+type PriceReader interface {
+	// Prices returns what one coin is worth in other currencies
+	Prices(ctx context.Context, account string) (Prices, error)
+}
+
+var (
+	// ErrAccountNotFound is matched by the errors of a Pool when the pool does not know the account
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrRateLimited is matched by the errors of a Pool when the pool rejected a request for exceeding its request budget
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnsupported is matched by the errors of Pool methods whose data the pool does not publish
+	ErrUnsupported = errors.New("unsupported by pool")
+)
+
+// Balance is what the pool owes an account, Unconfirmed is credited for blocks that have not matured yet
+type Balance struct {
+	Confirmed   *big.Rat
+	Unconfirmed *big.Rat
+}
+
+// Hashrate is the hashrate the pool sees from an account, Average covers the pool's longest window, usually 24 hours
+type Hashrate struct {
+	Current float64
+	Average float64
+}
+
+// Worker is one rig mining to an account as the pool sees it
+type Worker struct {
+	Name            string
+	Hashrate        float64
+	AverageHashrate float64
+	LastShare       time.Time
+}
+
+// ShareSlot is the number of shares the pool accepted from an account in the period starting at Time
+type ShareSlot struct {
+	Time   time.Time
+	Shares int64
+}
+
+// ShareCounts are the shares a pool counted for an account over its reporting window, usually the last hour
+type ShareCounts struct {
+	Valid   int64
+	Stale   int64
+	Invalid int64
+}
+
+// WorkerCounts are the workers of an account the pool has recently seen shares from, and those it has not
+type WorkerCounts struct {
+	Online  int64
+	Offline int64
+}
+
+// Prices are what one coin of a pool is worth in USD and BTC, a price the pool does not publish is nil
+type Prices struct {
+	USD *big.Rat
+	BTC *big.Rat
+}
+
+// Payment is a payout the pool sent to an account
+type Payment struct {
+	Time      time.Time
+	TXHash    string
+	Amount    *big.Rat
+	Confirmed bool
+}
+
+// Config is one entry of miningtools.pools, the accounts to watch on a pool and how to reach its API
+type Config struct {
+	// Name tags the metrics of the pool, it defaults to Type
+	Name string `mapstructure:"name"`
+	// Type selects the registered Factory, e.g. nanopool
+	Type string `mapstructure:"type"`
+	// Coin is the coin mined on the pool, for pools that run one API per coin
+	Coin string `mapstructure:"coin"`
+	// APIRoot overrides the pool's public API
+	APIRoot  string   `mapstructure:"apiRoot"`
+	Accounts []string `mapstructure:"accounts"`
+}
+
+// Factory builds a Pool from its config
+type Factory func(config Config) (Pool, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a Factory available to Open under name, it panics if name is registered twice or factory is nil
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if factory == nil {
+		panic("pool: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic("pool: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// Types returns the sorted names of the registered factories
+func Types() (types []string) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	for name := range factories {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return
+}
+
+// Open builds the Pool described by config with the Factory registered for config.Type
+func Open(config Config) (pool Pool, err error) {
+	factoriesMu.RLock()
+	factory, ok := factories[config.Type]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown pool type %q, expected one of %v", config.Type, Types())
+	}
+	return factory(config)
+}
+
+// Label returns the name used to tag the pool's metrics
+func (c Config) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Type
+}
@@ -0,0 +1,112 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type stubPool struct {
+	config Config
+}
+
+func (sp *stubPool) Coin() string {
+	return sp.config.Coin
+}
+
+func (sp *stubPool) Balance(ctx context.Context, account string) (balance Balance, err error) {
+	return
+}
+
+func (sp *stubPool) Hashrate(ctx context.Context, account string) (hashrate Hashrate, err error) {
+	return
+}
+
+func (sp *stubPool) Workers(ctx context.Context, account string) (workers []Worker, err error) {
+	return
+}
+
+func (sp *stubPool) ShareHistory(ctx context.Context, account string) (slots []ShareSlot, err error) {
+	return
+}
+
+func (sp *stubPool) Payments(ctx context.Context, account string) (payments []Payment, err error) {
+	return
+}
+
+func (sp *stubPool) PayoutThreshold(ctx context.Context, account string) (threshold *big.Rat, err error) {
+	return
+}
+
+func Test_Open(t *testing.T) {
+	failed := errors.New("Failed")
+	Register("stub", func(config Config) (Pool, error) {
+		return &stubPool{config: config}, nil
+	})
+	Register("broken", func(config Config) (Pool, error) {
+		return nil, failed
+	})
+	tests := []struct {
+		name    string
+		config  Config
+		want    Pool
+		wantErr bool
+	}{
+		{
+			name:   "Success01",
+			config: Config{Name: "main", Type: "stub", Accounts: []string{"0x01"}},
+			want:   &stubPool{config: Config{Name: "main", Type: "stub", Accounts: []string{"0x01"}}},
+		},
+		{name: "FactoryError01", config: Config{Type: "broken"}, wantErr: true},
+		{name: "Unknown01", config: Config{Type: "missing"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Open(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Open() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Open() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	types := Types()
+	if !sort.StringsAreSorted(types) || sort.SearchStrings(types, "stub") == len(types) {
+		t.Errorf("Types() = %v, want a sorted list holding stub", types)
+	}
+}
+
+func Test_Register(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory Factory
+	}{
+		{name: "Duplicate01", factory: func(config Config) (Pool, error) { return nil, nil }},
+		{name: "Nil01", factory: nil},
+	}
+	Register("Duplicate01", tests[0].factory)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register() did not panic")
+				}
+			}()
+			Register(tt.name, tt.factory)
+		})
+	}
+}
+
+func Test_ConfigLabel(t *testing.T) {
+	if got := (Config{Type: "nanopool"}).Label(); got != "nanopool" {
+		t.Errorf("Config.Label() = %v, want %v", got, "nanopool")
+	}
+	if got := (Config{Name: "eth-main", Type: "nanopool"}).Label(); got != "eth-main" {
+		t.Errorf("Config.Label() = %v, want %v", got, "eth-main")
+	}
+}
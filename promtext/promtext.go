@@ -0,0 +1,150 @@
+// Package promtext writes metrics in the Prometheus text exposition format, version 0.0.4, which Prometheus and the
+// agents compatible with it scrape
+package promtext
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the Content-Type of a response holding the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Type is the kind of metric a Family holds
+type Type string
+
+const (
+	// Gauge is a value that goes up and down
+	Gauge Type = "gauge"
+	// Counter is a value that only goes up, until the process counting it restarts
+	Counter Type = "counter"
+	// Untyped is a value of unknown kind
+	Untyped Type = "untyped"
+)
+
+// ErrInvalidMetric is matched by the errors of families that cannot be written
+var ErrInvalidMetric = errors.New("invalid metric")
+
+var (
+	metricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelName  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// Label is a name and value telling the samples of a family apart
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is one value of a family
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Family is every sample of one metric, written under a single HELP and TYPE line
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []Sample
+}
+
+// NewFamily returns an empty Family
+func NewFamily(name string, metricType Type, help string) *Family {
+	return &Family{Name: name, Help: help, Type: metricType}
+}
+
+// Add adds a sample of value labelled with name, value pairs, e.g. Add(1.5, "worker", "rig1")
+func (f *Family) Add(value float64, labels ...string) *Family {
+	sample := Sample{Value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		sample.Labels = append(sample.Labels, Label{Name: labels[i], Value: labels[i+1]})
+	}
+	f.Samples = append(f.Samples, sample)
+	return f
+}
+
+// Encode writes families to w, leaving out families without samples. Names are checked before anything is written so
+// a bad family does not leave half a response
+func Encode(w io.Writer, families []*Family) (err error) {
+	seen := make(map[string]bool)
+	for _, f := range families {
+		if err = f.validate(); err != nil {
+			return
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("%w: %s is written twice", ErrInvalidMetric, f.Name)
+		}
+		seen[f.Name] = true
+	}
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		if len(f.Samples) == 0 {
+			continue
+		}
+		if f.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, helpEscaper.Replace(f.Help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			bw.WriteString(f.Name)
+			if len(s.Labels) > 0 {
+				bw.WriteByte('{')
+				for i, l := range s.Labels {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					fmt.Fprintf(bw, `%s="%s"`, l.Name, labelEscaper.Replace(l.Value))
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteByte(' ')
+			bw.WriteString(FormatValue(s.Value))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// FormatValue renders a sample value the way Prometheus parses it, including NaN and the infinities
+func FormatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// validate checks the names of f and its labels and its type
+func (f *Family) validate() error {
+	if !metricName.MatchString(f.Name) {
+		return fmt.Errorf("%w: invalid metric name %q", ErrInvalidMetric, f.Name)
+	}
+	switch f.Type {
+	case Gauge, Counter, Untyped:
+	default:
+		return fmt.Errorf("%w: %s has unknown type %q", ErrInvalidMetric, f.Name, f.Type)
+	}
+	for _, s := range f.Samples {
+		for _, l := range s.Labels {
+			if !labelName.MatchString(l.Name) || strings.HasPrefix(l.Name, "__") {
+				return fmt.Errorf("%w: %s has invalid label name %q", ErrInvalidMetric, f.Name, l.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package promtext
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_Encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		families []*Family
+		want     string
+		wantErr  error
+	}{
+		{
+			name: "Success01",
+			families: []*Family{
+				NewFamily("mining_pool_balance_coins", Gauge, "Confirmed balance of the account").Add(0.142, "coin", "eth", "account", "0x01"),
+				NewFamily("mining_worker_hashrate_hashes_per_second", Gauge, "Hashrate of a worker").
+					Add(95500000, "worker", "rig1", "window", "1h").
+					Add(1.5e-7, "worker", "rig2", "window", "1h"),
+				NewFamily("mining_exporter_scrapes_total", Counter, "").Add(3),
+			},
+			want: "# HELP mining_pool_balance_coins Confirmed balance of the account\n" +
+				"# TYPE mining_pool_balance_coins gauge\n" +
+				"mining_pool_balance_coins{coin=\"eth\",account=\"0x01\"} 0.142\n" +
+				"# HELP mining_worker_hashrate_hashes_per_second Hashrate of a worker\n" +
+				"# TYPE mining_worker_hashrate_hashes_per_second gauge\n" +
+				"mining_worker_hashrate_hashes_per_second{worker=\"rig1\",window=\"1h\"} 9.55e+07\n" +
+				"mining_worker_hashrate_hashes_per_second{worker=\"rig2\",window=\"1h\"} 1.5e-07\n" +
+				"# TYPE mining_exporter_scrapes_total counter\n" +
+				"mining_exporter_scrapes_total 3\n",
+		},
+		{
+			name: "Escaping01",
+			families: []*Family{
+				NewFamily("m", Untyped, "back\\slash and\nnewline").Add(math.Inf(1), "worker", "rig \"1\"\\\n"),
+			},
+			want: "# HELP m back\\\\slash and\\nnewline\n" +
+				"# TYPE m untyped\n" +
+				"m{worker=\"rig \\\"1\\\"\\\\\\n\"} +Inf\n",
+		},
+		{
+			name:     "Empty01",
+			families: []*Family{NewFamily("m", Gauge, "no samples")},
+			want:     "",
+		},
+		{
+			name:     "BadName01",
+			families: []*Family{NewFamily("mining-balance", Gauge, "").Add(1)},
+			wantErr:  ErrInvalidMetric,
+		},
+		{
+			name:     "BadLabel01",
+			families: []*Family{NewFamily("m", Gauge, "").Add(1, "__name__", "x")},
+			wantErr:  ErrInvalidMetric,
+		},
+		{
+			name:     "BadType01",
+			families: []*Family{NewFamily("m", "histogram", "").Add(1)},
+			wantErr:  ErrInvalidMetric,
+		},
+		{
+			name:     "Duplicate01",
+			families: []*Family{NewFamily("m", Gauge, "").Add(1), NewFamily("m", Gauge, "").Add(2)},
+			wantErr:  ErrInvalidMetric,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := Encode(&b, tt.families)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Encode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && b.Len() > 0 {
+				t.Errorf("Encode() wrote %q, want nothing on error", b.String())
+			}
+			if err == nil && b.String() != tt.want {
+				t.Errorf("Encode() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_FormatValue(t *testing.T) {
+	for value, want := range map[float64]string{0: "0", -2.5: "-2.5", 190500000: "1.905e+08", math.Inf(-1): "-Inf"} {
+		if got := FormatValue(value); got != want {
+			t.Errorf("FormatValue(%v) = %s, want %s", value, got, want)
+		}
+	}
+	if got := FormatValue(math.NaN()); got != "NaN" {
+		t.Errorf("FormatValue(NaN) = %s, want NaN", got)
+	}
+}
@@ -0,0 +1,98 @@
+// Package questdb writes points to QuestDB over the InfluxDB line protocol (ILP) TCP listener, which InfluxDB 1.x
+// compatible listeners such as Telegraf's socket_listener accept as well
+package questdb
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"mining-tools/lineprotocol"
+	"mining-tools/sink"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultAddress is the host:port QuestDB's ILP listener binds by default
+	DefaultAddress = "127.0.0.1:9009"
+	// DefaultTimeout is the timeout a Sink applies to each write when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+)
+
+// Dialer is an interface to abstract net.Dialer to support testing
+type Dialer interface {
+	DialContext(ctx context.Context, network string, address string) (conn net.Conn, err error)
+}
+
+// Sink sends each batch of points over a connection of its own, ILP over TCP has no replies so a write that reached
+// the listener is as much as can be known
+type Sink struct {
+	address string
+	dialer  Dialer
+	timeout time.Duration
+}
+
+// Option configures a Sink created by NewSink
+type Option func(*Sink)
+
+// WithDialer sets how connections to the listener are opened
+func WithDialer(dialer Dialer) Option {
+	return func(s *Sink) {
+		s.dialer = dialer
+	}
+}
+
+// WithTimeout sets the timeout applied to each write, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Sink) {
+		s.timeout = timeout
+	}
+}
+
+// NewSink returns a Sink for the ILP listener at address, a host:port, adjusted by options
+func NewSink(address string, options ...Option) *Sink {
+	s := &Sink{
+		address: address,
+		dialer:  &net.Dialer{},
+		timeout: DefaultTimeout,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Address returns the host:port of the listener
+func (s *Sink) Address() string {
+	return s.address
+}
+
+// Write sends points as lines with nanosecond timestamps, the precision ILP over TCP expects
+func (s *Sink) Write(ctx context.Context, points []*lineprotocol.Point) (err error) {
+	payload, err := lineprotocol.Encode(points, lineprotocol.Nanosecond)
+	if err != nil {
+		return
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	conn, err := s.dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		log.Errorf("Sink.Write: DialContext(tcp, %s); returned err=%s\n", s.address, err.Error())
+		return fmt.Errorf("questdb %s: %w: %s", s.address, sink.ErrUnreachable, err.Error())
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	log.Debugf("Sink.Write: sending %d points to %s - %s", len(points), s.address, payload)
+	if _, err = conn.Write(payload); err != nil {
+		log.Errorf("Sink.Write: conn.Write(payload); returned err=%s\n", err.Error())
+		return fmt.Errorf("questdb %s: %w", s.address, err)
+	}
+	return
+}
@@ -0,0 +1,91 @@
+package questdb
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"mining-tools/lineprotocol"
+	"mining-tools/sink"
+)
+
+// listen accepts one connection on a random local port and sends everything written to it on the returned channel
+func listen(t *testing.T) (address string, received chan []byte) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	received = make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		payload, _ := ioutil.ReadAll(conn)
+		received <- payload
+	}()
+	return listener.Addr().String(), received
+}
+
+func Test_Write(t *testing.T) {
+	address, received := listen(t)
+	at := time.Unix(1609459200, 0).UTC()
+	points := []*lineprotocol.Point{
+		lineprotocol.NewPoint("rig", at).AddTag("Rig", "rig 1").AddField("Hashrate", 95500000.0),
+		lineprotocol.NewPoint("gpu", at).AddTag("GPU", "0").AddField("AcceptedShares", 95),
+	}
+	if err := NewSink(address, WithTimeout(time.Second)).Write(context.Background(), points); err != nil {
+		t.Fatalf("Sink.Write() error = %v", err)
+	}
+	want := "rig,Rig=rig\\ 1 Hashrate=95500000 1609459200000000000\ngpu,GPU=0 AcceptedShares=95i 1609459200000000000\n"
+	select {
+	case payload := <-received:
+		if string(payload) != want {
+			t.Errorf("Sink.Write() sent %q, want %q", payload, want)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Sink.Write() sent nothing")
+	}
+}
+
+func Test_WriteErrors(t *testing.T) {
+	address, _ := listen(t)
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	closed.Close()
+	tests := []struct {
+		name    string
+		address string
+		points  []*lineprotocol.Point
+		wantErr error
+	}{
+		{
+			name:    "Unreachable01",
+			address: closed.Addr().String(),
+			points:  []*lineprotocol.Point{lineprotocol.NewPoint("m", time.Time{}).AddField("f", 1.0)},
+			wantErr: sink.ErrUnreachable,
+		},
+		{
+			name:    "InvalidPoint01",
+			address: address,
+			points:  []*lineprotocol.Point{lineprotocol.NewPoint("m", time.Time{})},
+			wantErr: lineprotocol.ErrInvalidPoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSink(tt.address).Write(context.Background(), tt.points)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Sink.Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
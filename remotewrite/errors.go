@@ -0,0 +1,73 @@
+package remotewrite
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"mining-tools/sink"
+)
+
+var (
+	// ErrUnauthorized is returned when the receiver rejected the credentials or the tenant
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRejected is returned when the receiver refused the samples, e.g. as out of order or for bad labels, sending
+	// them again fails the same way
+	ErrRejected = errors.New("samples rejected")
+	// ErrRateLimited is returned when the receiver rejected the write for exceeding an ingestion limit
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer is returned when the receiver answered with a 5xx status
+	ErrServer = errors.New("server error")
+	// ErrUnreachable is returned when the receiver could not be reached
+	ErrUnreachable = errors.New("unreachable")
+	// ErrRequestFailed is returned for any other status than 2xx
+	ErrRequestFailed = errors.New("request failed")
+	// ErrInvalidLabel is returned by NewSink when WithLabels maps a tag to a name Prometheus does not accept
+	ErrInvalidLabel = errors.New("invalid label name")
+)
+
+// APIError describes a failed write, Err is one of the Err* sentinels above so callers can branch with errors.Is.
+// Message is the body the receiver answered with
+type APIError struct {
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("remote write: %s (HTTP %d)", e.Err, e.StatusCode)
+	}
+	return fmt.Sprintf("remote write: %s (HTTP %d): %s", e.Err, e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error describing the kind of failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets callers match sink.ErrUnreachable, so code written against sink.Sink can tell a down receiver apart
+func (e *APIError) Is(target error) bool {
+	return target == sink.ErrUnreachable && e.Err == ErrUnreachable
+}
+
+// checkResponse turns the status of a write in to an *APIError, or nil if the samples were written
+func checkResponse(statusCode int, message string) (err error) {
+	if statusCode >= 200 && statusCode < 300 {
+		return nil
+	}
+	apiErr := &APIError{StatusCode: statusCode, Message: message}
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		apiErr.Err = ErrUnauthorized
+	case statusCode == http.StatusTooManyRequests:
+		apiErr.Err = ErrRateLimited
+	case statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError:
+		apiErr.Err = ErrRejected
+	case statusCode >= http.StatusInternalServerError:
+		apiErr.Err = ErrServer
+	default:
+		apiErr.Err = ErrRequestFailed
+	}
+	return apiErr
+}
@@ -0,0 +1,97 @@
+package remotewrite
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// WriteRequest is the prometheus.WriteRequest message of the remote write protocol, version 0.1.0, without the
+// metadata receivers treat as optional
+type WriteRequest struct {
+	Timeseries []TimeSeries
+}
+
+// TimeSeries is one series, Labels must be sorted by name and include __name__, Samples sorted by time
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// Label is a name and value identifying a series
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a value at a time in milliseconds since the Unix epoch
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+const (
+	wireVarint = 0
+	wire64Bit  = 1
+	wireBytes  = 2
+)
+
+// Marshal encodes r in the protobuf wire format. Fields holding their zero value are left out as proto3 encoders do
+func (r *WriteRequest) Marshal() []byte {
+	var dst []byte
+	for _, ts := range r.Timeseries {
+		dst = appendBytes(dst, 1, ts.marshal())
+	}
+	return dst
+}
+
+func (ts *TimeSeries) marshal() []byte {
+	var dst []byte
+	for _, l := range ts.Labels {
+		var label []byte
+		label = appendString(label, 1, l.Name)
+		label = appendString(label, 2, l.Value)
+		dst = appendBytes(dst, 1, label)
+	}
+	for _, s := range ts.Samples {
+		var sample []byte
+		if s.Value != 0 || math.Signbit(s.Value) {
+			sample = appendTag(sample, 1, wire64Bit)
+			sample = appendFixed64(sample, math.Float64bits(s.Value))
+		}
+		if s.Timestamp != 0 {
+			sample = appendTag(sample, 2, wireVarint)
+			sample = appendVarint(sample, uint64(s.Timestamp))
+		}
+		dst = appendBytes(dst, 2, sample)
+	}
+	return dst
+}
+
+func appendTag(dst []byte, field int, wireType int) []byte {
+	return appendVarint(dst, uint64(field)<<3|uint64(wireType))
+}
+
+func appendBytes(dst []byte, field int, b []byte) []byte {
+	dst = appendTag(dst, field, wireBytes)
+	dst = appendVarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
+
+func appendString(dst []byte, field int, s string) []byte {
+	if s == "" {
+		return dst
+	}
+	return appendBytes(dst, field, []byte(s))
+}
+
+func appendFixed64(dst []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(dst, b[:]...)
+}
+
+func appendVarint(dst []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(dst, b[:n]...)
+}
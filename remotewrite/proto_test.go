@@ -0,0 +1,145 @@
+package remotewrite
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+var errTruncated = errors.New("truncated message")
+
+// unmarshal decodes a WriteRequest the way receivers do, skipping unknown fields
+func unmarshal(b []byte) (r WriteRequest, err error) {
+	err = eachField(b, func(field uint64, value []byte, _ uint64) error {
+		if field != 1 {
+			return nil
+		}
+		var ts TimeSeries
+		err := eachField(value, func(field uint64, value []byte, _ uint64) error {
+			switch field {
+			case 1:
+				var l Label
+				err := eachField(value, func(field uint64, value []byte, _ uint64) error {
+					if field == 1 {
+						l.Name = string(value)
+					} else if field == 2 {
+						l.Value = string(value)
+					}
+					return nil
+				})
+				ts.Labels = append(ts.Labels, l)
+				return err
+			case 2:
+				var s Sample
+				err := eachField(value, func(field uint64, value []byte, varint uint64) error {
+					if field == 1 {
+						s.Value = math.Float64frombits(binary.LittleEndian.Uint64(value))
+					} else if field == 2 {
+						s.Timestamp = int64(varint)
+					}
+					return nil
+				})
+				ts.Samples = append(ts.Samples, s)
+				return err
+			}
+			return nil
+		})
+		r.Timeseries = append(r.Timeseries, ts)
+		return err
+	})
+	return
+}
+
+// eachField calls fn with the number and value of each field of a message
+func eachField(b []byte, fn func(field uint64, value []byte, varint uint64) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+		b = b[n:]
+		var value []byte
+		var varint uint64
+		switch tag & 7 {
+		case wireVarint:
+			varint, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errTruncated
+			}
+			b = b[n:]
+		case wire64Bit:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			value, b = b[:8], b[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return errTruncated
+			}
+			value, b = b[n:n+int(length)], b[n+int(length):]
+		default:
+			return errTruncated
+		}
+		if err := fn(tag>>3, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Test_Marshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		request WriteRequest
+		want    []byte
+	}{
+		{
+			name: "Success01",
+			request: WriteRequest{Timeseries: []TimeSeries{{
+				Labels:  []Label{{Name: "a", Value: "b"}},
+				Samples: []Sample{{Value: 1, Timestamp: 300}},
+			}}},
+			want: []byte{
+				0x0a, 0x16, // timeseries, 22 bytes
+				0x0a, 0x06, 0x0a, 0x01, 'a', 0x12, 0x01, 'b', // label a=b
+				0x12, 0x0c, 0x09, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0x10, 0xac, 0x02, // sample 1 at 300
+			},
+		},
+		{
+			name: "ZeroValues01",
+			request: WriteRequest{Timeseries: []TimeSeries{{
+				Labels:  []Label{{Name: "a"}},
+				Samples: []Sample{{}},
+			}}},
+			want: []byte{0x0a, 0x07, 0x0a, 0x03, 0x0a, 0x01, 'a', 0x12, 0x00},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.request.Marshal()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WriteRequest.Marshal() = % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MarshalRoundTrip(t *testing.T) {
+	want := WriteRequest{Timeseries: []TimeSeries{
+		{
+			Labels:  []Label{{Name: "__name__", Value: "mining_pool_balance"}, {Name: "location", Value: "nanopool"}},
+			Samples: []Sample{{Value: 0.142, Timestamp: 1609459200000}, {Value: -1.5, Timestamp: 1609459260000}},
+		},
+		{
+			Labels:  []Label{{Name: "__name__", Value: "mining_rig_hashrate"}},
+			Samples: []Sample{{Value: math.Inf(1), Timestamp: -1}},
+		},
+	}}
+	got, err := unmarshal(want.Marshal())
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("unmarshal(Marshal()) = %+v, %v, want %+v", got, err, want)
+	}
+}
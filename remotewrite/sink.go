@@ -0,0 +1,302 @@
+// Package remotewrite pushes points to receivers of the Prometheus remote write protocol, such as Prometheus with
+// --web.enable-remote-write-receiver, Mimir, Cortex, Thanos and VictoriaMetrics. Each numeric field of a point becomes
+// a series named <namespace>_<measurement>_<field> labelled with the point's tags
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"mining-tools/lineprotocol"
+
+	"github.com/golang/snappy"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultURL is the receiver of a local Prometheus started with --web.enable-remote-write-receiver
+	DefaultURL = "http://127.0.0.1:9090/api/v1/write"
+	// DefaultNamespace prefixes the name of every series unless WithNamespace sets another one
+	DefaultNamespace = "mining"
+	// DefaultTimeout is the per-request timeout a Sink applies when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+	// Version is the version of the protocol sent in X-Prometheus-Remote-Write-Version
+	Version = "0.1.0"
+)
+
+// HTTPClient is an interface to abstract http.client to support testing using mocks
+type HTTPClient interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+var (
+	apiClient = HTTPClient(&http.Client{})
+
+	invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	validLabelName   = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Sink writes points to one remote write endpoint
+type Sink struct {
+	url            string
+	namespace      string
+	labels         map[string]string
+	externalLabels map[string]string
+	bearerToken    string
+	username       string
+	password       string
+	tenant         string
+	httpClient     HTTPClient
+	timeout        time.Duration
+	now            func() time.Time
+	err            error
+}
+
+// Option configures a Sink created by NewSink
+type Option func(*Sink)
+
+// WithNamespace sets the prefix of every series name, an empty namespace leaves names as <measurement>_<field>
+func WithNamespace(namespace string) Option {
+	return func(s *Sink) {
+		s.namespace = namespace
+	}
+}
+
+// WithLabels renames tags to labels, e.g. {"Location": "pool_type"}, an empty label drops the tag. Tags are matched
+// regardless of case, unmapped tags are converted to snake_case. NewSink fails with ErrInvalidLabel for a label that
+// is not a valid Prometheus name or starts with __, which is reserved for names such as __name__
+func WithLabels(labels map[string]string) Option {
+	return func(s *Sink) {
+		for tag, label := range labels {
+			if label != "" && (!validLabelName.MatchString(label) || strings.HasPrefix(label, "__")) {
+				s.err = fmt.Errorf("tag %s mapped to %q: %w", tag, label, ErrInvalidLabel)
+				return
+			}
+			s.labels[strings.ToLower(tag)] = label
+		}
+	}
+}
+
+// WithExternalLabels adds labels to every series that does not already carry them, such as job or instance
+func WithExternalLabels(labels map[string]string) Option {
+	return func(s *Sink) {
+		s.externalLabels = labels
+	}
+}
+
+// WithBearerToken sets the token sent as Authorization: Bearer with every write
+func WithBearerToken(token string) Option {
+	return func(s *Sink) {
+		s.bearerToken = token
+	}
+}
+
+// WithBasicAuth sets the username and password sent with every write, as hosted receivers usually require
+func WithBasicAuth(username string, password string) Option {
+	return func(s *Sink) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithTenant sets the X-Scope-OrgID header multi-tenant receivers such as Mimir and Cortex file the samples under
+func WithTenant(tenant string) Option {
+	return func(s *Sink) {
+		s.tenant = tenant
+	}
+}
+
+// WithHTTPClient sets the transport used for every request
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(s *Sink) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout applied to each request, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Sink) {
+		s.timeout = timeout
+	}
+}
+
+// NewSink returns a Sink writing to the remote write endpoint at writeURL, e.g.
+// http://127.0.0.1:9090/api/v1/write, adjusted by options, err is set when an option was given an invalid value
+func NewSink(writeURL string, options ...Option) (s *Sink, err error) {
+	s = &Sink{
+		url:        writeURL,
+		namespace:  DefaultNamespace,
+		labels:     make(map[string]string),
+		httpClient: apiClient,
+		timeout:    DefaultTimeout,
+		now:        time.Now,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return
+}
+
+// URL returns the remote write endpoint
+func (s *Sink) URL() string {
+	return s.url
+}
+
+// Write sends every numeric field of points in one request. Points without a time are stamped with the time of the
+// write, as receivers need a timestamp for every sample
+func (s *Sink) Write(ctx context.Context, points []*lineprotocol.Point) (err error) {
+	request := s.writeRequest(points)
+	if len(request.Timeseries) == 0 {
+		log.Debugln("Sink.Write: no numeric fields to write")
+		return nil
+	}
+	return s.post(ctx, snappy.Encode(nil, request.Marshal()))
+}
+
+// writeRequest converts points to series, samples of the same series are merged and sorted by time as receivers
+// reject out of order samples
+func (s *Sink) writeRequest(points []*lineprotocol.Point) (request WriteRequest) {
+	now := s.now()
+	index := make(map[string]int)
+	for _, p := range points {
+		at := p.Time
+		if at.IsZero() {
+			at = now
+		}
+		labels := s.pointLabels(p)
+		for _, f := range p.Fields {
+			value, ok := f.Float64()
+			if !ok {
+				continue
+			}
+			series := append([]Label{{Name: "__name__", Value: s.seriesName(p.Measurement, f.Key)}}, labels...)
+			sort.Slice(series, func(i, j int) bool { return series[i].Name < series[j].Name })
+			key := seriesKey(series)
+			i, seen := index[key]
+			if !seen {
+				i = len(request.Timeseries)
+				index[key] = i
+				request.Timeseries = append(request.Timeseries, TimeSeries{Labels: series})
+			}
+			request.Timeseries[i].Samples = append(request.Timeseries[i].Samples,
+				Sample{Value: value, Timestamp: at.UnixNano() / int64(time.Millisecond)})
+		}
+	}
+	for i := range request.Timeseries {
+		samples := request.Timeseries[i].Samples
+		sort.SliceStable(samples, func(a, b int) bool { return samples[a].Timestamp < samples[b].Timestamp })
+	}
+	return
+}
+
+// pointLabels maps the tags of p to labels and adds the external labels the tags do not already set
+func (s *Sink) pointLabels(p *lineprotocol.Point) (labels []Label) {
+	set := make(map[string]bool)
+	for _, t := range p.Tags {
+		name, mapped := s.labels[strings.ToLower(t.Key)]
+		if !mapped {
+			name = snakeCase(t.Key)
+		}
+		if name == "" || set[name] {
+			continue
+		}
+		set[name] = true
+		labels = append(labels, Label{Name: name, Value: t.Value})
+	}
+	for name, value := range s.externalLabels {
+		if !set[name] && value != "" {
+			labels = append(labels, Label{Name: name, Value: value})
+		}
+	}
+	return
+}
+
+// seriesName builds <namespace>_<measurement>_<field> in snake_case
+func (s *Sink) seriesName(measurement string, field string) string {
+	name := snakeCase(measurement) + "_" + snakeCase(field)
+	if s.namespace != "" {
+		name = s.namespace + "_" + name
+	}
+	return name
+}
+
+// post sends one snappy compressed WriteRequest
+func (s *Sink) post(ctx context.Context, payload []byte) (err error) {
+	log.Debugf("Sink.post(url=%s, %d bytes) called\n", s.url, len(payload))
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		log.Errorf("Sink.post: http.NewRequestWithContext(POST, %s); returned err=%s\n", s.url, err.Error())
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("User-Agent", "mining-tools")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", Version)
+	switch {
+	case s.bearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+s.bearerToken)
+	case s.username != "":
+		req.SetBasicAuth(s.username, s.password)
+	}
+	if s.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", s.tenant)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		log.Errorf("Sink.post: httpClient.Do(%s); returned err=%s\n", s.url, err.Error())
+		return &APIError{Message: err.Error(), Err: ErrUnreachable}
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("Sink.post: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return &APIError{StatusCode: resp.StatusCode, Message: err.Error(), Err: ErrUnreachable}
+	}
+	return checkResponse(resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// seriesKey identifies a sorted label set
+func seriesKey(labels []Label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		fmt.Fprintf(&b, "%s\xff%s\xff", l.Name, l.Value)
+	}
+	return b.String()
+}
+
+// snakeCase converts a CamelCase name such as EthereumUSD to a valid Prometheus name such as ethereum_usd
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	snake := invalidNameChars.ReplaceAllString(b.String(), "_")
+	if snake != "" && unicode.IsDigit(rune(snake[0])) {
+		snake = "_" + snake
+	}
+	return snake
+}
@@ -0,0 +1,206 @@
+package remotewrite
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"mining-tools/lineprotocol"
+	"mining-tools/sink"
+
+	"github.com/golang/snappy"
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+// fakeReceiver decodes the write requests it receives and answers each with status, 204 if unset
+type fakeReceiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	writes   []WriteRequest
+	status   int
+	body     string
+}
+
+func (f *fakeReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	compressed, _ := ioutil.ReadAll(r.Body)
+	payload, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	write, err := unmarshal(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r)
+	f.writes = append(f.writes, write)
+	if f.status == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(f.status)
+	w.Write([]byte(f.body))
+}
+
+func Test_Write(t *testing.T) {
+	fake := &fakeReceiver{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	at := time.Unix(1609459200, 0).UTC()
+	now := at.Add(time.Minute)
+	points := []*lineprotocol.Point{
+		lineprotocol.NewPoint("pool", at.Add(time.Second)).AddTag("Location", "nanopool").AddTag("Account", "0x01").
+			AddField("Balance", 0.142).AddField("Shares", int64(12)),
+		lineprotocol.NewPoint("pool", at).AddTag("Location", "nanopool").AddTag("Account", "0x01").
+			AddField("Balance", 0.14),
+		lineprotocol.NewPoint("financial", time.Time{}).AddTag("Location", "wallet").
+			AddField("EthereumUSD", big.NewRat(73051, 100)),
+		lineprotocol.NewPoint("rig", at).AddTag("Rig", "rig1").AddField("Miner", "ethminer-0.19.0"),
+	}
+	s, err := NewSink(server.URL+"/api/v1/write",
+		WithLabels(map[string]string{"location": "pool_type", "Account": ""}),
+		WithExternalLabels(map[string]string{"job": "mining-tools", "pool_type": "ignored"}),
+		WithBasicAuth("1234", "secret"),
+		WithTenant("miners"),
+	)
+	if err != nil {
+		t.Fatalf("NewSink() error = %v", err)
+	}
+	s.now = func() time.Time { return now }
+	if err := s.Write(context.Background(), points); err != nil {
+		t.Fatalf("Sink.Write() error = %v", err)
+	}
+	if len(fake.requests) != 1 {
+		t.Fatalf("Sink.Write() sent %d requests, want 1", len(fake.requests))
+	}
+	r := fake.requests[0]
+	user, password, _ := r.BasicAuth()
+	if r.Method != http.MethodPost || r.URL.Path != "/api/v1/write" || r.Header.Get("Content-Encoding") != "snappy" ||
+		r.Header.Get("Content-Type") != "application/x-protobuf" || r.Header.Get("X-Prometheus-Remote-Write-Version") != Version ||
+		r.Header.Get("X-Scope-OrgID") != "miners" || user != "1234" || password != "secret" {
+		t.Errorf("Sink.Write() requested %s %s with headers %v", r.Method, r.URL, r.Header)
+	}
+	want := WriteRequest{Timeseries: []TimeSeries{
+		{
+			Labels:  []Label{{Name: "__name__", Value: "mining_pool_balance"}, {Name: "job", Value: "mining-tools"}, {Name: "pool_type", Value: "nanopool"}},
+			Samples: []Sample{{Value: 0.14, Timestamp: 1609459200000}, {Value: 0.142, Timestamp: 1609459201000}},
+		},
+		{
+			Labels:  []Label{{Name: "__name__", Value: "mining_pool_shares"}, {Name: "job", Value: "mining-tools"}, {Name: "pool_type", Value: "nanopool"}},
+			Samples: []Sample{{Value: 12, Timestamp: 1609459201000}},
+		},
+		{
+			Labels:  []Label{{Name: "__name__", Value: "mining_financial_ethereum_usd"}, {Name: "job", Value: "mining-tools"}, {Name: "pool_type", Value: "wallet"}},
+			Samples: []Sample{{Value: 730.51, Timestamp: 1609459260000}},
+		},
+	}}
+	if !reflect.DeepEqual(fake.writes[0], want) {
+		t.Errorf("Sink.Write() wrote %+v, want %+v", fake.writes[0], want)
+	}
+}
+
+func Test_NewSinkInvalidLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		wantErr error
+	}{
+		{name: "Success01", labels: map[string]string{"Location": "pool_type", "Account": "", "Rig": "_rig2"}},
+		{name: "Failure01", labels: map[string]string{"Location": "pool-type"}, wantErr: ErrInvalidLabel},
+		{name: "Failure02", labels: map[string]string{"Location": "2pool"}, wantErr: ErrInvalidLabel},
+		{name: "Failure03", labels: map[string]string{"Location": "__name__"}, wantErr: ErrInvalidLabel},
+		{name: "Failure04", labels: map[string]string{"Location": "__pool"}, wantErr: ErrInvalidLabel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSink(DefaultURL, WithLabels(tt.labels))
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (s != nil) {
+				t.Errorf("NewSink() = %v, %v, want error %v", s, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_WriteNothing(t *testing.T) {
+	fake := &fakeReceiver{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	points := []*lineprotocol.Point{lineprotocol.NewPoint("rig", time.Now()).AddField("Miner", "ethminer-0.19.0")}
+	s, _ := NewSink(server.URL)
+	if err := s.Write(context.Background(), points); err != nil || len(fake.requests) != 0 {
+		t.Errorf("Sink.Write() = %v after %d requests, want nil after none", err, len(fake.requests))
+	}
+}
+
+func Test_WriteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "Unauthorized01", status: http.StatusUnauthorized, wantErr: ErrUnauthorized},
+		{name: "Rejected01", status: http.StatusBadRequest, wantErr: ErrRejected},
+		{name: "RateLimited01", status: http.StatusTooManyRequests, wantErr: ErrRateLimited},
+		{name: "Server01", status: http.StatusServiceUnavailable, wantErr: ErrServer},
+		{name: "RequestFailed01", status: http.StatusFound, wantErr: ErrRequestFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeReceiver{status: tt.status, body: "out of order sample\n"}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			points := []*lineprotocol.Point{lineprotocol.NewPoint("pool", time.Now()).AddField("Balance", 0.142)}
+			s, _ := NewSink(server.URL, WithHTTPClient(&http.Client{
+				CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+			}))
+			err := s.Write(context.Background(), points)
+			var apiErr *APIError
+			if !errors.Is(err, tt.wantErr) || !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status ||
+				apiErr.Message != "out of order sample" {
+				t.Errorf("Sink.Write() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_WriteUnreachable(t *testing.T) {
+	server := httptest.NewServer(&fakeReceiver{})
+	server.Close()
+	points := []*lineprotocol.Point{lineprotocol.NewPoint("pool", time.Now()).AddField("Balance", 0.142)}
+	s, _ := NewSink(server.URL, WithTimeout(time.Second))
+	err := s.Write(context.Background(), points)
+	if !errors.Is(err, ErrUnreachable) || !errors.Is(err, sink.ErrUnreachable) {
+		t.Errorf("Sink.Write() error = %v, want %v", err, sink.ErrUnreachable)
+	}
+}
+
+func Test_snakeCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"Balance":                "balance",
+		"EthereumUSD":            "ethereum_usd",
+		"BalanceBTC":             "balance_btc",
+		"EstimatedDailyEarnings": "estimated_daily_earnings",
+		"GPU":                    "gpu",
+		"Reward24h":              "reward24h",
+		"24h rate":               "_24h_rate",
+		"pool-type":              "pool_type",
+	} {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package rig describes what mining software reports about the rig it runs on, so the metrics command can watch rigs
+// running different miners next to the pools they mine to
+package rig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Rig is the API of the mining software running on one rig. Hashrates are in hashes (or solutions) per second,
+// temperatures in degrees Celsius, fan speeds in percent and power in watts, a value the miner does not report is zero
+type Rig interface {
+	Stats(ctx context.Context) (Stats, error)
+}
+
+// ErrUnreachable is matched by the errors of a Rig when its API could not be reached, usually because the rig or the
+// mining software is down
+var ErrUnreachable = errors.New("rig unreachable")
+
+// Stats is what the mining software reports about the whole rig
+type Stats struct {
+	// Miner is the mining software and its version, e.g. ethminer-0.19.0
+	Miner string
+	// Worker is the worker name the mining software sends to the pool, for mining software that reports it
+	Worker   string
+	Uptime   time.Duration
+	Hashrate float64
+	Power    float64
+	Shares   Shares
+	// PoolSwitches counts the times the mining software switched pools since it started, usually after losing its
+	// connection
+	PoolSwitches int64
+	GPUs         []GPU
+}
+
+// GPU is what the mining software reports about one of the rig's devices
+type GPU struct {
+	// Index is the device number the mining software uses in its own logs
+	Index             int
+	Name              string
+	Hashrate          float64
+	Temperature       float64
+	MemoryTemperature float64
+	FanSpeed          float64
+	Power             float64
+	Shares            Shares
+}
+
+// Shares are the shares submitted since the mining software started, Invalid shares were rejected as wrong rather
+// than stale
+type Shares struct {
+	Accepted int64
+	Rejected int64
+	Invalid  int64
+}
+
+// Config is one entry of miningtools.rigs, the API of the mining software on a rig
+type Config struct {
+	// Name tags the metrics of the rig, it defaults to Address
+	Name string `mapstructure:"name"`
+	// Type selects the registered Factory, e.g. ethminer
+	Type string `mapstructure:"type"`
+	// Address is the host:port the API listens on
+	Address string `mapstructure:"address"`
+	// Password is sent to APIs started with a password
+	Password string `mapstructure:"password"`
+	// Worker is the worker name the pool knows the rig by, it defaults to the one the mining software reports
+	Worker string `mapstructure:"worker"`
+}
+
+// Factory builds a Rig from its config
+type Factory func(config Config) (Rig, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a Factory available to Open under name, it panics if name is registered twice or factory is nil
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if factory == nil {
+		panic("rig: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic("rig: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// Types returns the sorted names of the registered factories
+func Types() (types []string) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	for name := range factories {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return
+}
+
+// Open builds the Rig described by config with the Factory registered for config.Type
+func Open(config Config) (rig Rig, err error) {
+	factoriesMu.RLock()
+	factory, ok := factories[config.Type]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown rig type %q, expected one of %v", config.Type, Types())
+	}
+	return factory(config)
+}
+
+// Label returns the name used to tag the rig's metrics
+func (c Config) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Address
+}
@@ -0,0 +1,87 @@
+package rig
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type stubRig struct {
+	config Config
+}
+
+func (sr *stubRig) Stats(ctx context.Context) (stats Stats, err error) {
+	return
+}
+
+func Test_Open(t *testing.T) {
+	failed := errors.New("Failed")
+	Register("stub", func(config Config) (Rig, error) {
+		return &stubRig{config: config}, nil
+	})
+	Register("broken", func(config Config) (Rig, error) {
+		return nil, failed
+	})
+	tests := []struct {
+		name    string
+		config  Config
+		want    Rig
+		wantErr bool
+	}{
+		{
+			name:   "Success01",
+			config: Config{Name: "rig1", Type: "stub", Address: "127.0.0.1:3333"},
+			want:   &stubRig{config: Config{Name: "rig1", Type: "stub", Address: "127.0.0.1:3333"}},
+		},
+		{name: "FactoryError01", config: Config{Type: "broken"}, wantErr: true},
+		{name: "Unknown01", config: Config{Type: "missing"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Open(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Open() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Open() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	types := Types()
+	if !sort.StringsAreSorted(types) || sort.SearchStrings(types, "stub") == len(types) {
+		t.Errorf("Types() = %v, want a sorted list holding stub", types)
+	}
+}
+
+func Test_Register(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory Factory
+	}{
+		{name: "Duplicate01", factory: func(config Config) (Rig, error) { return nil, nil }},
+		{name: "Nil01", factory: nil},
+	}
+	Register("Duplicate01", tests[0].factory)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register() did not panic")
+				}
+			}()
+			Register(tt.name, tt.factory)
+		})
+	}
+}
+
+func Test_ConfigLabel(t *testing.T) {
+	if got := (Config{Address: "10.0.0.2:3333"}).Label(); got != "10.0.0.2:3333" {
+		t.Errorf("Config.Label() = %v, want %v", got, "10.0.0.2:3333")
+	}
+	if got := (Config{Name: "rig1", Address: "10.0.0.2:3333"}).Label(); got != "rig1" {
+		t.Errorf("Config.Label() = %v, want %v", got, "rig1")
+	}
+}
@@ -0,0 +1,100 @@
+// Package rigtest provides a local TCP stand-in for the APIs of mining software, answering every request on a
+// connection with a reply built by a Handler
+package rigtest
+
+import (
+	"bufio"
+	"net"
+	"sync"
+)
+
+// Handler builds the reply to one request, the request is the line the client sent without its line ending.
+// A nil reply closes the connection without answering
+type Handler func(request []byte) (reply []byte)
+
+// Server is a TCP server on a random local port, call Close when done
+type Server struct {
+	// Addr is the host:port the server listens on
+	Addr string
+
+	listener net.Listener
+	handler  Handler
+	oneShot  bool
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	requests [][]byte
+}
+
+// NewServer starts a Server answering requests with handler
+func NewServer(handler Handler) *Server {
+	return newServer(handler, false)
+}
+
+// NewOneShotServer starts a Server answering a single request per connection with handler and closing it after the
+// reply, the way Claymore's API marks the end of a reply
+func NewOneShotServer(handler Handler) *Server {
+	return newServer(handler, true)
+}
+
+func newServer(handler Handler, oneShot bool) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("rigtest: failed to listen on a port: " + err.Error())
+	}
+	s := &Server{Addr: listener.Addr().String(), listener: listener, handler: handler, oneShot: oneShot}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Requests returns every request received so far
+func (s *Server) Requests() (requests [][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append(requests, s.requests...)
+}
+
+// Close stops the server and waits for open connections to be answered
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go s.answer(conn)
+	}
+}
+
+// answer replies to each line sent on conn until the client closes it, or to the first one for a one shot Server
+func (s *Server) answer(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		request, err := reader.ReadBytes('\n')
+		if len(request) == 0 && err != nil {
+			return
+		}
+		if request[len(request)-1] == '\n' {
+			request = request[:len(request)-1]
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		s.mu.Unlock()
+		reply := s.handler(request)
+		if reply == nil {
+			return
+		}
+		if _, err = conn.Write(reply); err != nil || s.oneShot {
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package rigtest
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func Test_Server(t *testing.T) {
+	s := NewServer(func(request []byte) []byte {
+		if string(request) == "bye" {
+			return nil
+		}
+		return append(request, '\n')
+	})
+	defer s.Close()
+	conn, err := net.Dial("tcp", s.Addr)
+	if err != nil {
+		t.Fatalf("net.Dial() error = %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for _, request := range []string{"summary", "devs"} {
+		conn.Write([]byte(request + "\n"))
+		if got, err := reader.ReadString('\n'); err != nil || got != request+"\n" {
+			t.Errorf("Server answered %q, %v, want %q", got, err, request+"\n")
+		}
+	}
+	conn.Write([]byte("bye\n"))
+	if _, err = reader.ReadString('\n'); err == nil {
+		t.Errorf("Server did not close the connection after a nil reply")
+	}
+	if got, want := s.Requests(), [][]byte{[]byte("summary"), []byte("devs"), []byte("bye")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Server.Requests() = %q, want %q", got, want)
+	}
+}
+
+func Test_OneShotServer(t *testing.T) {
+	s := NewOneShotServer(func(request []byte) []byte {
+		return []byte(`{"result":["9.3 - ETH"]}`)
+	})
+	defer s.Close()
+	conn, err := net.Dial("tcp", s.Addr)
+	if err != nil {
+		t.Fatalf("net.Dial() error = %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("miner_getstat1\n"))
+	got, err := ioutil.ReadAll(conn)
+	if err != nil || string(got) != `{"result":["9.3 - ETH"]}` {
+		t.Errorf("OneShotServer answered %q, %v, want the reply followed by the connection closing", got, err)
+	}
+}
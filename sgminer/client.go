@@ -0,0 +1,159 @@
+// Package sgminer is a client for the socket API of cgminer and the miners derived from it, sgminer and TeamRedMiner
+// among them, which many ASICs serve as well. The API answers JSON or, from older miners, pipe and comma delimited text
+package sgminer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPort is the port the API listens on unless --api-port sets another one
+	DefaultPort = "4028"
+	// DefaultTimeout is the per-call timeout a Client applies when WithTimeout is not given
+	DefaultTimeout = 5 * time.Second
+)
+
+// Dialer is an interface to abstract net.Dialer to support testing
+type Dialer interface {
+	DialContext(ctx context.Context, network string, address string) (conn net.Conn, err error)
+}
+
+// Client talks to the API of a single miner, opening a connection for each command as the miner closes it after every
+// reply
+type Client struct {
+	address   string
+	dialer    Dialer
+	timeout   time.Duration
+	plainText bool
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithDialer sets how connections to the API are opened
+func WithDialer(dialer Dialer) Option {
+	return func(c *Client) {
+		c.dialer = dialer
+	}
+}
+
+// WithTimeout sets the timeout applied to each call, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithPlainText sends commands as plain text and parses the delimited replies, for miners whose JSON replies are
+// broken or missing
+func WithPlainText() Option {
+	return func(c *Client) {
+		c.plainText = true
+	}
+}
+
+// NewClient returns a Client for the API listening on address, a host:port, adjusted by options
+func NewClient(address string, options ...Option) *Client {
+	c := &Client{
+		address: address,
+		dialer:  &net.Dialer{},
+		timeout: DefaultTimeout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Address returns the host:port of the API
+func (c *Client) Address() string {
+	return c.address
+}
+
+// GetSummary sends the summary command and forms the reply in to a usable Struct
+func (c *Client) GetSummary(ctx context.Context) (summary Summary, err error) {
+	err = c.call(ctx, "summary", &summary)
+	return
+}
+
+// GetDevs sends the devs command and forms the reply in to a usable Struct
+func (c *Client) GetDevs(ctx context.Context) (devs Devs, err error) {
+	err = c.call(ctx, "devs", &devs)
+	return
+}
+
+// GetPools sends the pools command and forms the reply in to a usable Struct
+func (c *Client) GetPools(ctx context.Context) (pools Pools, err error) {
+	err = c.call(ctx, "pools", &pools)
+	return
+}
+
+// call sends command within the per-call timeout and decodes the reply in to output. The miner reads a single request
+// without a line ending, so the connection is half closed once it is sent
+func (c *Client) call(ctx context.Context, command string, output interface{}) (err error) {
+	log.Debugf("Client.call(address=%s, command=%s, output interface{}) called\n", c.address, command)
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		log.Errorf("Client.call: c.dialer.DialContext(ctx, tcp, %s); returned err=%s\n", c.address, err.Error())
+		return &APIError{Command: command, Message: err.Error(), Err: ErrUnreachable}
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	request := []byte(command)
+	if !c.plainText {
+		request, _ = json.Marshal(Request{Command: command})
+	}
+	if _, err = conn.Write(request); err != nil {
+		log.Errorf("Client.call: conn.Write(%s); returned err=%s\n", command, err.Error())
+		return &APIError{Command: command, Message: err.Error(), Err: ErrUnreachable}
+	}
+	if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+	}
+	reply, err := ioutil.ReadAll(conn)
+	// replies end with a NUL byte
+	reply = bytes.TrimRight(reply, "\x00\r\n ")
+	if err != nil || len(reply) == 0 {
+		message := "connection closed without a reply"
+		if err != nil {
+			message = err.Error()
+		}
+		log.Errorf("Client.call: ioutil.ReadAll(conn); returned %s\n", message)
+		return &APIError{Command: command, Message: message, Err: ErrUnreachable}
+	}
+	if c.plainText {
+		reply, err = textToJSON(reply, sections[command])
+		if err != nil {
+			log.Errorf("Client.call: textToJSON(reply, %s); returned err=%s\n", sections[command], err.Error())
+			return &APIError{Command: command, Message: err.Error(), Err: ErrMalformedResponse}
+		}
+	}
+	envelope := new(Envelope)
+	if err = json.Unmarshal(reply, envelope); err != nil {
+		log.Errorf("Client.call: json.Unmarshal(reply, envelope); returned err=%s\n", err.Error())
+		return &APIError{Command: command, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	if err = checkStatus(command, envelope.Status); err != nil {
+		log.Errorf("Client.call: checkStatus(%s, status); returned err=%s\n", command, err.Error())
+		return
+	}
+	if err = json.Unmarshal(reply, output); err != nil {
+		log.Errorf("Client.call: json.Unmarshal(reply, output); returned err=%s\n", err.Error())
+		return &APIError{Command: command, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	return
+}
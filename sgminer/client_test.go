@@ -0,0 +1,151 @@
+package sgminer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"mining-tools/rig"
+	"mining-tools/rig/rigtest"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+// reply answers every request with body
+func reply(body string) rigtest.Handler {
+	return func(request []byte) []byte {
+		return []byte(body)
+	}
+}
+
+// replies answers JSON and plain text commands with the reply for the command
+func replies(byCommand map[string]string) rigtest.Handler {
+	return func(request []byte) []byte {
+		var r Request
+		if json.Unmarshal(request, &r) != nil {
+			r.Command = string(request)
+		}
+		if body, ok := byCommand[r.Command]; ok {
+			return []byte(body)
+		}
+		return []byte(`{"STATUS":[{"STATUS":"E","Code":14,"Msg":"Invalid command"}],"id":1}` + "\x00")
+	}
+}
+
+func Test_callErrors(t *testing.T) {
+	closed := rigtest.NewServer(reply(""))
+	closed.Close()
+	tests := []struct {
+		name    string
+		address string
+		handler rigtest.Handler
+		wantErr error
+	}{
+		{
+			name:    "Success01",
+			handler: reply(devsTRM),
+			wantErr: nil,
+		},
+		{
+			name:    "Unreachable01",
+			address: closed.Addr,
+			wantErr: ErrUnreachable,
+		},
+		{
+			name:    "Unreachable02",
+			handler: func(request []byte) []byte { return nil },
+			wantErr: rig.ErrUnreachable,
+		},
+		{
+			name:    "Unauthorized01",
+			handler: reply(accessDenied),
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "Malformed01",
+			handler: reply("garbage\x00"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "Malformed02",
+			handler: reply(`{"id":1}` + "\x00"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "Malformed03",
+			handler: reply(`{"STATUS":[{"STATUS":"S"}],"DEVS":[{"GPU":"first"}],"id":1}` + "\x00"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "RequestFailed01",
+			handler: replies(nil),
+			wantErr: ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.address
+			if tt.handler != nil {
+				server := rigtest.NewOneShotServer(tt.handler)
+				defer server.Close()
+				address = server.Addr
+			}
+			_, err := NewClient(address).GetDevs(context.Background())
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetDevs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.Command != "devs") {
+				t.Errorf("Client.GetDevs() error = %#v, want *APIError for devs", err)
+			}
+		})
+	}
+}
+
+func Test_commands(t *testing.T) {
+	server := rigtest.NewOneShotServer(replies(map[string]string{
+		"summary": summaryTRM, "devs": devsTRM, "pools": poolsTRM,
+	}))
+	defer server.Close()
+	textServer := rigtest.NewOneShotServer(replies(map[string]string{
+		"summary": summaryText, "devs": devsText, "pools": poolsText,
+	}))
+	defer textServer.Close()
+	ctx := context.Background()
+	c := NewClient(server.Addr)
+	tc := NewClient(textServer.Addr, WithPlainText())
+
+	summary, err := c.GetSummary(ctx)
+	if err != nil || len(summary.Summary) != 1 || *summary.Summary[0].MHS30s != 58.8 || summary.Summary[0].HardwareErrors != 1 {
+		t.Errorf("Client.GetSummary() = %+v, %v, want 58.8 MH/s and 1 hardware error", summary, err)
+	}
+	devs, err := c.GetDevs(ctx)
+	if err != nil || len(devs.Devs) != 2 || *devs.Devs[1].GPU != 1 || devs.Devs[1].FanPercent != 60 {
+		t.Errorf("Client.GetDevs() = %+v, %v, want 2 GPUs", devs, err)
+	}
+	pools, err := c.GetPools(ctx)
+	if err != nil || len(pools.Pools) != 2 || !pools.Pools[0].StratumActive || pools.Pools[0].User != "0x01.rig2" {
+		t.Errorf("Client.GetPools() = %+v, %v, want the active pool first", pools, err)
+	}
+
+	summary, err = tc.GetSummary(ctx)
+	if err != nil || summary.Summary[0].KHS30s == nil || *summary.Summary[0].KHS30s != 960.25 || summary.Summary[0].MHS30s != nil {
+		t.Errorf("Client.GetSummary() plain text = %+v, %v, want 960.25 kH/s", summary, err)
+	}
+	devs, err = tc.GetDevs(ctx)
+	if err != nil || len(devs.Devs) != 1 || devs.Devs[0].Temperature != 70 || devs.Devs[0].Enabled != "Y" {
+		t.Errorf("Client.GetDevs() plain text = %+v, %v, want 1 GPU at 70°C", devs, err)
+	}
+	pools, err = tc.GetPools(ctx)
+	if err != nil || len(pools.Pools) != 1 || pools.Pools[0].URL != "stratum+tcp://litecoinpool.org:3333" || !pools.Pools[0].StratumActive {
+		t.Errorf("Client.GetPools() plain text = %+v, %v, want litecoinpool.org", pools, err)
+	}
+	if requests := textServer.Requests(); len(requests) != 3 || string(requests[0]) != "summary" {
+		t.Errorf("plain text commands = %q, want summary, devs and pools without line endings", requests)
+	}
+}
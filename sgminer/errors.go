@@ -0,0 +1,64 @@
+package sgminer
+
+import (
+	"errors"
+	"fmt"
+
+	"mining-tools/rig"
+)
+
+var (
+	// ErrUnreachable is returned when no connection could be made to the API or it closed before answering
+	ErrUnreachable = errors.New("unreachable")
+	// ErrUnauthorized is returned when the miner only allows the command to privileged or listed hosts
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrMalformedResponse is returned when the reply could not be decoded
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrRequestFailed is returned for any other error status
+	ErrRequestFailed = errors.New("request failed")
+)
+
+// codeAccessDenied is the status code of a command the miner does not allow from the client's host
+const codeAccessDenied = 45
+
+// APIError describes a failed command, Err is one of the Err* sentinels above so callers can branch with errors.Is
+type APIError struct {
+	Command string
+	Code    int
+	Message string
+	Err     error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("sgminer %s: %s", e.Command, e.Err)
+	}
+	return fmt.Sprintf("sgminer %s: %s: %s", e.Command, e.Err, e.Message)
+}
+
+// Unwrap returns the sentinel error describing the kind of failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets callers match the rig package sentinels, so code written against rig.Rig can branch on sgminer failures
+func (e *APIError) Is(target error) bool {
+	return target == rig.ErrUnreachable && e.Err == ErrUnreachable
+}
+
+// checkStatus turns the STATUS section of a reply in to an *APIError, or nil if the command succeeded. Success,
+// information and warning statuses all carry data
+func checkStatus(command string, status []Status) (err error) {
+	if len(status) == 0 {
+		return &APIError{Command: command, Message: "no STATUS", Err: ErrMalformedResponse}
+	}
+	switch status[0].Status {
+	case "S", "I", "W":
+		return nil
+	}
+	apiErr := &APIError{Command: command, Code: status[0].Code, Message: status[0].Msg, Err: ErrRequestFailed}
+	if status[0].Code == codeAccessDenied {
+		apiErr.Err = ErrUnauthorized
+	}
+	return apiErr
+}
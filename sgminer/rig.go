@@ -0,0 +1,106 @@
+package sgminer
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"mining-tools/rig"
+)
+
+// Rig adapts a Client to the rig.Rig interface
+type Rig struct {
+	client *Client
+}
+
+// NewRig returns a rig.Rig backed by client
+func NewRig(client *Client) *Rig {
+	return &Rig{client: client}
+}
+
+// Client returns the sgminer Client behind the Rig
+func (r *Rig) Client() *Client {
+	return r.client
+}
+
+// Stats returns the hashrate, shares and sensor readings of the rig and each of its devices from the summary and devs
+// commands, along with the worker name of the active pool from the pools command
+func (r *Rig) Stats(ctx context.Context) (stats rig.Stats, err error) {
+	summary, err := r.client.GetSummary(ctx)
+	if err != nil {
+		return
+	}
+	devs, err := r.client.GetDevs(ctx)
+	if err != nil {
+		return
+	}
+	pools, err := r.client.GetPools(ctx)
+	if err != nil {
+		return
+	}
+	// the miner describes itself as e.g. "TeamRedMiner 0.8.1", kept as teamredminer-0.8.1 so it is usable as a tag
+	if len(summary.Status) > 0 {
+		stats.Miner = strings.ToLower(strings.Join(strings.Fields(summary.Status[0].Description), "-"))
+	}
+	stats.Worker = activeWorker(pools.Pools)
+	if len(summary.Summary) > 0 {
+		s := summary.Summary[0]
+		stats.Uptime = time.Duration(s.Elapsed) * time.Second
+		stats.Hashrate = hashrate(s.MHS30s, s.KHS30s)
+		stats.Shares = rig.Shares{Accepted: s.Accepted, Rejected: s.Rejected, Invalid: s.HardwareErrors}
+	}
+	for i, d := range devs.Devs {
+		stats.GPUs = append(stats.GPUs, rig.GPU{
+			Index:       index(d, i),
+			Name:        d.Name,
+			Hashrate:    hashrate(d.MHS30s, d.KHS30s),
+			Temperature: d.Temperature,
+			FanSpeed:    d.FanPercent,
+			Shares:      rig.Shares{Accepted: d.Accepted, Rejected: d.Rejected, Invalid: d.HardwareErrors},
+		})
+	}
+	return
+}
+
+// hashrate returns the 30 second hashrate in H/s from whichever of the MH/s and kH/s values the miner sends
+func hashrate(mhs *float64, khs *float64) float64 {
+	switch {
+	case mhs != nil:
+		return *mhs * 1e6
+	case khs != nil:
+		return *khs * 1e3
+	}
+	return 0
+}
+
+// index returns the number the miner gives a device, or its position for miners that number none
+func index(d Dev, position int) int {
+	for _, n := range []*int{d.GPU, d.ASC, d.PGA} {
+		if n != nil {
+			return *n
+		}
+	}
+	return position
+}
+
+// activeWorker returns the worker name of the pool the miner is mining on, the first live pool when none is marked
+// active, or nothing when the login has no worker name
+func activeWorker(pools []PoolData) string {
+	var active *PoolData
+	for i := range pools {
+		if pools[i].StratumActive {
+			active = &pools[i]
+			break
+		}
+		if active == nil && pools[i].Status == "Alive" {
+			active = &pools[i]
+		}
+	}
+	if active == nil {
+		return ""
+	}
+	if i := strings.LastIndexAny(active.User, "./"); i >= 0 {
+		return active.User[i+1:]
+	}
+	return ""
+}
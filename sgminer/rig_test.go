@@ -0,0 +1,88 @@
+package sgminer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/rig"
+	"mining-tools/rig/rigtest"
+)
+
+func Test_Rig(t *testing.T) {
+	server := rigtest.NewOneShotServer(replies(map[string]string{
+		"summary": summaryTRM, "devs": devsTRM, "pools": poolsTRM,
+	}))
+	defer server.Close()
+	textServer := rigtest.NewOneShotServer(replies(map[string]string{
+		"summary": summaryText, "devs": devsText, "pools": poolsText,
+	}))
+	defer textServer.Close()
+	tests := []struct {
+		name   string
+		client *Client
+		want   rig.Stats
+	}{
+		{
+			name:   "TeamRedMiner01",
+			client: NewClient(server.Addr),
+			want: rig.Stats{
+				Miner:    "teamredminer-0.8.1",
+				Worker:   "rig2",
+				Uptime:   time.Hour,
+				Hashrate: 58800000,
+				Shares:   rig.Shares{Accepted: 185, Rejected: 1, Invalid: 1},
+				GPUs: []rig.GPU{
+					{Index: 0, Hashrate: 30000000, Temperature: 61, FanSpeed: 55, Shares: rig.Shares{Accepted: 95, Rejected: 1}},
+					{Index: 1, Hashrate: 28800000, Temperature: 65, FanSpeed: 60, Shares: rig.Shares{Accepted: 90, Invalid: 1}},
+				},
+			},
+		},
+		{
+			name:   "PlainText01",
+			client: NewClient(textServer.Addr, WithPlainText()),
+			want: rig.Stats{
+				Miner:    "sgminer-5.6.0",
+				Worker:   "rig7",
+				Uptime:   2 * time.Hour,
+				Hashrate: 960250,
+				Shares:   rig.Shares{Accepted: 410, Rejected: 3, Invalid: 2},
+				GPUs: []rig.GPU{
+					{Index: 0, Hashrate: 960250, Temperature: 70, FanSpeed: 65, Shares: rig.Shares{Accepted: 410, Rejected: 3, Invalid: 2}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := NewRig(tt.client).Stats(context.Background())
+			if err != nil {
+				t.Fatalf("Rig.Stats() error = %v", err)
+			}
+			if !reflect.DeepEqual(stats, tt.want) {
+				t.Errorf("Rig.Stats() = %+v, want %+v", stats, tt.want)
+			}
+		})
+	}
+}
+
+func Test_activeWorker(t *testing.T) {
+	tests := []struct {
+		name  string
+		pools []PoolData
+		want  string
+	}{
+		{name: "Active01", pools: []PoolData{{Status: "Alive", User: "0x01.rig1"}, {Status: "Alive", User: "0x01/rig2", StratumActive: true}}, want: "rig2"},
+		{name: "Alive01", pools: []PoolData{{Status: "Dead", User: "0x01.rig1"}, {Status: "Alive", User: "0x01.rig2"}}, want: "rig2"},
+		{name: "NoWorker01", pools: []PoolData{{Status: "Alive", User: "0x01", StratumActive: true}}, want: ""},
+		{name: "NoPools01", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := activeWorker(tt.pools); got != tt.want {
+				t.Errorf("activeWorker() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package sgminer
+
+// Request is a command sent to the API as JSON
+type Request struct {
+	Command   string `json:"command"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// Envelope is for decoding the STATUS section every reply starts with
+type Envelope struct {
+	Status []Status `json:"STATUS"`
+}
+
+// Status is for decoding the STATUS section of a reply, Status is S, I or W when the command succeeded and E or F when
+// it failed
+type Status struct {
+	Status      string `json:"STATUS"`
+	When        int64  `json:"When"`
+	Code        int    `json:"Code"`
+	Msg         string `json:"Msg"`
+	Description string `json:"Description"`
+}
+
+// Summary is for decoding json from a successful reply to the summary command
+type Summary struct {
+	Status  []Status      `json:"STATUS"`
+	Summary []SummaryData `json:"SUMMARY"`
+	ID      int           `json:"id"`
+}
+
+// SummaryData is for decoding json from a successful reply to the summary command, hashrates are sent in MH/s or,
+// by scrypt miners, in kH/s and Elapsed is in seconds
+type SummaryData struct {
+	Elapsed        int64    `json:"Elapsed"`
+	MHSAv          *float64 `json:"MHS av,omitempty"`
+	MHS30s         *float64 `json:"MHS 30s,omitempty"`
+	KHSAv          *float64 `json:"KHS av,omitempty"`
+	KHS30s         *float64 `json:"KHS 30s,omitempty"`
+	FoundBlocks    int64    `json:"Found Blocks"`
+	Getworks       int64    `json:"Getworks"`
+	Accepted       int64    `json:"Accepted"`
+	Rejected       int64    `json:"Rejected"`
+	HardwareErrors int64    `json:"Hardware Errors"`
+	Utility        float64  `json:"Utility"`
+	Discarded      int64    `json:"Discarded"`
+	Stale          int64    `json:"Stale"`
+	GetFailures    int64    `json:"Get Failures"`
+	RemoteFailures int64    `json:"Remote Failures"`
+	NetworkBlocks  int64    `json:"Network Blocks"`
+	BestShare      float64  `json:"Best Share"`
+}
+
+// Devs is for decoding json from a successful reply to the devs command
+type Devs struct {
+	Status []Status `json:"STATUS"`
+	Devs   []Dev    `json:"DEVS"`
+	ID     int      `json:"id"`
+}
+
+// Dev is for decoding json from a successful reply to the devs command, GPUs are numbered by GPU and ASICs by ASC or
+// PGA. Temperature is in °C and hashrates are sent in MH/s or, by scrypt miners, in kH/s
+type Dev struct {
+	GPU            *int     `json:"GPU,omitempty"`
+	ASC            *int     `json:"ASC,omitempty"`
+	PGA            *int     `json:"PGA,omitempty"`
+	Name           string   `json:"Name,omitempty"`
+	Enabled        string   `json:"Enabled"`
+	Status         string   `json:"Status"`
+	Temperature    float64  `json:"Temperature"`
+	FanSpeed       float64  `json:"Fan Speed"`
+	FanPercent     float64  `json:"Fan Percent"`
+	GPUClock       float64  `json:"GPU Clock"`
+	MemoryClock    float64  `json:"Memory Clock"`
+	GPUVoltage     float64  `json:"GPU Voltage"`
+	GPUActivity    float64  `json:"GPU Activity"`
+	MHSAv          *float64 `json:"MHS av,omitempty"`
+	MHS30s         *float64 `json:"MHS 30s,omitempty"`
+	KHSAv          *float64 `json:"KHS av,omitempty"`
+	KHS30s         *float64 `json:"KHS 30s,omitempty"`
+	Accepted       int64    `json:"Accepted"`
+	Rejected       int64    `json:"Rejected"`
+	HardwareErrors int64    `json:"Hardware Errors"`
+	LastSharePool  int64    `json:"Last Share Pool"`
+	LastShareTime  int64    `json:"Last Share Time"`
+}
+
+// Pools is for decoding json from a successful reply to the pools command
+type Pools struct {
+	Status []Status   `json:"STATUS"`
+	Pools  []PoolData `json:"POOLS"`
+	ID     int        `json:"id"`
+}
+
+// PoolData is for decoding json from a successful reply to the pools command, User is the login sent to the pool,
+// usually the wallet address and the worker name joined by a dot or a slash
+type PoolData struct {
+	Pool          int    `json:"POOL"`
+	URL           string `json:"URL"`
+	Status        string `json:"Status"`
+	Priority      int    `json:"Priority"`
+	Accepted      int64  `json:"Accepted"`
+	Rejected      int64  `json:"Rejected"`
+	Stale         int64  `json:"Stale"`
+	User          string `json:"User"`
+	LastShareTime int64  `json:"Last Share Time"`
+	StratumActive bool   `json:"Stratum Active"`
+}
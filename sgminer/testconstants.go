@@ -0,0 +1,42 @@
+package sgminer
+
+// summaryTRM is TeamRedMiner's JSON reply to the summary command, ended by a NUL byte
+const summaryTRM = `{"STATUS":[{"STATUS":"S","When":1609459200,"Code":11,"Msg":"Summary","Description":"TeamRedMiner 0.8.1"}],` +
+	`"SUMMARY":[{"Elapsed":3600,"MHS av":58.7,"MHS 30s":58.8,"Found Blocks":0,"Getworks":120,"Accepted":185,"Rejected":1,` +
+	`"Hardware Errors":1,"Utility":3.08,"Discarded":40,"Stale":1,"Get Failures":0,"Remote Failures":0,"Network Blocks":30,` +
+	`"Best Share":2405163722}],"id":1}` + "\x00"
+
+// devsTRM is TeamRedMiner's JSON reply to the devs command for two GPUs
+const devsTRM = `{"STATUS":[{"STATUS":"S","When":1609459200,"Code":9,"Msg":"2 GPU(s)","Description":"TeamRedMiner 0.8.1"}],` +
+	`"DEVS":[{"GPU":0,"Enabled":"Y","Status":"Alive","Temperature":61,"Fan Speed":1800,"Fan Percent":55,"GPU Clock":1150,` +
+	`"Memory Clock":2100,"GPU Voltage":0.85,"GPU Activity":100,"MHS av":29.9,"MHS 30s":30,"Accepted":95,"Rejected":1,` +
+	`"Hardware Errors":0,"Last Share Pool":0,"Last Share Time":1609459180},` +
+	`{"GPU":1,"Enabled":"Y","Status":"Alive","Temperature":65,"Fan Speed":2000,"Fan Percent":60,"GPU Clock":1150,` +
+	`"Memory Clock":2100,"GPU Voltage":0.85,"GPU Activity":100,"MHS av":28.8,"MHS 30s":28.8,"Accepted":90,"Rejected":0,` +
+	`"Hardware Errors":1,"Last Share Pool":0,"Last Share Time":1609459150}],"id":1}` + "\x00"
+
+// poolsTRM is TeamRedMiner's JSON reply to the pools command with a failover pool
+const poolsTRM = `{"STATUS":[{"STATUS":"S","When":1609459200,"Code":7,"Msg":"2 Pool(s)","Description":"TeamRedMiner 0.8.1"}],` +
+	`"POOLS":[{"POOL":0,"URL":"stratum+tcp://eth-eu1.nanopool.org:9999","Status":"Alive","Priority":0,"Accepted":185,` +
+	`"Rejected":1,"Stale":1,"User":"0x01.rig2","Last Share Time":1609459180,"Stratum Active":true},` +
+	`{"POOL":1,"URL":"stratum+tcp://eth-eu2.nanopool.org:9999","Status":"Alive","Priority":1,"User":"0x01.rig2",` +
+	`"Stratum Active":false}],"id":1}` + "\x00"
+
+// summaryText is sgminer's plain text reply to the summary command of a scrypt miner
+const summaryText = `STATUS=S,When=1609459200,Code=11,Msg=Summary,Description=sgminer 5.6.0|` +
+	`SUMMARY,Elapsed=7200,KHS av=950.5,KHS 30s=960.25,Found Blocks=0,Accepted=410,Rejected=3,Hardware Errors=2,` +
+	`Utility=3.42,Stale=1,Best Share=91233|` + "\x00"
+
+// devsText is sgminer's plain text reply to the devs command for one GPU
+const devsText = `STATUS=S,When=1609459200,Code=9,Msg=1 GPU(s),Description=sgminer 5.6.0|` +
+	`GPU=0,Enabled=Y,Status=Alive,Temperature=70.00,Fan Speed=2400,Fan Percent=65,KHS av=950.5,KHS 30s=960.25,` +
+	`Accepted=410,Rejected=3,Hardware Errors=2,Last Share Time=1609459190|` + "\x00"
+
+// poolsText is sgminer's plain text reply to the pools command
+const poolsText = `STATUS=S,When=1609459200,Code=7,Msg=1 Pool(s),Description=sgminer 5.6.0|` +
+	`POOL=0,URL=stratum+tcp://litecoinpool.org:3333,Status=Alive,Priority=0,Accepted=410,Rejected=3,User=miner.rig7,` +
+	`Stratum Active=true|` + "\x00"
+
+// accessDenied is the reply to a command the miner does not allow from the client's host
+const accessDenied = `{"STATUS":[{"STATUS":"E","When":1609459200,"Code":45,"Msg":"Access denied to 'devs' command",` +
+	`"Description":"sgminer 5.6.0"}],"id":1}` + "\x00"
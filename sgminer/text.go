@@ -0,0 +1,61 @@
+package sgminer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// sections maps a command to the section holding its data, the name JSON replies use for it
+var sections = map[string]string{
+	"summary": "SUMMARY",
+	"devs":    "DEVS",
+	"pools":   "POOLS",
+}
+
+// textToJSON converts a plain text reply such as "STATUS=S,Code=11,Msg=Summary|SUMMARY,Elapsed=3600,MHS av=58.80|" to
+// the JSON reply of the same command, every section after STATUS being put under section. Values that read as
+// numbers or booleans are converted, so text and JSON replies decode in to the same Structs
+func textToJSON(reply []byte, section string) (converted []byte, err error) {
+	parts := strings.Split(strings.TrimSuffix(string(reply), "|"), "|")
+	object := map[string][]map[string]interface{}{"STATUS": nil, section: {}}
+	for i, part := range parts {
+		fields := make(map[string]interface{})
+		for _, field := range strings.Split(part, ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				// data sections may start with their bare name, e.g. SUMMARY
+				continue
+			}
+			fields[kv[0]] = textValue(kv[1])
+		}
+		if i == 0 {
+			if _, ok := fields["STATUS"]; !ok {
+				return nil, fmt.Errorf("reply does not start with a STATUS section: %q", part)
+			}
+			object["STATUS"] = append(object["STATUS"], fields)
+			continue
+		}
+		object[section] = append(object[section], fields)
+	}
+	return json.Marshal(object)
+}
+
+// textValue returns a number or boolean for values that read as one, anything else stays a string. Hexadecimal
+// looking values such as wallet addresses, and names such as Inf that JSON has no number for, are kept as strings
+func textValue(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if !strings.HasPrefix(value, "0x") {
+		if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f
+		}
+	}
+	return value
+}
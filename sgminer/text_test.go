@@ -0,0 +1,70 @@
+package sgminer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_textToJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		section string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Summary01",
+			reply:   "STATUS=S,Code=11,Msg=Summary|SUMMARY,Elapsed=3600,MHS av=58.80|",
+			section: "SUMMARY",
+			want: map[string]interface{}{
+				"STATUS":  []interface{}{map[string]interface{}{"STATUS": "S", "Code": 11.0, "Msg": "Summary"}},
+				"SUMMARY": []interface{}{map[string]interface{}{"Elapsed": 3600.0, "MHS av": 58.8}},
+			},
+		},
+		{
+			name:    "Pools01",
+			reply:   "STATUS=S,Code=7|POOL=0,User=0x01.rig2,Stratum Active=true|POOL=1,User=0x01,Best Share=Inf,Stratum Active=false",
+			section: "POOLS",
+			want: map[string]interface{}{
+				"STATUS": []interface{}{map[string]interface{}{"STATUS": "S", "Code": 7.0}},
+				"POOLS": []interface{}{
+					map[string]interface{}{"POOL": 0.0, "User": "0x01.rig2", "Stratum Active": true},
+					map[string]interface{}{"POOL": 1.0, "User": "0x01", "Best Share": "Inf", "Stratum Active": false},
+				},
+			},
+		},
+		{
+			name:    "Empty01",
+			reply:   "STATUS=E,Code=14,Msg=Invalid command|",
+			section: "DEVS",
+			want: map[string]interface{}{
+				"STATUS": []interface{}{map[string]interface{}{"STATUS": "E", "Code": 14.0, "Msg": "Invalid command"}},
+				"DEVS":   []interface{}{},
+			},
+		},
+		{
+			name:    "NoStatus01",
+			reply:   "GPU=0,Enabled=Y|",
+			section: "DEVS",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := textToJSON([]byte(tt.reply), tt.section)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("textToJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var got map[string]interface{}
+			json.Unmarshal(converted, &got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("textToJSON() = %s, want %v", converted, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package sink describes the timeseries databases the metrics command writes to, so the configured protocol picks the
+// backend without the command knowing how each one is written to
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mining-tools/lineprotocol"
+)
+
+// Sink is a timeseries database accepting points
+type Sink interface {
+	Write(ctx context.Context, points []*lineprotocol.Point) error
+}
+
+// ErrUnreachable is matched by the errors of a Sink when the database could not be reached
+var ErrUnreachable = errors.New("timeseries database unreachable")
+
+// Config is miningtools.timeseriesDB, where the metrics go and how they are sent
+type Config struct {
+	// Protocol selects the registered Factory, names are matched regardless of case
+	Protocol string `mapstructure:"protocol"`
+	// Address is where the database listens, a host:port or a URL depending on the protocol
+	Address string `mapstructure:"address"`
+	// Timeout bounds connecting to the database and sending one batch of points
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Org, Bucket and Token select where and as whom an HTTP API such as InfluxDB 2.x writes
+	Org    string `mapstructure:"org"`
+	Bucket string `mapstructure:"bucket"`
+	Token  string `mapstructure:"token"`
+	// Precision is the unit of timestamps for protocols that let it be chosen, ns, us, ms or s
+	Precision string `mapstructure:"precision"`
+	// BatchSize is the most points sent in one request for protocols that batch
+	BatchSize int `mapstructure:"batchSize"`
+	// Template builds the dotted metric paths of protocols such as Graphite and StatsD, e.g. mining.{location}.{field}
+	Template string `mapstructure:"template"`
+	// Namespace prefixes metric names for protocols that name a metric after each field, such as remote write
+	Namespace string `mapstructure:"namespace"`
+	// Labels renames tags for protocols with labels, an empty label drops the tag. ExternalLabels are added to every
+	// series that does not already carry them
+	Labels         map[string]string `mapstructure:"labels"`
+	ExternalLabels map[string]string `mapstructure:"externalLabels"`
+}
+
+// Factory builds a Sink from its config
+type Factory func(config Config) (Sink, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a Factory available to Open under name, it panics if name is registered twice or factory is nil
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if factory == nil {
+		panic("sink: Register factory is nil")
+	}
+	name = strings.ToLower(name)
+	if _, dup := factories[name]; dup {
+		panic("sink: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// Types returns the sorted names of the registered factories
+func Types() (types []string) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	for name := range factories {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return
+}
+
+// Open builds the Sink described by config with the Factory registered for config.Protocol
+func Open(config Config) (sink Sink, err error) {
+	factoriesMu.RLock()
+	factory, ok := factories[strings.ToLower(config.Protocol)]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown timeseries protocol %q, expected one of %v", config.Protocol, Types())
+	}
+	return factory(config)
+}
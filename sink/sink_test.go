@@ -0,0 +1,88 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"mining-tools/lineprotocol"
+)
+
+type stubSink struct {
+	config Config
+}
+
+func (ss *stubSink) Write(ctx context.Context, points []*lineprotocol.Point) (err error) {
+	return
+}
+
+func Test_Open(t *testing.T) {
+	failed := errors.New("Failed")
+	Register("Stub", func(config Config) (Sink, error) {
+		return &stubSink{config: config}, nil
+	})
+	Register("broken", func(config Config) (Sink, error) {
+		return nil, failed
+	})
+	tests := []struct {
+		name    string
+		config  Config
+		want    Sink
+		wantErr bool
+	}{
+		{
+			name:   "Success01",
+			config: Config{Protocol: "stub", Address: "127.0.0.1:9009"},
+			want:   &stubSink{config: Config{Protocol: "stub", Address: "127.0.0.1:9009"}},
+		},
+		{
+			name:   "CaseInsensitive01",
+			config: Config{Protocol: "STUB"},
+			want:   &stubSink{config: Config{Protocol: "STUB"}},
+		},
+		{name: "FactoryError01", config: Config{Protocol: "broken"}, wantErr: true},
+		{name: "Unknown01", config: Config{Protocol: "missing"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Open(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Open() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Open() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	types := Types()
+	if !sort.StringsAreSorted(types) || sort.SearchStrings(types, "stub") == len(types) {
+		t.Errorf("Types() = %v, want a sorted list holding stub", types)
+	}
+}
+
+func Test_Register(t *testing.T) {
+	factory := func(config Config) (Sink, error) { return &stubSink{}, nil }
+	Register("twice", factory)
+	tests := []struct {
+		name    string
+		sink    string
+		factory Factory
+	}{
+		{name: "Duplicate01", sink: "twice", factory: factory},
+		{name: "Duplicate02", sink: "TWICE", factory: factory},
+		{name: "NilFactory01", sink: "nil", factory: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%s) did not panic", tt.sink)
+				}
+			}()
+			Register(tt.sink, tt.factory)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Package statsd sends points to StatsD as gauges over UDP. Every numeric field of a point becomes a gauge named by a
+// graphite.Template, StatsD stamps gauges itself when it flushes so the times of points are not sent
+package statsd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"mining-tools/graphite"
+	"mining-tools/lineprotocol"
+	"mining-tools/sink"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultAddress is the host:port StatsD listens on by default
+	DefaultAddress = "127.0.0.1:8125"
+	// DefaultTimeout is the timeout a Sink applies to each write when WithTimeout is not given
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxPacketSize keeps datagrams within the MTU of an Ethernet link, so they are not fragmented and lost
+	DefaultMaxPacketSize = 1432
+)
+
+// Dialer is an interface to abstract net.Dialer to support testing
+type Dialer interface {
+	DialContext(ctx context.Context, network string, address string) (conn net.Conn, err error)
+}
+
+// Sink sends gauges to one StatsD server, UDP has no replies so a write that left the host is as much as can be known
+type Sink struct {
+	address       string
+	template      *graphite.Template
+	dialer        Dialer
+	timeout       time.Duration
+	maxPacketSize int
+}
+
+// Option configures a Sink created by NewSink
+type Option func(*Sink)
+
+// WithTemplate sets how gauges are named, graphite.DefaultTemplate is used otherwise
+func WithTemplate(template *graphite.Template) Option {
+	return func(s *Sink) {
+		s.template = template
+	}
+}
+
+// WithDialer sets how the UDP socket to the server is opened
+func WithDialer(dialer Dialer) Option {
+	return func(s *Sink) {
+		s.dialer = dialer
+	}
+}
+
+// WithTimeout sets the timeout applied to each write, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Sink) {
+		s.timeout = timeout
+	}
+}
+
+// WithMaxPacketSize sets the most bytes sent in one datagram, gauges are never split across datagrams
+func WithMaxPacketSize(size int) Option {
+	return func(s *Sink) {
+		s.maxPacketSize = size
+	}
+}
+
+// NewSink returns a Sink for the StatsD server at address, a host:port, adjusted by options
+func NewSink(address string, options ...Option) *Sink {
+	template, _ := graphite.ParseTemplate(graphite.DefaultTemplate)
+	s := &Sink{
+		address:       address,
+		template:      template,
+		dialer:        &net.Dialer{},
+		timeout:       DefaultTimeout,
+		maxPacketSize: DefaultMaxPacketSize,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Address returns the host:port of the server
+func (s *Sink) Address() string {
+	return s.address
+}
+
+// Write sends the numeric fields of points as gauges, packed in to as few datagrams as fit
+func (s *Sink) Write(ctx context.Context, points []*lineprotocol.Point) (err error) {
+	metrics := s.template.Metrics(points)
+	if len(metrics) == 0 {
+		log.Debugln("Sink.Write: no numeric fields to write")
+		return nil
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	conn, err := s.dialer.DialContext(ctx, "udp", s.address)
+	if err != nil {
+		log.Errorf("Sink.Write: DialContext(udp, %s); returned err=%s\n", s.address, err.Error())
+		return fmt.Errorf("statsd %s: %w: %s", s.address, sink.ErrUnreachable, err.Error())
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	packets := pack(metrics, s.maxPacketSize)
+	log.Debugf("Sink.Write: sending %d gauges in %d packets to %s\n", len(metrics), len(packets), s.address)
+	for _, packet := range packets {
+		if _, err = conn.Write(packet); err != nil {
+			log.Errorf("Sink.Write: conn.Write(packet); returned err=%s\n", err.Error())
+			return fmt.Errorf("statsd %s: %w", s.address, err)
+		}
+	}
+	return
+}
+
+// gauge returns the lines setting the gauge of m. A signed value adjusts a gauge rather than setting it, so a negative
+// value is set by zeroing the gauge first
+func gauge(m graphite.Metric) []byte {
+	value := strconv.FormatFloat(m.Value, 'f', -1, 64)
+	if m.Value < 0 {
+		return []byte(fmt.Sprintf("%s:0|g\n%s:%s|g\n", m.Path, m.Path, value))
+	}
+	return []byte(fmt.Sprintf("%s:%s|g\n", m.Path, value))
+}
+
+// pack joins gauges in to datagrams of up to size bytes, a gauge longer than size is sent in a datagram of its own
+func pack(metrics []graphite.Metric, size int) (packets [][]byte) {
+	var packet bytes.Buffer
+	for _, m := range metrics {
+		line := gauge(m)
+		if packet.Len() > 0 && packet.Len()+len(line) > size {
+			packets = append(packets, bytes.TrimSuffix(append([]byte(nil), packet.Bytes()...), []byte("\n")))
+			packet.Reset()
+		}
+		packet.Write(line)
+	}
+	if packet.Len() > 0 {
+		packets = append(packets, bytes.TrimSuffix(packet.Bytes(), []byte("\n")))
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package statsd
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/graphite"
+	"mining-tools/lineprotocol"
+)
+
+// listen reads datagrams on a random local port and sends each on the returned channel
+func listen(t *testing.T) (address string, received chan string) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.ListenPacket() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	received = make(chan string, 10)
+	go func() {
+		buf := make([]byte, 65536)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			received <- string(buf[:n])
+		}
+	}()
+	return conn.LocalAddr().String(), received
+}
+
+func Test_Write(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxPacketSize int
+		want          []string
+	}{
+		{
+			name:          "Success01",
+			maxPacketSize: DefaultMaxPacketSize,
+			want:          []string{"mining.rig1.Hashrate:95500000|g\nmining.rig1.Temperature:0|g\nmining.rig1.Temperature:-2.5|g\nmining.rig1.AcceptedShares:185|g"},
+		},
+		{
+			name:          "Packets01",
+			maxPacketSize: 64,
+			want: []string{
+				"mining.rig1.Hashrate:95500000|g",
+				"mining.rig1.Temperature:0|g\nmining.rig1.Temperature:-2.5|g",
+				"mining.rig1.AcceptedShares:185|g",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, received := listen(t)
+			points := []*lineprotocol.Point{
+				lineprotocol.NewPoint("rig", time.Now()).AddTag("Rig", "rig1").AddTag("Miner", "ethminer").
+					AddField("Hashrate", 95500000.0).AddField("Temperature", -2.5).AddField("AcceptedShares", 185),
+			}
+			template, _ := graphite.ParseTemplate("mining.{rig}.{field}")
+			s := NewSink(address, WithTemplate(template), WithMaxPacketSize(tt.maxPacketSize), WithTimeout(time.Second))
+			if err := s.Write(context.Background(), points); err != nil {
+				t.Fatalf("Sink.Write() error = %v", err)
+			}
+			var got []string
+			for range tt.want {
+				select {
+				case packet := <-received:
+					got = append(got, packet)
+				case <-time.After(time.Second):
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sink.Write() sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
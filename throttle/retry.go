@@ -0,0 +1,119 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Policy describes how an idempotent request is retried. The zero value makes a single attempt
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt, doubled for every attempt after that
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay and any Retry-After the server asks for
+	MaxDelay time.Duration
+}
+
+// DefaultPolicy makes up to three attempts, waiting about one then two seconds between them
+var DefaultPolicy = Policy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+// Error reports the final error of a request that was attempted more than once
+type Error struct {
+	Attempts int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (after %d attempts)", e.Err, e.Attempts)
+}
+
+// Unwrap returns the error of the final attempt
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Do calls send until it returns a response that should not be retried or the attempts run out, waiting on limiter
+// before every attempt. Transport errors, 429 and 5xx responses are retried, the body of a discarded response is closed.
+// The final response or error is returned along with the number of attempts made
+func (p Policy) Do(ctx context.Context, limiter *Limiter, send func(ctx context.Context) (*http.Response, error)) (resp *http.Response, attempts int, err error) {
+	for {
+		if err = limiter.Wait(ctx); err != nil {
+			return
+		}
+		attempts++
+		resp, err = send(ctx)
+		if attempts >= p.MaxAttempts || !retryable(ctx, resp, err) {
+			return
+		}
+		delay := p.backoff(attempts)
+		if resp != nil {
+			if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > delay {
+				delay = retryAfter
+			}
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			resp = nil
+		}
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns BaseDelay doubled for every attempt after the first, with jitter picking a delay between half and all of it
+func (p Policy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter understands both forms of the Retry-After header, delay-seconds and HTTP-date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
@@ -0,0 +1,154 @@
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type attempt struct {
+	statusCode int
+	retryAfter string
+	err        error
+}
+
+func Test_PolicyDo(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	timedOut := errors.New("Timedout")
+	tests := []struct {
+		name           string
+		policy         Policy
+		attempts       []attempt
+		wantAttempts   int
+		wantStatusCode int
+		wantErr        error
+	}{
+		{
+			name:           "Success01",
+			policy:         policy,
+			attempts:       []attempt{{statusCode: 200}},
+			wantAttempts:   1,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "Recovered01",
+			policy:         policy,
+			attempts:       []attempt{{statusCode: 502}, {statusCode: 429, retryAfter: "1"}, {statusCode: 200}},
+			wantAttempts:   3,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "Recovered02",
+			policy:         policy,
+			attempts:       []attempt{{err: timedOut}, {statusCode: 200}},
+			wantAttempts:   2,
+			wantStatusCode: 200,
+		},
+		{
+			name:         "Exhausted01",
+			policy:       policy,
+			attempts:     []attempt{{err: timedOut}, {err: timedOut}, {err: timedOut}, {statusCode: 200}},
+			wantAttempts: 3,
+			wantErr:      timedOut,
+		},
+		{
+			name:           "Exhausted02",
+			policy:         policy,
+			attempts:       []attempt{{statusCode: 503}, {statusCode: 503}, {statusCode: 503}},
+			wantAttempts:   3,
+			wantStatusCode: 503,
+		},
+		{
+			name:           "NotRetryable01",
+			policy:         policy,
+			attempts:       []attempt{{statusCode: 404}, {statusCode: 200}},
+			wantAttempts:   1,
+			wantStatusCode: 404,
+		},
+		{
+			name:           "ZeroPolicy01",
+			policy:         Policy{},
+			attempts:       []attempt{{statusCode: 500}, {statusCode: 200}},
+			wantAttempts:   1,
+			wantStatusCode: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			send := func(ctx context.Context) (*http.Response, error) {
+				a := tt.attempts[calls]
+				calls++
+				if a.err != nil {
+					return nil, a.err
+				}
+				resp := &http.Response{
+					StatusCode: a.statusCode,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+				}
+				if a.retryAfter != "" {
+					resp.Header.Set("Retry-After", a.retryAfter)
+				}
+				return resp, nil
+			}
+			resp, gotAttempts, err := tt.policy.Do(context.Background(), nil, send)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Policy.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotAttempts != tt.wantAttempts {
+				t.Errorf("Policy.Do() attempts = %v, want %v", gotAttempts, tt.wantAttempts)
+			}
+			if tt.wantErr == nil && resp.StatusCode != tt.wantStatusCode {
+				t.Errorf("Policy.Do() StatusCode = %v, want %v", resp.StatusCode, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "Empty01", value: "", wantMin: 0, wantMax: 0},
+		{name: "Seconds01", value: "120", wantMin: 2 * time.Minute, wantMax: 2 * time.Minute},
+		{name: "Date01", value: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), wantMin: 58 * time.Second, wantMax: time.Minute},
+		{name: "Garbage01", value: "soon", wantMin: 0, wantMax: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("parseRetryAfter(%q) = %v, want between %v and %v", tt.value, got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func Test_LimiterWait(t *testing.T) {
+	// 600 requests per minute is one every 100ms, the burst of 2 goes through immediately
+	limiter := NewLimiter(600, 2)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Limiter.Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Limiter.Wait() let 3 requests through in %v, want at least 80ms", elapsed)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Limiter.Wait() error = %v, want %v", err, context.Canceled)
+	}
+	if err := NewLimiter(0, 0).Wait(ctx); err != nil {
+		t.Errorf("nil Limiter.Wait() error = %v, want nil", err)
+	}
+}
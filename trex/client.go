@@ -0,0 +1,113 @@
+// Package trex is a client for the HTTP API T-Rex serves on --api-bind-http, reporting the stats of the rig it runs on
+package trex
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPort is the port the HTTP API listens on unless --api-bind-http sets another one
+	DefaultPort = "4067"
+	// DefaultTimeout is the per-call timeout a Client applies when WithTimeout is not given
+	DefaultTimeout = 5 * time.Second
+)
+
+// HTTPClient is an interface to abstract http.client to support testing using mocks
+type HTTPClient interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+var (
+	apiClient = HTTPClient(&http.Client{})
+)
+
+// Client talks to the API of a single T-Rex instance
+type Client struct {
+	baseURL    string
+	httpClient HTTPClient
+	timeout    time.Duration
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithHTTPClient sets the transport used for every request
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout applied to each call, zero disables it and leaves only the caller's context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// NewClient returns a Client for the API listening on address, a host:port, adjusted by options
+func NewClient(address string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    "http://" + address + "/",
+		httpClient: apiClient,
+		timeout:    DefaultTimeout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// BaseURL returns the URL every endpoint path is appended to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// GetSummary calls the summary endpoint and forms the response in to a usable Struct
+func (c *Client) GetSummary(ctx context.Context) (summary Summary, err error) {
+	err = c.get(ctx, "summary", &summary)
+	return
+}
+
+// get makes a single attempt at endpoint within the per-call timeout, rigs are on the local network so failures are
+// not retried
+func (c *Client) get(ctx context.Context, endpoint string, output interface{}) (err error) {
+	fullPath := c.baseURL + endpoint
+	log.Debugf("Client.get(fullPath=%s, output interface{}) called\n", fullPath)
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath, nil)
+	if err != nil {
+		log.Errorf("Client.get: http.NewRequestWithContext(ctx, GET, %s, nil); returned err=%s\n", fullPath, err.Error())
+		return
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Errorf("Client.get: c.httpClient.Do(%s); returned err=%s\n", fullPath, err.Error())
+		return &APIError{Endpoint: endpoint, Message: err.Error(), Err: ErrUnreachable}
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("Client.get: ioutil.ReadAll(resp.Body); returned err=%s\n", err.Error())
+		return &APIError{Endpoint: endpoint, StatusCode: resp.StatusCode, Message: err.Error(), Err: ErrUnreachable}
+	}
+	if err = checkResponse(endpoint, resp.StatusCode, body); err != nil {
+		log.Errorf("Client.get: checkResponse(%s, %d, body); returned err=%s\n", endpoint, resp.StatusCode, err.Error())
+		return
+	}
+	if err = json.Unmarshal(body, output); err != nil {
+		log.Errorf("Client.get: json.Unmarshal(body, output); returned err=%s\n", err.Error())
+		err = &APIError{Endpoint: endpoint, StatusCode: resp.StatusCode, Message: err.Error(), Err: ErrMalformedResponse}
+	}
+	return
+}
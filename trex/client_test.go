@@ -0,0 +1,109 @@
+package trex
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"mining-tools/rig"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAPIClient struct {
+	mock.Mock
+}
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func (mac *mockAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	args := mac.Called(req.URL.String())
+	resp = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(args.String(0))),
+	}
+	return resp, args.Error(1)
+}
+
+type cannedAPIClient struct {
+	statusCode int
+	body       string
+	err        error
+}
+
+func (cac *cannedAPIClient) Do(req *http.Request) (resp *http.Response, err error) {
+	if cac.err != nil {
+		return nil, cac.err
+	}
+	resp = &http.Response{
+		StatusCode: cac.statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(cac.body)),
+	}
+	return
+}
+
+func Test_getErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *cannedAPIClient
+		wantErr error
+	}{
+		{
+			name:    "Success01",
+			client:  &cannedAPIClient{statusCode: http.StatusOK, body: summaryRig3},
+			wantErr: nil,
+		},
+		{
+			name:    "Unreachable01",
+			client:  &cannedAPIClient{err: errors.New("connect: connection refused")},
+			wantErr: rig.ErrUnreachable,
+		},
+		{
+			name:    "Unauthorized01",
+			client:  &cannedAPIClient{statusCode: http.StatusUnauthorized, body: `{"error":"unauthorized"}`},
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "Malformed01",
+			client:  &cannedAPIClient{statusCode: http.StatusOK, body: `<html>T-Rex</html>`},
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "RequestFailed01",
+			client:  &cannedAPIClient{statusCode: http.StatusNotFound, body: `not found`},
+			wantErr: ErrRequestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient("127.0.0.1:4067", WithHTTPClient(tt.client)).GetSummary(context.Background())
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Client.GetSummary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.client.statusCode) {
+				t.Errorf("Client.GetSummary() error = %#v, want *APIError with StatusCode %d", err, tt.client.statusCode)
+			}
+		})
+	}
+}
+
+func Test_GetSummary(t *testing.T) {
+	mockClient := &mockAPIClient{}
+	mockClient.On("Do", "http://10.0.0.3:4067/summary").Return(summaryRig3, nil)
+	summary, err := NewClient("10.0.0.3:4067", WithHTTPClient(mockClient)).GetSummary(context.Background())
+	if err != nil {
+		t.Fatalf("Client.GetSummary() error = %v", err)
+	}
+	want := SummaryGPU{DeviceID: 1, GPUID: 1, Name: "RTX 3060 Ti", Vendor: "EVGA", Hashrate: 28800000, Power: 120,
+		Temperature: 65, MemoryTemperature: 84, FanSpeed: 60, Efficiency: "240kH/W", Shares: SummaryGPUShares{AcceptedCount: 90, InvalidCount: 1}}
+	if len(summary.GPUs) != 2 || summary.GPUs[1] != want || summary.ActivePool.Worker != "rig3" {
+		t.Errorf("Client.GetSummary() = %+v, want GPU 1 %+v and worker rig3", summary, want)
+	}
+}
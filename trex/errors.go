@@ -0,0 +1,61 @@
+package trex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"mining-tools/rig"
+)
+
+var (
+	// ErrUnreachable is returned when the request could not be sent or its response read, usually because the rig is down
+	ErrUnreachable = errors.New("unreachable")
+	// ErrUnauthorized is returned when T-Rex requires an API password
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrMalformedResponse is returned when the response body could not be decoded
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrRequestFailed is returned for any other non-200 status
+	ErrRequestFailed = errors.New("request failed")
+)
+
+// APIError describes a failed call to a T-Rex endpoint, Err is one of the Err* sentinels above so callers can branch
+// with errors.Is
+type APIError struct {
+	Endpoint   string
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("t-rex %s: %s (HTTP %d)", e.Endpoint, e.Err, e.StatusCode)
+	}
+	return fmt.Sprintf("t-rex %s: %s (HTTP %d): %s", e.Endpoint, e.Err, e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error describing the kind of failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets callers match the rig package sentinels, so code written against rig.Rig can branch on T-Rex failures
+func (e *APIError) Is(target error) bool {
+	return target == rig.ErrUnreachable && e.Err == ErrUnreachable
+}
+
+// checkResponse turns an HTTP status in to an *APIError, or nil if the call succeeded
+func checkResponse(endpoint string, statusCode int, body []byte) (err error) {
+	apiErr := &APIError{Endpoint: endpoint, StatusCode: statusCode}
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		apiErr.Err = ErrUnauthorized
+	case statusCode < 200 || statusCode >= 300:
+		apiErr.Message = string(body)
+		apiErr.Err = ErrRequestFailed
+	default:
+		return nil
+	}
+	return apiErr
+}
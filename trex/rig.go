@@ -0,0 +1,55 @@
+package trex
+
+import (
+	"context"
+	"time"
+
+	"mining-tools/rig"
+)
+
+// Rig adapts a Client to the rig.Rig interface
+type Rig struct {
+	client *Client
+}
+
+// NewRig returns a rig.Rig backed by client
+func NewRig(client *Client) *Rig {
+	return &Rig{client: client}
+}
+
+// Client returns the T-Rex Client behind the Rig
+func (r *Rig) Client() *Client {
+	return r.client
+}
+
+// Stats returns the hashrate, power, shares and sensor readings of the rig and each of its GPUs from the summary
+// endpoint, along with the worker name T-Rex mines as
+func (r *Rig) Stats(ctx context.Context) (stats rig.Stats, err error) {
+	summary, err := r.client.GetSummary(ctx)
+	if err != nil {
+		return
+	}
+	stats.Miner = "t-rex-" + summary.Version
+	stats.Worker = summary.ActivePool.Worker
+	stats.Uptime = time.Duration(summary.Uptime) * time.Second
+	stats.Hashrate = summary.Hashrate
+	stats.Shares = rig.Shares{Accepted: summary.AcceptedCount, Rejected: summary.RejectedCount, Invalid: summary.InvalidCount}
+	for _, gpu := range summary.GPUs {
+		stats.Power += gpu.Power
+		stats.GPUs = append(stats.GPUs, rig.GPU{
+			Index:             gpu.DeviceID,
+			Name:              gpu.Name,
+			Hashrate:          gpu.Hashrate,
+			Temperature:       gpu.Temperature,
+			MemoryTemperature: gpu.MemoryTemperature,
+			FanSpeed:          gpu.FanSpeed,
+			Power:             gpu.Power,
+			Shares: rig.Shares{
+				Accepted: gpu.Shares.AcceptedCount,
+				Rejected: gpu.Shares.RejectedCount,
+				Invalid:  gpu.Shares.InvalidCount,
+			},
+		})
+	}
+	return
+}
@@ -0,0 +1,34 @@
+package trex
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"mining-tools/rig"
+)
+
+func Test_Rig(t *testing.T) {
+	mockClient := &mockAPIClient{}
+	mockClient.On("Do", "http://10.0.0.3:4067/summary").Return(summaryRig3, nil)
+	stats, err := NewRig(NewClient("10.0.0.3:4067", WithHTTPClient(mockClient))).Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Rig.Stats() error = %v", err)
+	}
+	want := rig.Stats{
+		Miner:    "t-rex-0.24.8",
+		Worker:   "rig3",
+		Uptime:   time.Hour,
+		Hashrate: 58800000,
+		Power:    240,
+		Shares:   rig.Shares{Accepted: 185, Rejected: 1, Invalid: 1},
+		GPUs: []rig.GPU{
+			{Index: 0, Name: "RTX 3060 Ti", Hashrate: 30000000, Temperature: 61, MemoryTemperature: 80, FanSpeed: 55, Power: 120, Shares: rig.Shares{Accepted: 95, Rejected: 1}},
+			{Index: 1, Name: "RTX 3060 Ti", Hashrate: 28800000, Temperature: 65, MemoryTemperature: 84, FanSpeed: 60, Power: 120, Shares: rig.Shares{Accepted: 90, Invalid: 1}},
+		},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("Rig.Stats() = %+v, want %+v", stats, want)
+	}
+}
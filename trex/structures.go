@@ -0,0 +1,50 @@
+package trex
+
+// Summary is for decoding json from a successful response of the T-Rex summary endpoint, hashrates are in H/s,
+// power in W, temperatures in °C and Uptime in seconds
+type Summary struct {
+	Name          string       `json:"name"`
+	Version       string       `json:"version"`
+	Algorithm     string       `json:"algorithm"`
+	Uptime        int64        `json:"uptime"`
+	Hashrate      float64      `json:"hashrate"`
+	HashrateDay   float64      `json:"hashrate_day"`
+	AcceptedCount int64        `json:"accepted_count"`
+	RejectedCount int64        `json:"rejected_count"`
+	InvalidCount  int64        `json:"invalid_count"`
+	GPUTotal      int          `json:"gpu_total"`
+	GPUs          []SummaryGPU `json:"gpus"`
+	ActivePool    SummaryPool  `json:"active_pool"`
+}
+
+// SummaryGPU is for decoding json from a successful response of the T-Rex summary endpoint
+type SummaryGPU struct {
+	DeviceID          int              `json:"device_id"`
+	GPUID             int              `json:"gpu_id"`
+	Name              string           `json:"name"`
+	Vendor            string           `json:"vendor"`
+	Hashrate          float64          `json:"hashrate"`
+	Power             float64          `json:"power"`
+	Temperature       float64          `json:"temperature"`
+	MemoryTemperature float64          `json:"memory_temperature"`
+	FanSpeed          float64          `json:"fan_speed"`
+	Efficiency        string           `json:"efficiency"`
+	Shares            SummaryGPUShares `json:"shares"`
+}
+
+// SummaryGPUShares is for decoding json from a successful response of the T-Rex summary endpoint
+type SummaryGPUShares struct {
+	AcceptedCount int64 `json:"accepted_count"`
+	RejectedCount int64 `json:"rejected_count"`
+	InvalidCount  int64 `json:"invalid_count"`
+}
+
+// SummaryPool is for decoding json from a successful response of the T-Rex summary endpoint, Worker is the worker
+// name T-Rex sends to the pool
+type SummaryPool struct {
+	URL     string `json:"url"`
+	User    string `json:"user"`
+	Worker  string `json:"worker"`
+	Ping    int64  `json:"ping"`
+	Retries int64  `json:"retries"`
+}
@@ -0,0 +1,10 @@
+package trex
+
+// summaryRig3 is a summary response of T-Rex 0.24.8 mining ETH on two GPUs
+const summaryRig3 = `{"accepted_count":185,"active_pool":{"ping":32,"retries":0,"url":"stratum+tcp://eth-eu1.nanopool.org:9999",` +
+	`"user":"0x01","worker":"rig3"},"algorithm":"ethash","api":"3.6","gpu_total":2,"gpus":[` +
+	`{"device_id":0,"efficiency":"250kH/W","fan_speed":55,"gpu_id":0,"hashrate":30000000,"memory_temperature":80,` +
+	`"name":"RTX 3060 Ti","power":120,"shares":{"accepted_count":95,"invalid_count":0,"rejected_count":1},"temperature":61,"vendor":"Gigabyte"},` +
+	`{"device_id":1,"efficiency":"240kH/W","fan_speed":60,"gpu_id":1,"hashrate":28800000,"memory_temperature":84,` +
+	`"name":"RTX 3060 Ti","power":120,"shares":{"accepted_count":90,"invalid_count":1,"rejected_count":0},"temperature":65,"vendor":"EVGA"}],` +
+	`"hashrate":58800000,"hashrate_day":58500000,"invalid_count":1,"name":"t-rex","rejected_count":1,"uptime":3600,"version":"0.24.8"}`
@@ -0,0 +1,57 @@
+// Package wei converts between wei, gwei and ether exactly using math/big, so balances never pass through a float64
+package wei
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	// PerGwei is the number of wei in one gwei
+	PerGwei = big.NewInt(1000000000)
+	// PerEther is the number of wei in one ether
+	PerEther = big.NewInt(1000000000000000000)
+)
+
+// Parse parses a base 10 count of wei, such as the result of an Etherscan account balance call
+func Parse(s string) (w *big.Int, err error) {
+	w, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid wei amount %q", s)
+	}
+	return
+}
+
+// ToEther returns the exact value of w in ether
+func ToEther(w *big.Int) *big.Rat {
+	return new(big.Rat).SetFrac(w, PerEther)
+}
+
+// ToGwei returns the exact value of w in gwei
+func ToGwei(w *big.Int) *big.Rat {
+	return new(big.Rat).SetFrac(w, PerGwei)
+}
+
+// FormatEther renders w in ether with every significant digit and no trailing zeros, e.g. 1.5 or 0.000000000000000001
+func FormatEther(w *big.Int) string {
+	return FormatRat(ToEther(w), 18)
+}
+
+// FormatGwei renders w in gwei with every significant digit and no trailing zeros
+func FormatGwei(w *big.Int) string {
+	return FormatRat(ToGwei(w), 9)
+}
+
+// FormatRat renders r as a decimal rounded to at most decimals places, without trailing zeros or a trailing point
+func FormatRat(r *big.Rat, decimals int) string {
+	s := r.FloatString(decimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
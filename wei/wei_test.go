@@ -0,0 +1,63 @@
+package wei
+
+import (
+	"math/big"
+	"testing"
+)
+
+func Test_FormatEther(t *testing.T) {
+	tests := []struct {
+		name      string
+		wei       string
+		wantEther string
+		wantGwei  string
+		wantErr   bool
+	}{
+		{name: "Zero01", wei: "0", wantEther: "0", wantGwei: "0"},
+		{name: "OneWei01", wei: "1", wantEther: "0.000000000000000001", wantGwei: "0.000000001"},
+		{name: "OneEther01", wei: "1000000000000000000", wantEther: "1", wantGwei: "1000000000"},
+		{name: "Large01", wei: "123456789012345678901234567", wantEther: "123456789.012345678901234567", wantGwei: "123456789012345678.901234567"},
+		{name: "Balance01", wei: "142000000000000001", wantEther: "0.142000000000000001", wantGwei: "142000000.000000001"},
+		{name: "Invalid01", wei: "1.5", wantErr: true},
+		{name: "Invalid02", wei: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := Parse(tt.wei)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%q) error = %v, wantErr %v", tt.wei, err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := FormatEther(w); got != tt.wantEther {
+				t.Errorf("FormatEther(%s) = %v, want %v", tt.wei, got, tt.wantEther)
+			}
+			if got := FormatGwei(w); got != tt.wantGwei {
+				t.Errorf("FormatGwei(%s) = %v, want %v", tt.wei, got, tt.wantGwei)
+			}
+		})
+	}
+}
+
+func Test_FormatRat(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        *big.Rat
+		decimals int
+		want     string
+	}{
+		{name: "Whole01", r: big.NewRat(730, 1), decimals: 12, want: "730"},
+		{name: "Fraction01", r: big.NewRat(7305, 10), decimals: 12, want: "730.5"},
+		{name: "Rounded01", r: big.NewRat(1, 3), decimals: 4, want: "0.3333"},
+		{name: "NegativeZero01", r: big.NewRat(-1, 1000000), decimals: 2, want: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatRat(tt.r, tt.decimals); got != tt.want {
+				t.Errorf("FormatRat(%s, %d) = %v, want %v", tt.r, tt.decimals, got, tt.want)
+			}
+		})
+	}
+}